@@ -0,0 +1,46 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AccessDeniedError is returned when the spooler denies an operation on a
+// printer or job. Besides the access the operation needed, it tells who
+// the process runs as and, where there is one, an operation that would
+// succeed instead. It wraps the spooler error, so errors.Is(err,
+// os.ErrPermission) holds.
+type AccessDeniedError struct {
+	Op       string // such as "OpenAdmin" or "CancelJob"
+	Printer  string
+	Access   string // the access requested, such as "Manage Printers"
+	User     string // the account the process runs as, empty if unknown
+	Elevated bool   // whether the process runs with administrator rights
+	Hint     string // what would succeed instead, if known
+	Err      error
+}
+
+func (e *AccessDeniedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "printer: %s %q: access denied: needs %s access", e.Op, e.Printer, e.Access)
+	if e.User != "" {
+		elevated := "not elevated"
+		if e.Elevated {
+			elevated = "elevated"
+		}
+		fmt.Fprintf(&b, "; process runs as %s, %s", e.User, elevated)
+	}
+	if e.Hint != "" {
+		b.WriteString("; ")
+		b.WriteString(e.Hint)
+	}
+	return b.String()
+}
+
+func (e *AccessDeniedError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,35 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestAccessDeniedError(t *testing.T) {
+	e := &AccessDeniedError{
+		Op:      "OpenAdmin",
+		Printer: "Kitchen",
+		Access:  "Manage Printers",
+		User:    `SHOP\till`,
+		Hint:    "Open still prints",
+		Err:     fmt.Errorf("denied: %w", os.ErrPermission),
+	}
+	want := `printer: OpenAdmin "Kitchen": access denied: needs Manage Printers access; process runs as SHOP\till, not elevated; Open still prints`
+	if got := e.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(e, os.ErrPermission) {
+		t.Error("AccessDeniedError does not wrap os.ErrPermission")
+	}
+	e.User, e.Hint = "", ""
+	want = `printer: OpenAdmin "Kitchen": access denied: needs Manage Printers access`
+	if got := e.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
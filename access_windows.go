@@ -0,0 +1,45 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// accessDenied returns err as an *AccessDeniedError if the spooler denied
+// op on printer, and err unchanged otherwise. access is what op needed,
+// hint what would succeed instead.
+func accessDenied(op, printer, access, hint string, err error) error {
+	if !errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+		return err
+	}
+	e := &AccessDeniedError{
+		Op:      op,
+		Printer: printer,
+		Access:  access,
+		Hint:    hint,
+		Err:     err,
+	}
+	token := windows.GetCurrentProcessToken()
+	e.Elevated = token.IsElevated()
+	if tu, err := token.GetTokenUser(); err == nil {
+		if account, domain, _, err := tu.User.Sid.LookupAccount(""); err == nil {
+			e.User = account
+			if domain != "" {
+				e.User = domain + `\` + account
+			}
+		}
+	}
+	return e
+}
+
+// jobAccessDenied is accessDenied for the job control operations.
+func (p *Printer) jobAccessDenied(op string, id uint32, err error) error {
+	hint := fmt.Sprintf("only the owner of job %d or a user with the Manage Documents permission on the queue may control it; the process can still control the jobs it submitted", id)
+	return accessDenied(op, p.name, "Manage Documents", hint, err)
+}
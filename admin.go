@@ -0,0 +1,43 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+// Info holds the settings of a printer queue.
+type Info struct {
+	Name            string
+	ServerName      string
+	ShareName       string
+	PortName        string
+	DriverName      string
+	Comment         string
+	Location        string
+	SepFile         string
+	PrintProcessor  string
+	Datatype        string
+	Parameters      string
+	Attributes      Attributes
+	Priority        uint32
+	DefaultPriority uint32
+	// StartTime and UntilTime are the minutes after midnight UTC between
+	// which the queue prints; they are equal if the queue is always
+	// available.
+	StartTime uint32
+	UntilTime uint32
+	Status    uint32
+	Jobs      uint32
+}
+
+// SpoolMode tells how a queue hands jobs to the printer.
+type SpoolMode int
+
+const (
+	// SpoolImmediate spools jobs and starts printing with the first page.
+	SpoolImmediate SpoolMode = iota
+	// SpoolComplete spools jobs and starts printing once the last page is
+	// spooled.
+	SpoolComplete
+	// SpoolDirect sends jobs directly to the printer without spooling.
+	SpoolDirect
+)
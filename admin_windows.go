@@ -0,0 +1,306 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	PRINTER_ACCESS_ADMINISTER = 0x00000004
+	PRINTER_ACCESS_USE        = 0x00000008
+	PRINTER_ALL_ACCESS        = 0x000F000C
+
+	ERROR_INVALID_SHARENAME = syscall.Errno(1215)
+)
+
+//sys	SetPrinter(h syscall.Handle, level uint32, buf *byte, command uint32) (err error) = winspool.SetPrinterW
+
+// OpenAdmin opens the printer name with administrative access, as needed
+// to change the queue settings with methods such as Rename. It usually
+// requires administrator rights.
+func OpenAdmin(name string) (*Printer, error) {
+	p := Printer{name: name, access: PRINTER_ALL_ACCESS}
+	d := PRINTER_DEFAULTS{
+		DesiredAccess: PRINTER_ALL_ACCESS,
+	}
+	u, err := newUTF16(name)
+	if err != nil {
+		return nil, err
+	}
+	defer u.free()
+	err = OpenPrinter(u.ptr(), &p.h, &d)
+	if err != nil {
+		return nil, accessDenied("OpenAdmin", name, "Manage Printers", "run the process elevated or as a user with the Manage Printers permission on the queue; Open, which needs Print access only, still prints and reads the queue", err)
+	}
+	p.t = &spoolTransport{h: p.h}
+	return &p, nil
+}
+
+// updateInfo2 reads the PRINTER_INFO_2 of the queue, lets f modify it and
+// writes it back. The security descriptor and the default DEVMODE are left
+// unchanged. p must have been opened with OpenAdmin. The GC does not see
+// the pointers f stores in the PRINTER_INFO_2, so callers keep what they
+// point to alive until updateInfo2 returns, see updateInfo2String.
+func (p *Printer) updateInfo2(f func(pi *PRINTER_INFO_2)) error {
+	pi, err := p.info2()
+	if err != nil {
+		return err
+	}
+	pi.SecurityDescriptor = 0
+	pi.DevMode = nil
+	f(pi)
+	return SetPrinter(p.h, 2, (*byte)(unsafe.Pointer(pi)), 0)
+}
+
+// updateInfo2String is updateInfo2 for the settings holding a string: f
+// stores str, converted to UTF-16, in the PRINTER_INFO_2, and the
+// conversion is kept alive until the PRINTER_INFO_2 is written.
+func (p *Printer) updateInfo2String(str string, f func(pi *PRINTER_INFO_2, s *uint16)) error {
+	s, err := windows.UTF16PtrFromString(str)
+	if err != nil {
+		return err
+	}
+	err = p.updateInfo2(func(pi *PRINTER_INFO_2) {
+		f(pi, s)
+	})
+	runtime.KeepAlive(s)
+	return err
+}
+
+// Rename changes the name of the printer queue to newName. p must have
+// been opened with OpenAdmin.
+func (p *Printer) Rename(newName string) error {
+	err := p.updateInfo2String(newName, func(pi *PRINTER_INFO_2, s *uint16) {
+		pi.PrinterName = s
+	})
+	if err != nil {
+		return err
+	}
+	p.name = newName
+	return nil
+}
+
+// Info returns the settings of the printer queue.
+func (p *Printer) Info() (*Info, error) {
+	pi, err := p.info2()
+	if err != nil {
+		return nil, err
+	}
+	return newInfo(pi), nil
+}
+
+// Attributes returns the attributes of the printer queue.
+func (p *Printer) Attributes() (Attributes, error) {
+	pi, err := p.info2()
+	if err != nil {
+		return 0, err
+	}
+	return Attributes(pi.Attributes), nil
+}
+
+func newInfo(pi *PRINTER_INFO_2) *Info {
+	return &Info{
+		Name:            windows.UTF16PtrToString(pi.PrinterName),
+		ServerName:      windows.UTF16PtrToString(pi.ServerName),
+		ShareName:       windows.UTF16PtrToString(pi.ShareName),
+		PortName:        windows.UTF16PtrToString(pi.PortName),
+		DriverName:      windows.UTF16PtrToString(pi.DriverName),
+		Comment:         windows.UTF16PtrToString(pi.Comment),
+		Location:        windows.UTF16PtrToString(pi.Location),
+		SepFile:         windows.UTF16PtrToString(pi.SepFile),
+		PrintProcessor:  windows.UTF16PtrToString(pi.PrintProcessor),
+		Datatype:        windows.UTF16PtrToString(pi.Datatype),
+		Parameters:      windows.UTF16PtrToString(pi.Parameters),
+		Attributes:      Attributes(pi.Attributes),
+		Priority:        pi.Priority,
+		DefaultPriority: pi.DefaultPriority,
+		StartTime:       pi.StartTime,
+		UntilTime:       pi.UntilTime,
+		Status:          pi.Status,
+		Jobs:            pi.Jobs,
+	}
+}
+
+// SetComment changes the comment of the printer queue. p must have been
+// opened with OpenAdmin.
+func (p *Printer) SetComment(comment string) error {
+	return p.updateInfo2String(comment, func(pi *PRINTER_INFO_2, s *uint16) {
+		pi.Comment = s
+	})
+}
+
+// SetLocation changes the location of the printer queue. p must have been
+// opened with OpenAdmin.
+func (p *Printer) SetLocation(location string) error {
+	return p.updateInfo2String(location, func(pi *PRINTER_INFO_2, s *uint16) {
+		pi.Location = s
+	})
+}
+
+// SetShareName changes the name the printer queue is shared as. It does
+// not share the queue; see SetShared. p must have been opened with
+// OpenAdmin.
+func (p *Printer) SetShareName(shareName string) error {
+	return p.updateInfo2String(shareName, func(pi *PRINTER_INFO_2, s *uint16) {
+		pi.ShareName = s
+	})
+}
+
+// SetShared shares or stops sharing the printer queue on the network,
+// under its current share name. p must have been opened with OpenAdmin.
+func (p *Printer) SetShared(shared bool) error {
+	return p.setAttribute(PRINTER_ATTRIBUTE_SHARED, shared)
+}
+
+// Share shares the printer queue on the network as shareName, or under
+// its own name if shareName is empty, turning the machine into a print
+// server. Sharing needs the queue to be opened with OpenAdmin by an
+// administrator, and file and printer sharing to be enabled.
+func (p *Printer) Share(shareName string) error {
+	if shareName == "" {
+		shareName = p.name
+	}
+	return shareError(p.updateInfo2String(shareName, func(pi *PRINTER_INFO_2, s *uint16) {
+		pi.ShareName = s
+		pi.Attributes |= PRINTER_ATTRIBUTE_SHARED
+	}))
+}
+
+// Unshare stops sharing the printer queue. p must have been opened with
+// OpenAdmin.
+func (p *Printer) Unshare() error {
+	return shareError(p.SetShared(false))
+}
+
+// shareError explains the errors commonly returned when changing the
+// sharing of a queue.
+func shareError(err error) error {
+	switch err {
+	case syscall.ERROR_ACCESS_DENIED:
+		return fmt.Errorf("printer: changing sharing needs a queue opened with OpenAdmin by an administrator: %v", err)
+	case ERROR_INVALID_SHARENAME:
+		return fmt.Errorf("printer: invalid or duplicate share name: %v", err)
+	}
+	return err
+}
+
+// Priorities of printer queues and jobs.
+const (
+	MIN_PRIORITY = 1
+	MAX_PRIORITY = 99
+	DEF_PRIORITY = 1
+)
+
+// SetPriority sets the priority of the printer queue, between MIN_PRIORITY
+// and MAX_PRIORITY. When several queues print to the same port, jobs of
+// the queue with the highest priority print first. p must have been opened
+// with OpenAdmin.
+func (p *Printer) SetPriority(priority uint32) error {
+	if priority < MIN_PRIORITY || priority > MAX_PRIORITY {
+		return fmt.Errorf("printer: invalid priority: %d", priority)
+	}
+	return p.updateInfo2(func(pi *PRINTER_INFO_2) {
+		pi.Priority = priority
+	})
+}
+
+// SetDefaultPriority sets the priority given to new jobs of the printer
+// queue. p must have been opened with OpenAdmin.
+func (p *Printer) SetDefaultPriority(priority uint32) error {
+	if priority < MIN_PRIORITY || priority > MAX_PRIORITY {
+		return fmt.Errorf("printer: invalid priority: %d", priority)
+	}
+	return p.updateInfo2(func(pi *PRINTER_INFO_2) {
+		pi.DefaultPriority = priority
+	})
+}
+
+// SetAvailability restricts printing to the time between start and until,
+// both measured from midnight UTC; jobs sent outside of it wait in the
+// queue. until may be less than start for a window spanning midnight.
+// Equal values make the queue always available. p must have been opened
+// with OpenAdmin.
+func (p *Printer) SetAvailability(start, until time.Duration) error {
+	const day = 24 * time.Hour
+	if start < 0 || start >= day || until < 0 || until >= day {
+		return fmt.Errorf("printer: invalid availability %v-%v", start, until)
+	}
+	return p.updateInfo2(func(pi *PRINTER_INFO_2) {
+		pi.StartTime = uint32(start / time.Minute)
+		pi.UntilTime = uint32(until / time.Minute)
+	})
+}
+
+// SeparatorPage returns the path of the separator page file printed
+// before each job, or "" if there is none.
+func (p *Printer) SeparatorPage() (string, error) {
+	pi, err := p.info2()
+	if err != nil {
+		return "", err
+	}
+	return windows.UTF16PtrToString(pi.SepFile), nil
+}
+
+// SetSeparatorPage sets the separator page file printed before each job,
+// such as `C:\Windows\System32\sysprint.sep`. An empty path removes the
+// separator page. p must have been opened with OpenAdmin.
+func (p *Printer) SetSeparatorPage(path string) error {
+	return p.updateInfo2String(path, func(pi *PRINTER_INFO_2, s *uint16) {
+		pi.SepFile = s
+	})
+}
+
+// SetSpoolMode sets how the printer queue hands jobs to the printer. p
+// must have been opened with OpenAdmin.
+func (p *Printer) SetSpoolMode(mode SpoolMode) error {
+	return p.updateInfo2(func(pi *PRINTER_INFO_2) {
+		pi.Attributes &^= PRINTER_ATTRIBUTE_QUEUED | PRINTER_ATTRIBUTE_DIRECT
+		switch mode {
+		case SpoolComplete:
+			pi.Attributes |= PRINTER_ATTRIBUTE_QUEUED
+		case SpoolDirect:
+			pi.Attributes |= PRINTER_ATTRIBUTE_DIRECT
+		}
+	})
+}
+
+// SetKeepPrintedJobs sets whether jobs stay in the queue after they have
+// printed, so they can be printed again. p must have been opened with
+// OpenAdmin.
+func (p *Printer) SetKeepPrintedJobs(keep bool) error {
+	return p.setAttribute(PRINTER_ATTRIBUTE_KEEPPRINTEDJOBS, keep)
+}
+
+// SetEnableDevQ sets whether jobs not matching the printer setup, such as
+// jobs for a paper size the printer does not have, are held in the queue
+// instead of being printed. p must have been opened with OpenAdmin.
+func (p *Printer) SetEnableDevQ(enable bool) error {
+	return p.setAttribute(PRINTER_ATTRIBUTE_ENABLE_DEVQ, enable)
+}
+
+// SetDoCompleteFirst sets whether completely spooled jobs print before
+// jobs still spooling, regardless of their priority. p must have been
+// opened with OpenAdmin.
+func (p *Printer) SetDoCompleteFirst(enable bool) error {
+	return p.setAttribute(PRINTER_ATTRIBUTE_DO_COMPLETE_FIRST, enable)
+}
+
+// setAttribute sets or clears the attribute a of the printer queue.
+func (p *Printer) setAttribute(a uint32, on bool) error {
+	return p.updateInfo2(func(pi *PRINTER_INFO_2) {
+		if on {
+			pi.Attributes |= a
+		} else {
+			pi.Attributes &^= a
+		}
+	})
+}
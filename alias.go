@@ -0,0 +1,111 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Aliases maps logical printer roles, such as "receipt", "kitchen" or
+// "labels", to the names of printers, so applications do not hardcode the
+// names of the queues of a particular installation. Roles are not case
+// sensitive. It is safe for concurrent use.
+type Aliases struct {
+	mu sync.RWMutex
+	m  map[string]string
+}
+
+// NewAliases returns an empty set of aliases.
+func NewAliases() *Aliases {
+	return &Aliases{m: make(map[string]string)}
+}
+
+// LoadAliases reads aliases from the JSON file path, which holds an object
+// mapping roles to printer names:
+//
+//	{"receipt": "EPSON TM-T20II Receipt", "labels": "ZDesigner GK420d"}
+func LoadAliases(path string) (*Aliases, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	a := NewAliases()
+	if err := a.ReadJSON(f); err != nil {
+		return nil, fmt.Errorf("printer: %s: %v", path, err)
+	}
+	return a, nil
+}
+
+// ReadJSON adds the aliases of the JSON object read from r, replacing
+// existing ones for the same roles.
+func (a *Aliases) ReadJSON(r io.Reader) error {
+	var m map[string]string
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return err
+	}
+	for role, name := range m {
+		a.Register(role, name)
+	}
+	return nil
+}
+
+// WriteJSON writes the aliases to w as a JSON object.
+func (a *Aliases) WriteJSON(w io.Writer) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(a.m)
+}
+
+// Register maps role to the printer name.
+func (a *Aliases) Register(role, name string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.m[strings.ToLower(role)] = name
+}
+
+// Unregister removes role.
+func (a *Aliases) Unregister(role string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.m, strings.ToLower(role))
+}
+
+// Lookup returns the printer name registered for role.
+func (a *Aliases) Lookup(role string) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	name, ok := a.m[strings.ToLower(role)]
+	return name, ok
+}
+
+// Roles returns the registered roles in sorted order.
+func (a *Aliases) Roles() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	roles := make([]string, 0, len(a.m))
+	for role := range a.m {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Open opens the printer registered for role.
+func (a *Aliases) Open(role string) (*Printer, error) {
+	name, ok := a.Lookup(role)
+	if !ok {
+		return nil, fmt.Errorf("printer: no printer registered for role %q", role)
+	}
+	return Open(name)
+}
@@ -0,0 +1,30 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	a := NewAliases()
+	err := a.ReadJSON(strings.NewReader(`{"Receipt": "EPSON TM-T20", "labels": "ZDesigner"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := a.Lookup("receipt"); !ok || name != "EPSON TM-T20" {
+		t.Errorf("Lookup(receipt) = %q, %v", name, ok)
+	}
+	a.Register("kitchen", "Star TSP100")
+	a.Unregister("labels")
+	if got, want := a.Roles(), []string{"kitchen", "receipt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Roles() = %q, want %q", got, want)
+	}
+	if _, err := a.Open("labels"); err == nil {
+		t.Error("Open of unregistered role succeeded")
+	}
+}
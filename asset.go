@@ -0,0 +1,101 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SerialNumber returns the serial number of the printer, queried with
+// the profile's SerialQuery. It returns ErrUnsupported if the printer
+// does not report it.
+func (p *Printer) SerialNumber() (string, error) {
+	cmd := p.profile().SerialQuery
+	if cmd == nil {
+		cmd = []byte{gs, 'I', 68}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, err := p.queryInfo(cmd)
+	if err != nil {
+		return "", err
+	}
+	if s = strings.TrimSpace(s); s == "" {
+		return "", ErrUnsupported
+	}
+	return s, nil
+}
+
+// MACAddress returns the MAC address of the network interface of the
+// printer, queried with the profile's MACQuery. It returns
+// ErrUnsupported if the profile sets none or the printer does not
+// answer it.
+func (p *Printer) MACAddress() (net.HardwareAddr, error) {
+	cmd := p.profile().MACQuery
+	if cmd == nil {
+		return nil, ErrUnsupported
+	}
+	p.mu.Lock()
+	s, err := p.queryInfo(cmd)
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if s = strings.TrimSpace(s); s == "" {
+		return nil, ErrUnsupported
+	}
+	return parseMAC(s)
+}
+
+// parseMAC parses a MAC address such as "00:26:AB:01:02:03",
+// "00-26-AB-01-02-03" or "0026AB010203".
+func parseMAC(s string) (net.HardwareAddr, error) {
+	if len(s) == 12 && !strings.ContainsAny(s, ":-.") {
+		var b strings.Builder
+		for i := 0; i < len(s); i += 2 {
+			if i > 0 {
+				b.WriteByte(':')
+			}
+			b.WriteString(s[i : i+2])
+		}
+		s = b.String()
+	}
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, fmt.Errorf("printer: invalid MAC address %q", s)
+	}
+	return mac, nil
+}
+
+// Asset is the inventory record of a printer, see Printer.Asset.
+type Asset struct {
+	Name     string
+	Maker    string
+	Model    string
+	Firmware string
+	Serial   string           // empty if not reported
+	MAC      net.HardwareAddr // nil if not reported
+}
+
+// Asset queries the identity of the printer for asset inventories: its
+// model and firmware with GS I, its serial number and, for networked
+// printers whose profile sets MACQuery, its MAC address. Like
+// PrinterID, it needs a port that reads.
+func (p *Printer) Asset() (*Asset, error) {
+	id, err := p.PrinterID()
+	if err != nil {
+		return nil, err
+	}
+	a := &Asset{Name: p.name, Maker: id.Maker, Model: id.Model, Firmware: id.Firmware}
+	if a.Serial, err = p.SerialNumber(); err != nil && err != ErrUnsupported {
+		return nil, err
+	}
+	if a.MAC, err = p.MACAddress(); err != nil && err != ErrUnsupported {
+		return nil, err
+	}
+	return a, nil
+}
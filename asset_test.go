@@ -0,0 +1,45 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestParseMAC(t *testing.T) {
+	for _, s := range []string{"00:26:ab:01:02:03", "00-26-AB-01-02-03", "0026AB010203"} {
+		mac, err := parseMAC(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if got := mac.String(); got != "00:26:ab:01:02:03" {
+			t.Errorf("%s: got %s", s, got)
+		}
+	}
+	if _, err := parseMAC("printer"); err == nil {
+		t.Error("no error for an invalid address")
+	}
+}
+
+func TestAsset(t *testing.T) {
+	ft := &fakeTransport{status: []byte("\x20\x02\x41_EPSON\x00_TM-T20\x00_1.00\x00_X5E7012345\x00_0026AB010203\x00")}
+	p := NewPrinter("fake", ft)
+	p.Profile = &Profile{MACQuery: []byte("\x1DIM")}
+	a, err := p.Asset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Model != "TM-T20" || a.Serial != "X5E7012345" || a.MAC.String() != "00:26:ab:01:02:03" {
+		t.Errorf("got %+v", a)
+	}
+
+	// no serial number and no MAC query
+	ft = &fakeTransport{status: []byte("\x20\x02\x41\x20\x20\x20\x20")}
+	if a, err = NewPrinter("fake", ft).Asset(); err != nil {
+		t.Fatal(err)
+	}
+	if a.Serial != "" || a.MAC != nil {
+		t.Errorf("got %+v", a)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	PRINTER_ATTRIBUTE_QUEUED            = 0x00000001
+	PRINTER_ATTRIBUTE_DIRECT            = 0x00000002
+	PRINTER_ATTRIBUTE_DEFAULT           = 0x00000004
+	PRINTER_ATTRIBUTE_SHARED            = 0x00000008
+	PRINTER_ATTRIBUTE_NETWORK           = 0x00000010
+	PRINTER_ATTRIBUTE_HIDDEN            = 0x00000020
+	PRINTER_ATTRIBUTE_LOCAL             = 0x00000040
+	PRINTER_ATTRIBUTE_ENABLE_DEVQ       = 0x00000080
+	PRINTER_ATTRIBUTE_KEEPPRINTEDJOBS   = 0x00000100
+	PRINTER_ATTRIBUTE_DO_COMPLETE_FIRST = 0x00000200
+	PRINTER_ATTRIBUTE_WORK_OFFLINE      = 0x00000400
+	PRINTER_ATTRIBUTE_ENABLE_BIDI       = 0x00000800
+	PRINTER_ATTRIBUTE_RAW_ONLY          = 0x00001000
+	PRINTER_ATTRIBUTE_PUBLISHED         = 0x00002000
+	PRINTER_ATTRIBUTE_FAX               = 0x00004000
+	PRINTER_ATTRIBUTE_TS                = 0x00008000
+)
+
+// Attributes are the attributes of a printer queue, a combination of the
+// PRINTER_ATTRIBUTE_ constants.
+type Attributes uint32
+
+const (
+	AttrQueued          Attributes = PRINTER_ATTRIBUTE_QUEUED
+	AttrDirect          Attributes = PRINTER_ATTRIBUTE_DIRECT
+	AttrDefault         Attributes = PRINTER_ATTRIBUTE_DEFAULT
+	AttrShared          Attributes = PRINTER_ATTRIBUTE_SHARED
+	AttrNetwork         Attributes = PRINTER_ATTRIBUTE_NETWORK
+	AttrHidden          Attributes = PRINTER_ATTRIBUTE_HIDDEN
+	AttrLocal           Attributes = PRINTER_ATTRIBUTE_LOCAL
+	AttrEnableDevQ      Attributes = PRINTER_ATTRIBUTE_ENABLE_DEVQ
+	AttrKeepPrintedJobs Attributes = PRINTER_ATTRIBUTE_KEEPPRINTEDJOBS
+	AttrDoCompleteFirst Attributes = PRINTER_ATTRIBUTE_DO_COMPLETE_FIRST
+	AttrWorkOffline     Attributes = PRINTER_ATTRIBUTE_WORK_OFFLINE
+	AttrEnableBidi      Attributes = PRINTER_ATTRIBUTE_ENABLE_BIDI
+	AttrRawOnly         Attributes = PRINTER_ATTRIBUTE_RAW_ONLY
+	AttrPublished       Attributes = PRINTER_ATTRIBUTE_PUBLISHED
+	AttrFax             Attributes = PRINTER_ATTRIBUTE_FAX
+	AttrTS              Attributes = PRINTER_ATTRIBUTE_TS
+)
+
+var attributeNames = []struct {
+	a    Attributes
+	name string
+}{
+	{AttrQueued, "Queued"},
+	{AttrDirect, "Direct"},
+	{AttrDefault, "Default"},
+	{AttrShared, "Shared"},
+	{AttrNetwork, "Network"},
+	{AttrHidden, "Hidden"},
+	{AttrLocal, "Local"},
+	{AttrEnableDevQ, "EnableDevQ"},
+	{AttrKeepPrintedJobs, "KeepPrintedJobs"},
+	{AttrDoCompleteFirst, "DoCompleteFirst"},
+	{AttrWorkOffline, "WorkOffline"},
+	{AttrEnableBidi, "EnableBidi"},
+	{AttrRawOnly, "RawOnly"},
+	{AttrPublished, "Published"},
+	{AttrFax, "Fax"},
+	{AttrTS, "TS"},
+}
+
+// Has reports whether all attributes in b are set in a.
+func (a Attributes) Has(b Attributes) bool {
+	return a&b == b
+}
+
+// String returns the names of the attributes set in a separated by "|",
+// such as "Queued|Local|EnableBidi".
+func (a Attributes) String() string {
+	var names []string
+	for _, an := range attributeNames {
+		if a&an.a != 0 {
+			names = append(names, an.name)
+			a &^= an.a
+		}
+	}
+	if a != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(a), 16))
+	}
+	if len(names) == 0 {
+		return "0"
+	}
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,30 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestAttributesString(t *testing.T) {
+	tests := []struct {
+		a    Attributes
+		want string
+	}{
+		{0, "0"},
+		{AttrQueued, "Queued"},
+		{AttrQueued | AttrLocal | AttrEnableBidi, "Queued|Local|EnableBidi"},
+		{AttrShared | 0x100000, "Shared|0x100000"},
+	}
+	for _, test := range tests {
+		if got := test.a.String(); got != test.want {
+			t.Errorf("Attributes(%#x).String() = %q, want %q", uint32(test.a), got, test.want)
+		}
+	}
+	if !(AttrQueued | AttrLocal).Has(AttrLocal) {
+		t.Error("Has(AttrLocal) = false, want true")
+	}
+	if AttrLocal.Has(AttrLocal | AttrShared) {
+		t.Error("Has(AttrLocal|AttrShared) = true, want false")
+	}
+}
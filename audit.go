@@ -0,0 +1,178 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// AuditRecord records a document printed by a Printer with Audit set.
+type AuditRecord struct {
+	// Time is when the document started.
+	Time     time.Time
+	Duration time.Duration
+	User     string
+	Machine  string
+	Printer  string
+	Document string
+	// JobID is the spooler ID of the print job, 0 if the document did
+	// not go through the spooler or could not be started.
+	JobID uint32
+	// Bytes is the number of bytes sent to the printer, copies included.
+	Bytes int64
+	// Error is the error that ended the document, empty if it printed.
+	Error string `json:",omitempty"`
+}
+
+// AuditStore persists AuditRecords. Implementations must be safe for
+// concurrent use when shared by several printers.
+type AuditStore interface {
+	Record(r *AuditRecord) error
+}
+
+// AuditFunc adapts a function to an AuditStore.
+type AuditFunc func(r *AuditRecord) error
+
+func (f AuditFunc) Record(r *AuditRecord) error {
+	return f(r)
+}
+
+var (
+	auditUserOnce sync.Once
+	auditUser     string
+	auditMachine  string
+)
+
+// processUser returns the user running the program and the machine name.
+func processUser() (string, string) {
+	auditUserOnce.Do(func() {
+		if u, err := user.Current(); err == nil {
+			auditUser = u.Username
+		}
+		auditMachine, _ = os.Hostname()
+	})
+	return auditUser, auditMachine
+}
+
+// startAudit starts the record of the document name if p.Audit is set.
+func (p *Printer) startAudit(name string) {
+	if p.Audit == nil {
+		p.audit = nil
+		return
+	}
+	user, machine := processUser()
+	if p.AuditUser != "" {
+		user = p.AuditUser
+	}
+	p.audit = &AuditRecord{
+		Time:     time.Now(),
+		User:     user,
+		Machine:  machine,
+		Printer:  p.name,
+		Document: name,
+	}
+}
+
+// endAudit records the document ended by err.
+func (p *Printer) endAudit(err error) error {
+	r := p.audit
+	if r == nil {
+		return nil
+	}
+	p.audit = nil
+	r.Duration = time.Since(r.Time)
+	r.JobID = p.job
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if err := p.Audit.Record(r); err != nil {
+		return fmt.Errorf("printer: audit: %w", err)
+	}
+	return nil
+}
+
+// FileAuditStore appends AuditRecords to a file as JSON lines.
+type FileAuditStore struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileAuditStore opens or creates the file path and appends the
+// records to it.
+func NewFileAuditStore(path string) (*FileAuditStore, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditStore{f: f}, nil
+}
+
+func (s *FileAuditStore) Record(r *AuditRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	// records must survive a crash of the program
+	return s.f.Sync()
+}
+
+func (s *FileAuditStore) Close() error {
+	return s.f.Close()
+}
+
+// SQLAuditStore inserts AuditRecords in a table of a SQL database, such as
+// SQLite. The database driver is registered by the program.
+type SQLAuditStore struct {
+	db     *sql.DB
+	insert string
+}
+
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// NewSQLAuditStore returns a store inserting the records in table of db,
+// created if it does not exist. The queries use ? placeholders, as
+// SQLite and MySQL do.
+func NewSQLAuditStore(db *sql.DB, table string) (*SQLAuditStore, error) {
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("printer: invalid audit table name %q", table)
+	}
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + table + ` (
+		time TIMESTAMP NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		user_name TEXT NOT NULL,
+		machine TEXT NOT NULL,
+		printer TEXT NOT NULL,
+		document TEXT NOT NULL,
+		job_id INTEGER NOT NULL,
+		bytes INTEGER NOT NULL,
+		error TEXT NOT NULL
+	)`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLAuditStore{
+		db: db,
+		insert: `INSERT INTO ` + table + ` (time, duration_ms, user_name, machine, printer, document, job_id, bytes, error)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	}, nil
+}
+
+func (s *SQLAuditStore) Record(r *AuditRecord) error {
+	_, err := s.db.Exec(s.insert, r.Time.UTC(), r.Duration.Milliseconds(), r.User, r.Machine,
+		r.Printer, r.Document, r.JobID, r.Bytes, r.Error)
+	return err
+}
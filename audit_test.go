@@ -0,0 +1,78 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	var records []AuditRecord
+	p := NewPrinter("fake", new(fakeTransport))
+	p.AuditUser = "alice"
+	p.Audit = AuditFunc(func(r *AuditRecord) error {
+		records = append(records, *r)
+		return nil
+	})
+	if err := p.StartRawDocument("receipt"); err != nil {
+		t.Fatal(err)
+	}
+	p.Write([]byte("hello\n"))
+	if err := p.EndDocument(); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("%d records, want 1", len(records))
+	}
+	r := records[0]
+	if r.User != "alice" || r.Printer != "fake" || r.Document != "receipt" || r.Bytes != 6 || r.Error != "" || r.Time.IsZero() {
+		t.Errorf("record %+v", r)
+	}
+
+	p.Audit = AuditFunc(func(r *AuditRecord) error { return errors.New("disk full") })
+	p.StartRawDocument("receipt")
+	if err := p.EndDocument(); err == nil {
+		t.Error("audit error not returned")
+	}
+}
+
+func TestFileAuditStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	s, err := NewFileAuditStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []string{"a", "b"} {
+		if err := s.Record(&AuditRecord{Document: doc, Bytes: 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	for _, doc := range []string{"a", "b"} {
+		var r AuditRecord
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Document != doc || r.Bytes != 10 {
+			t.Errorf("record %+v", r)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// noCutter is the finish of printers without an autocutter.
+var noCutter = &Finish{Feed: 4, Cut: CutNone}
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = map[string]*Profile{
+		"TM-T20": {Name: "TM-T20", Width: 576, Fonts: map[Font]int{FontA: 48, FontB: 64}},
+		"TM-T70": {Name: "TM-T70", Width: 576, Fonts: map[Font]int{FontA: 48, FontB: 64}},
+		"TM-T82": {Name: "TM-T82", Width: 576, Fonts: map[Font]int{FontA: 48, FontB: 64}},
+		"TM-T88": {Name: "TM-T88", Width: 512, Fonts: map[Font]int{FontA: 42, FontB: 56}},
+		"TM-M30": {Name: "TM-m30", Width: 576, Fonts: map[Font]int{FontA: 48, FontB: 64}},
+		"TM-P20": {Name: "TM-P20", Width: 384, Fonts: map[Font]int{FontA: 32, FontB: 42}, Finish: noCutter},
+		"TM-U220": {
+			Name: "TM-U220", Width: 200, Fonts: map[Font]int{FontA: 33, FontB: 40},
+			MaxCharSize: 2, NoGraphics: true,
+		},
+	}
+)
+
+// RegisterProfile makes ProfileFor, and so OpenAuto, select pr for the
+// printers whose model name starts with model, ignoring case, so
+// applications can describe the models they deploy. The longest
+// matching model wins; registering a model again replaces its profile.
+func RegisterProfile(model string, pr *Profile) {
+	if model == "" || pr == nil {
+		panic("printer: invalid RegisterProfile of " + model)
+	}
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	profiles[strings.ToUpper(model)] = pr
+}
+
+// ProfileFor returns the profile registered for the printer model, such
+// as "TM-T20II", or nil if there is none.
+func ProfileFor(model string) *Profile {
+	model = strings.ToUpper(strings.TrimSpace(model))
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	var pr *Profile
+	n := 0
+	for m, p := range profiles {
+		if len(m) > n && strings.HasPrefix(model, m) {
+			pr, n = p, len(m)
+		}
+	}
+	return pr
+}
+
+// autoTimeout bounds the wait for the answers to GS I while detecting
+// the profile of a printer.
+const autoTimeout = 2 * time.Second
+
+// DetectProfile returns the profile of the printer model, as reported by
+// its IEEE 1284 device ID or, failing that, by GS I. Unknown models get
+// DefaultProfile, without its cutter if GS I reports none.
+func (p *Printer) DetectProfile() (*Profile, error) {
+	if id, err := p.DeviceID(); err == nil {
+		if pr := ProfileFor(id.Model); pr != nil {
+			return pr, nil
+		}
+	}
+	p.SetReadDeadline(time.Now().Add(autoTimeout))
+	defer p.SetReadDeadline(time.Time{})
+	id, err := p.PrinterID()
+	if err != nil {
+		return nil, err
+	}
+	return profileForID(id), nil
+}
+
+// profileForID returns the profile of the printer identified by id.
+func profileForID(id *PrinterID) *Profile {
+	if pr := ProfileFor(id.Model); pr != nil {
+		return pr
+	}
+	if id.HasCutter() {
+		return DefaultProfile
+	}
+	pr := *DefaultProfile
+	pr.Finish = noCutter
+	return &pr
+}
+
+// OpenAuto opens the printer target, a printer URI (see OpenURI) or the
+// name of a spooler queue, and sets its Profile to the one detected for
+// its model, so receipts print with the right paper width, code page and
+// cutter without configuration. The printer keeps DefaultProfile if its
+// model cannot be detected.
+func OpenAuto(target string) (*Printer, error) {
+	var p *Printer
+	var err error
+	if strings.Contains(target, ":") {
+		p, err = OpenURI(target)
+	} else {
+		p, err = Open(target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pr, err := p.DetectProfile(); err == nil {
+		p.Profile = pr
+	}
+	return p, nil
+}
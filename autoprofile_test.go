@@ -0,0 +1,45 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestProfileFor(t *testing.T) {
+	tests := []struct {
+		model, want string
+	}{
+		{"TM-T20II", "TM-T20"},
+		{"tm-m30", "TM-m30"},
+		{" TM-U220B ", "TM-U220"},
+		{"TSP100", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if pr := ProfileFor(tt.model); pr != nil {
+			got = pr.Name
+		}
+		if got != tt.want {
+			t.Errorf("ProfileFor(%q) = %q, want %q", tt.model, got, tt.want)
+		}
+	}
+
+	RegisterProfile("TM-T20III", &Profile{Name: "T20III"})
+	if pr := ProfileFor("TM-T20III"); pr == nil || pr.Name != "T20III" {
+		t.Errorf("registered profile not selected: %v", pr)
+	}
+}
+
+func TestProfileForID(t *testing.T) {
+	if pr := profileForID(&PrinterID{Model: "TM-P20"}); pr.Name != "TM-P20" {
+		t.Errorf("got %s", pr.Name)
+	}
+	if pr := profileForID(&PrinterID{TypeID: printerTypeCutter}); pr != DefaultProfile {
+		t.Errorf("got %s, want the default profile", pr.Name)
+	}
+	pr := profileForID(&PrinterID{})
+	if pr.Width != DefaultProfile.Width || pr.finish().Cut != CutNone {
+		t.Errorf("got %+v, want the default profile without cutter", pr)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BarcodeType is a barcode symbology, as numbered by GS k function B.
+type BarcodeType uint8
+
+const (
+	BarcodeUPCA    BarcodeType = 65
+	BarcodeUPCE    BarcodeType = 66
+	BarcodeEAN13   BarcodeType = 67
+	BarcodeEAN8    BarcodeType = 68
+	BarcodeCODE39  BarcodeType = 69
+	BarcodeITF     BarcodeType = 70
+	BarcodeCODABAR BarcodeType = 71
+	BarcodeCODE93  BarcodeType = 72
+	BarcodeCODE128 BarcodeType = 73
+)
+
+var barcodeNames = map[BarcodeType]string{
+	BarcodeUPCA:    "UPC-A",
+	BarcodeUPCE:    "UPC-E",
+	BarcodeEAN13:   "EAN-13",
+	BarcodeEAN8:    "EAN-8",
+	BarcodeCODE39:  "CODE39",
+	BarcodeITF:     "ITF",
+	BarcodeCODABAR: "CODABAR",
+	BarcodeCODE93:  "CODE93",
+	BarcodeCODE128: "CODE128",
+}
+
+func (t BarcodeType) String() string {
+	if name, ok := barcodeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("BarcodeType(%d)", uint8(t))
+}
+
+// barcodeData returns the data sent for a barcode of type t, checking
+// its length.
+func barcodeData(data string, t BarcodeType) (string, error) {
+	if _, ok := barcodeNames[t]; !ok {
+		return "", fmt.Errorf("printer: invalid barcode type: %d", uint8(t))
+	}
+	if err := validateBarcode(data, t); err != nil {
+		return "", err
+	}
+	if t == BarcodeCODE128 && !hasCodeSet(data) {
+		// select code set B, a literal { is sent as {{
+		data = "{B" + strings.Replace(data, "{", "{{", -1)
+	}
+	if len(data) > 255 {
+		return "", fmt.Errorf("printer: invalid %v barcode data length: %d", t, len(data))
+	}
+	return data, nil
+}
+
+// hasCodeSet reports whether the CODE128 data data starts by selecting
+// its code set, {A, {B or {C, and is sent as is.
+func hasCodeSet(data string) bool {
+	return len(data) >= 2 && data[0] == '{' && data[1] >= 'A' && data[1] <= 'C'
+}
+
+// validateBarcode checks that data can be encoded as a barcode of type t.
+// Printers silently skip invalid barcodes, so this is the only chance to
+// report them.
+func validateBarcode(data string, t BarcodeType) error {
+	if data == "" {
+		return fmt.Errorf("printer: empty %v barcode", t)
+	}
+	digits := strings.Trim(data, "0123456789") == ""
+	switch t {
+	case BarcodeUPCA, BarcodeUPCE, BarcodeEAN13, BarcodeEAN8:
+		lengths := map[BarcodeType][]int{
+			BarcodeUPCA:  {11, 12},
+			BarcodeUPCE:  {6, 7, 8, 11, 12},
+			BarcodeEAN13: {12, 13},
+			BarcodeEAN8:  {7, 8},
+		}[t]
+		if !digits {
+			return fmt.Errorf("printer: %v barcode %q must contain digits only", t, data)
+		}
+		for _, n := range lengths {
+			if len(data) == n {
+				return nil
+			}
+		}
+		return fmt.Errorf("printer: %v barcode %q must have %v digits, not %d", t, data, lengths, len(data))
+	case BarcodeITF:
+		if !digits {
+			return fmt.Errorf("printer: ITF barcode %q must contain digits only", data)
+		}
+		if len(data)%2 != 0 {
+			return fmt.Errorf("printer: ITF barcode %q must have an even number of digits, not %d", data, len(data))
+		}
+	case BarcodeCODABAR:
+		if len(data) < 2 || !isCodabarStartStop(data[0]) || !isCodabarStartStop(data[len(data)-1]) {
+			return fmt.Errorf("printer: CODABAR barcode %q must start and end with one of A, B, C or D", data)
+		}
+		for i := 1; i < len(data)-1; i++ {
+			if !strings.ContainsRune("0123456789-$:/.+", rune(data[i])) {
+				return fmt.Errorf("printer: invalid character %q in CODABAR barcode %q", data[i], data)
+			}
+		}
+	case BarcodeCODE39:
+		for i := 0; i < len(data); i++ {
+			if !strings.ContainsRune("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./", rune(data[i])) {
+				return fmt.Errorf("printer: invalid character %q in CODE39 barcode %q", data[i], data)
+			}
+		}
+	case BarcodeCODE93, BarcodeCODE128:
+		for i := 0; i < len(data); i++ {
+			if data[i] > 0x7F {
+				return fmt.Errorf("printer: invalid character %q in %v barcode %q", data[i], t, data)
+			}
+		}
+	}
+	return nil
+}
+
+func isCodabarStartStop(c byte) bool {
+	return strings.IndexByte("ABCDabcd", c) >= 0
+}
+
+// print a barcode -- GS k m n d1...dn, the human readable text is printed
+// by the printer as set with GS H. The function A types 0 to 6 are
+// accepted as their function B equivalents. CODE128 data is printed in
+// code set B unless it selects its code set itself with {A, {B or {C.
+func (p *Printer) Barcode(data string, t BarcodeType) error {
+	if t < 7 {
+		t += 65
+	}
+	data, err := barcodeData(data, t)
+	if err != nil {
+		return err
+	}
+	b := append([]byte{gs, 'k', byte(t), byte(len(data))}, data...)
+	_, err = p.Write(b)
+	return err
+}
+
+// HRIPosition is where the human readable interpretation of a barcode is
+// printed.
+type HRIPosition uint8
+
+const (
+	HRINone  HRIPosition = 0
+	HRIAbove HRIPosition = 1
+	HRIBelow HRIPosition = 2
+	HRIBoth  HRIPosition = 3
+)
+
+// BarcodeOptions are the settings of the barcodes printed after them.
+// Zero fields leave the printer setting unchanged.
+type BarcodeOptions struct {
+	Height  uint8 // in dots
+	Width   uint8 // module width, 2 to 6
+	HRI     HRIPosition
+	HRIFont Font // FontA or FontB
+	// SetHRI applies HRI even if it is HRINone.
+	SetHRI bool
+}
+
+// set barcode height in dots -- GS h
+func (p *Printer) SetBarcodeHeight(dots uint8) error {
+	if dots == 0 {
+		return fmt.Errorf("printer: invalid barcode height: %d", dots)
+	}
+	_, err := p.Write([]byte{gs, 'h', dots})
+	return err
+}
+
+// set barcode module width, 2 to 6 -- GS w
+func (p *Printer) SetBarcodeWidth(n uint8) error {
+	if n < 2 || n > 6 {
+		return fmt.Errorf("printer: invalid barcode width: %d", n)
+	}
+	_, err := p.Write([]byte{gs, 'w', n})
+	return err
+}
+
+// set position of the barcode human readable interpretation -- GS H
+func (p *Printer) SetHRIPosition(pos HRIPosition) error {
+	if pos > HRIBoth {
+		return fmt.Errorf("printer: invalid HRI position: %d", pos)
+	}
+	_, err := p.Write([]byte{gs, 'H', byte(pos)})
+	return err
+}
+
+// set font of the barcode human readable interpretation -- GS f
+func (p *Printer) SetHRIFont(f Font) error {
+	if f != FontA && f != FontB {
+		return fmt.Errorf("printer: invalid HRI font: %v", f)
+	}
+	_, err := p.Write([]byte{gs, 'f', byte(f)})
+	return err
+}
+
+// SetBarcodeOptions applies the non-zero settings of o.
+func (p *Printer) SetBarcodeOptions(o BarcodeOptions) error {
+	if o.Height != 0 {
+		if err := p.SetBarcodeHeight(o.Height); err != nil {
+			return err
+		}
+	}
+	if o.Width != 0 {
+		if err := p.SetBarcodeWidth(o.Width); err != nil {
+			return err
+		}
+	}
+	if o.HRI != HRINone || o.SetHRI {
+		if err := p.SetHRIPosition(o.HRI); err != nil {
+			return err
+		}
+		if err := p.SetHRIFont(o.HRIFont); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestBarcodeData(t *testing.T) {
+	tests := []struct {
+		data string
+		t    BarcodeType
+		want string
+		ok   bool
+	}{
+		{"TEST123", BarcodeCODE39, "TEST123", true},
+		{"ABC-123", BarcodeCODE128, "{BABC-123", true},
+		{"{C123456", BarcodeCODE128, "{C123456", true},
+		{"A{1}", BarcodeCODE128, "{BA{{1}", true},
+		{"{x}", BarcodeCODE128, "{B{{x}", true},
+		{"", BarcodeEAN13, "", false},
+		{"123", BarcodeType(80), "", false},
+		{"12345678", BarcodeITF, "12345678", true},
+		{"1234567", BarcodeITF, "", false},
+		{"12a4", BarcodeITF, "", false},
+		{"A40156B", BarcodeCODABAR, "A40156B", true},
+		{"40156", BarcodeCODABAR, "", false},
+		{"A40#56B", BarcodeCODABAR, "", false},
+		{"490123456789", BarcodeEAN13, "490123456789", true},
+		{"49012345678", BarcodeEAN13, "", false},
+		{"test", BarcodeCODE39, "", false},
+	}
+	for _, test := range tests {
+		got, err := barcodeData(test.data, test.t)
+		if (err == nil) != test.ok || got != test.want {
+			t.Errorf("barcodeData(%q, %v) = %q, %v", test.data, test.t, got, err)
+		}
+	}
+}
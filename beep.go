@@ -0,0 +1,139 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lengths of the beeps and pauses of beep patterns.
+const (
+	BeepShort = 100 * time.Millisecond
+	BeepLong  = 400 * time.Millisecond
+	BeepPause = 500 * time.Millisecond
+)
+
+// MaxBeepCount is the largest count of a step of a beep pattern.
+const MaxBeepCount = 99
+
+// BeepStep is a step of a beep pattern: Count beeps of Length, or a
+// silence of Pause if Count is zero.
+type BeepStep struct {
+	Count  int
+	Length time.Duration
+	Pause  time.Duration
+}
+
+// BeepPattern is a sequence of beeps and pauses, see ParseBeepPattern.
+type BeepPattern []BeepStep
+
+// ParseBeepPattern parses a comma separated list of "short", "long" and
+// "pause", each optionally preceded by a count of at most MaxBeepCount,
+// such as "long, pause, 2 short".
+func ParseBeepPattern(s string) (BeepPattern, error) {
+	var pat BeepPattern
+	for _, f := range strings.Split(s, ",") {
+		w := strings.Fields(f)
+		n := 1
+		if len(w) == 2 {
+			var err error
+			if n, err = strconv.Atoi(w[0]); err != nil || n < 1 || n > MaxBeepCount {
+				return nil, fmt.Errorf("printer: invalid beep count in %q", f)
+			}
+			w = w[1:]
+		}
+		if len(w) != 1 {
+			return nil, fmt.Errorf("printer: invalid beep pattern %q", s)
+		}
+		switch w[0] {
+		case "short":
+			pat = append(pat, BeepStep{Count: n, Length: BeepShort})
+		case "long":
+			pat = append(pat, BeepStep{Count: n, Length: BeepLong})
+		case "pause":
+			pat = append(pat, BeepStep{Pause: time.Duration(n) * BeepPause})
+		default:
+			return nil, fmt.Errorf("printer: unknown beep %q", w[0])
+		}
+	}
+	return pat, nil
+}
+
+// MustParseBeepPattern is like ParseBeepPattern but panics if s is invalid.
+func MustParseBeepPattern(s string) BeepPattern {
+	pat, err := ParseBeepPattern(s)
+	if err != nil {
+		panic(err)
+	}
+	return pat
+}
+
+var (
+	beepPatternsMu sync.RWMutex
+	beepPatterns   = map[string]BeepPattern{
+		"order":     MustParseBeepPattern("2 short"),
+		"void":      MustParseBeepPattern("long, pause, long"),
+		"low-paper": MustParseBeepPattern("3 short, pause, 3 short"),
+	}
+)
+
+// RegisterBeepPattern sets the pattern played by Alert for event, so
+// kitchens can tell tickets apart by their sound. The events "order",
+// "void" and "low-paper" are preset.
+func RegisterBeepPattern(event string, pat BeepPattern) {
+	if event == "" || len(pat) == 0 {
+		panic("printer: invalid RegisterBeepPattern of " + event)
+	}
+	beepPatternsMu.Lock()
+	defer beepPatternsMu.Unlock()
+	beepPatterns[event] = pat
+}
+
+// beep the buzzer n times for d, rounded to 50ms -- ESC B n t
+func (p *Printer) Beep(n uint8, d time.Duration) error {
+	t := d / (50 * time.Millisecond)
+	if n < 1 || n > 9 || t < 1 || t > 9 {
+		return fmt.Errorf("printer: invalid beep %d times %v", n, d)
+	}
+	_, err := p.Write([]byte{esc, 'B', n, byte(t)})
+	return err
+}
+
+// PlayBeeps sounds pat on the buzzer, in ESC B commands of at most 9
+// beeps. The pauses are timed by the host, so they only separate the
+// beeps of printers written to directly rather than through a spooled
+// document.
+func (p *Printer) PlayBeeps(pat BeepPattern) error {
+	for _, s := range pat {
+		if s.Count < 0 || s.Count > MaxBeepCount {
+			return fmt.Errorf("printer: invalid beep count %d", s.Count)
+		}
+		if s.Count == 0 {
+			time.Sleep(s.Pause)
+			continue
+		}
+		for n := s.Count; n > 0; n -= 9 {
+			if err := p.Beep(uint8(min(n, 9)), s.Length); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Alert plays the pattern registered for event, see RegisterBeepPattern.
+func (p *Printer) Alert(event string) error {
+	beepPatternsMu.RLock()
+	pat, ok := beepPatterns[event]
+	beepPatternsMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("printer: no beep pattern for %q", event)
+	}
+	return p.PlayBeeps(pat)
+}
@@ -0,0 +1,74 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseBeepPattern(t *testing.T) {
+	pat, err := ParseBeepPattern("long, pause, 2 short")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := BeepPattern{{Count: 1, Length: BeepLong}, {Pause: BeepPause}, {Count: 2, Length: BeepShort}}
+	if !reflect.DeepEqual(pat, want) {
+		t.Errorf("got %v, want %v", pat, want)
+	}
+	if _, err := ParseBeepPattern("99 short"); err != nil {
+		t.Errorf("99 short: %v", err)
+	}
+	for _, s := range []string{"", "beep", "0 short", "100 short", "1000000000 short", "100 pause", "two short", "2 long short"} {
+		if _, err := ParseBeepPattern(s); err == nil {
+			t.Errorf("no error for %q", s)
+		}
+	}
+}
+
+func TestAlert(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	RegisterBeepPattern("test", MustParseBeepPattern("long, 12 short"))
+	if err := p.Alert("test"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ft.String(), "\x1BB\x01\x08\x1BB\x09\x02\x1BB\x03\x02"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+	if err := p.Alert("missing"); err == nil {
+		t.Error("no error for an unregistered event")
+	}
+}
+
+func TestPlayBeepsChunks(t *testing.T) {
+	for _, tt := range []struct {
+		count int
+		want  []byte // counts of the ESC B commands
+	}{
+		{9, []byte{9}},
+		{10, []byte{9, 1}},
+		{MaxBeepCount, []byte{9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9}},
+	} {
+		ft := new(fakeTransport)
+		p := NewPrinter("fake", ft)
+		if err := p.PlayBeeps(BeepPattern{{Count: tt.count, Length: BeepShort}}); err != nil {
+			t.Fatal(err)
+		}
+		var want []byte
+		for _, n := range tt.want {
+			want = append(want, esc, 'B', n, 2)
+		}
+		if got := ft.Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("%d beeps wrote %q, want %q", tt.count, got, want)
+		}
+	}
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	if err := p.PlayBeeps(BeepPattern{{Count: MaxBeepCount + 1, Length: BeepShort}}); err == nil || ft.Len() != 0 {
+		t.Errorf("PlayBeeps of %d beeps = %v, wrote %q", MaxBeepCount+1, err, ft.Bytes())
+	}
+}
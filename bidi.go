@@ -0,0 +1,32 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+// BidiStatus is the status a printer reports through its language monitor.
+type BidiStatus struct {
+	// State is the summary state, such as "Idle", "Printing" or "Error".
+	State    string
+	Supplies []Supply
+	Trays    []Tray
+}
+
+// Supply is a consumable of a printer, such as a toner cartridge.
+type Supply struct {
+	Name        string
+	Description string
+	Type        string // "Toner", "Ink", "Drum", ...
+	Color       string
+	// Level is the remaining amount in percent, or -1 if unknown.
+	Level int
+}
+
+// Tray is an input bin of a printer.
+type Tray struct {
+	Name      string
+	MediaType string
+	State     string // "Ready", "Empty", "Open", ...
+	// Level is the remaining amount of paper in percent, or -1 if unknown.
+	Level int
+}
@@ -0,0 +1,91 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"sort"
+)
+
+// Keys of the printer data where language monitors publish the
+// bidirectional schema values they read from the printer.
+const (
+	bidiStatusKey      = `Printer.Status.Summary`
+	bidiConsumablesKey = `Printer.Consumables`
+	bidiInputBinsKey   = `Printer.Layout.InputBins`
+)
+
+// BidiStatus returns the supply levels and tray states of the printer, as
+// published by the language monitor of drivers supporting bidirectional
+// communication. It returns ErrUnsupported if the printer publishes none.
+func (p *Printer) BidiStatus() (*BidiStatus, error) {
+	var st BidiStatus
+	found := false
+	if vs, err := p.dataValues(bidiStatusKey); err == nil && len(vs) > 0 {
+		st.State = dataString(vs["State"])
+		found = true
+	}
+	err := p.bidiEntries(bidiConsumablesKey, func(name string, vs map[string]interface{}) {
+		st.Supplies = append(st.Supplies, Supply{
+			Name:        name,
+			Description: dataString(vs["Description"]),
+			Type:        dataString(vs["Type"]),
+			Color:       dataString(vs["Color"]),
+			Level:       bidiLevel(vs),
+		})
+	})
+	if err == nil {
+		found = true
+	}
+	err = p.bidiEntries(bidiInputBinsKey, func(name string, vs map[string]interface{}) {
+		st.Trays = append(st.Trays, Tray{
+			Name:      name,
+			MediaType: dataString(vs["MediaType"]),
+			State:     dataString(vs["State"]),
+			Level:     bidiLevel(vs),
+		})
+	})
+	if err == nil {
+		found = true
+	}
+	if !found {
+		return nil, ErrUnsupported
+	}
+	return &st, nil
+}
+
+// bidiEntries calls f with the values of each subkey of key, in order of
+// name.
+func (p *Printer) bidiEntries(key string, f func(name string, vs map[string]interface{})) error {
+	names, err := p.dataKeys(key)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		vs, err := p.dataValues(key + `\` + name)
+		if err != nil {
+			continue
+		}
+		f(name, vs)
+	}
+	return nil
+}
+
+// bidiLevel returns the level in percent from the Level value, scaled by
+// MaxCapacity if the level is not in percent.
+func bidiLevel(vs map[string]interface{}) int {
+	level := dataInt(vs["Level"])
+	if level < 0 {
+		return -1
+	}
+	max := dataInt(vs["MaxCapacity"])
+	if max > 0 && dataString(vs["LevelUnit"]) != "Percent" {
+		level = level * 100 / max
+	}
+	if level > 100 {
+		level = 100
+	}
+	return level
+}
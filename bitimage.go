@@ -13,140 +13,215 @@ package printer
 import (
 	"fmt"
 	"image"
+	"runtime"
+	"sync"
+	"time"
 )
 
-func closestNDivisibleBy8(n int) int {
-	q := n / 8
-	n1 := q * 8
-
-	return n1
-}
-
 func printImage(img image.Image) (xL byte, xH byte, yL byte, yH byte, data []byte) {
-	width, height, pixels := getPixels(img)
-
-	removeTransparency(&pixels)
-	makeGrayscale(&pixels)
-
-	printWidth := closestNDivisibleBy8(width)
-	printHeight := closestNDivisibleBy8(height)
-	bytes, _ := rasterize(printWidth, printHeight, &pixels)
-
-	return byte((width >> 3) & 0xff), byte(((width >> 3) >> 8) & 0xff), byte(height & 0xff), byte((height >> 8) & 0xff), bytes
+	stride, data := packRaster(img)
+	height := img.Bounds().Dy()
+	return byte(stride), byte(stride >> 8), byte(height), byte(height >> 8), data
 }
 
-func makeGrayscale(pixels *[][]pixel) {
-	height := len(*pixels)
-	width := len((*pixels)[0])
-
-	for y := 0; y < height; y++ {
-		row := (*pixels)[y]
-		for x := 0; x < width; x++ {
-			pixel := row[x]
-
-			luminance := (float64(pixel.R) * 0.299) + (float64(pixel.G) * 0.587) + (float64(pixel.B) * 0.114)
-			var value int
-			if luminance < 128 {
-				value = 0
-			} else {
-				value = 255
+// lumaRow stores the luminance of the pixels of row y of an image,
+// flattened on white paper, in dst.
+type lumaRow func(y int, dst []uint8)
+
+// lumaRows returns the lumaRow function of img. Common image types are
+// read from their pixel buffers directly instead of through At.
+func lumaRows(img image.Image) lumaRow {
+	b := img.Bounds()
+	switch m := img.(type) {
+	case *image.Gray:
+		return func(y int, dst []uint8) {
+			i := m.PixOffset(b.Min.X, b.Min.Y+y)
+			copy(dst, m.Pix[i:i+len(dst)])
+		}
+	case *image.Paletted:
+		var luma [256]uint8
+		for i, c := range m.Palette {
+			cr, cg, cb, ca := c.RGBA()
+			luma[i] = luminance(cr+0xffff-ca, cg+0xffff-ca, cb+0xffff-ca)
+		}
+		return func(y int, dst []uint8) {
+			pix := m.Pix[m.PixOffset(b.Min.X, b.Min.Y+y):]
+			for x := range dst {
+				dst[x] = luma[pix[x]]
+			}
+		}
+	case *image.RGBA:
+		return func(y int, dst []uint8) {
+			pix := m.Pix[m.PixOffset(b.Min.X, b.Min.Y+y):]
+			for x := range dst {
+				p := pix[4*x : 4*x+4 : 4*x+4]
+				// premultiplied, so adding the transparent part of
+				// white flattens it
+				w := uint32(255 - p[3])
+				dst[x] = luminance8(uint32(p[0])+w, uint32(p[1])+w, uint32(p[2])+w)
 			}
-
-			pixel.R = value
-			pixel.G = value
-			pixel.B = value
-
-			row[x] = pixel
+		}
+	case *image.NRGBA:
+		return func(y int, dst []uint8) {
+			pix := m.Pix[m.PixOffset(b.Min.X, b.Min.Y+y):]
+			for x := range dst {
+				p := pix[4*x : 4*x+4 : 4*x+4]
+				// luminance is linear, so flatten it rather than
+				// the channels
+				a := uint32(p[3])
+				l := 299*uint32(p[0]) + 587*uint32(p[1]) + 114*uint32(p[2])
+				if a == 255 {
+					dst[x] = uint8(l / 1000)
+				} else {
+					dst[x] = uint8((l*a + 255000*(255-a)) / 255000)
+				}
+			}
+		}
+	case *image.YCbCr:
+		return func(y int, dst []uint8) {
+			for x := range dst {
+				dst[x] = m.Y[m.YOffset(b.Min.X+x, b.Min.Y+y)]
+			}
+		}
+	}
+	return func(y int, dst []uint8) {
+		for x := range dst {
+			cr, cg, cb, ca := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			dst[x] = luminance(cr+0xffff-ca, cg+0xffff-ca, cb+0xffff-ca)
 		}
 	}
 }
 
-func removeTransparency(pixels *[][]pixel) {
-	height := len(*pixels)
-	width := len((*pixels)[0])
-
-	for y := 0; y < height; y++ {
-		row := (*pixels)[y]
-		for x := 0; x < width; x++ {
-			pixel := row[x]
-
-			alpha := pixel.A
-			invAlpha := 255 - alpha
+// luminance returns the luminance of a 16 bit per channel color.
+func luminance(r, g, b uint32) uint8 {
+	return luminance8(r>>8, g>>8, b>>8)
+}
 
-			pixel.R = (alpha*pixel.R + invAlpha*255) / 255
-			pixel.G = (alpha*pixel.G + invAlpha*255) / 255
-			pixel.B = (alpha*pixel.B + invAlpha*255) / 255
-			pixel.A = 255
+// luminance8 returns the luminance of an 8 bit per channel color.
+func luminance8(r, g, b uint32) uint8 {
+	return uint8((299*r + 587*g + 114*b) / 1000)
+}
 
-			row[x] = pixel
-		}
+// parallelRows calls fn for ranges of the rows of an image height rows
+// tall, width wide, on several goroutines if the image is large enough
+// for it to pay off.
+func parallelRows(width, height int, fn func(y0, y1 int)) {
+	n := runtime.GOMAXPROCS(0)
+	if width*height < 1<<16 || n == 1 {
+		fn(0, height)
+		return
 	}
+	if n > height {
+		n = height
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(y0, y1 int) {
+			defer wg.Done()
+			fn(y0, y1)
+		}(i*height/n, (i+1)*height/n)
+	}
+	wg.Wait()
 }
 
-func rasterize(printWidth int, printHeight int, pixels *[][]pixel) ([]byte, error) {
-	if printWidth%8 != 0 {
-		return nil, fmt.Errorf("printWidth must be a multiple of 8")
-	}
+// packRaster packs img into rows of dots for GS v 0, stride bytes each,
+// the leftmost dot in the most significant bit. Dots darker than 50% gray
+// are printed; rows are padded with white to a whole number of bytes.
+func packRaster(img image.Image) (stride int, data []byte) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	stride = (w + 7) / 8
+	data = make([]byte, stride*h)
+	row := lumaRows(img)
+	parallelRows(w, h, func(y0, y1 int) {
+		luma := make([]uint8, w)
+		for y := y0; y < y1; y++ {
+			row(y, luma)
+			packRow(data[y*stride:(y+1)*stride], luma)
+		}
+	})
+	return stride, data
+}
 
-	if printHeight%8 != 0 {
-		return nil, fmt.Errorf("printHeight must be a multiple of 8")
+// packRow packs the dots of a row of luminances in dst.
+func packRow(dst []byte, luma []uint8) {
+	n := len(luma) / 8
+	for i := range dst[:n] {
+		p := luma[8*i : 8*i+8 : 8*i+8]
+		dst[i] = dark(p[0])<<7 | dark(p[1])<<6 | dark(p[2])<<5 | dark(p[3])<<4 |
+			dark(p[4])<<3 | dark(p[5])<<2 | dark(p[6])<<1 | dark(p[7])
 	}
-
-	bytes := make([]byte, (printWidth*printHeight)>>3)
-
-	for y := 0; y < printHeight; y++ {
-		for x := 0; x < printWidth; x = x + 8 {
-			i := y*(printWidth>>3) + (x >> 3)
-			bytes[i] =
-				byte((getPixelValue(x+0, y, pixels) << 7) |
-					(getPixelValue(x+1, y, pixels) << 6) |
-					(getPixelValue(x+2, y, pixels) << 5) |
-					(getPixelValue(x+3, y, pixels) << 4) |
-					(getPixelValue(x+4, y, pixels) << 3) |
-					(getPixelValue(x+5, y, pixels) << 2) |
-					(getPixelValue(x+6, y, pixels) << 1) |
-					getPixelValue(x+7, y, pixels))
+	if n < len(dst) {
+		var c byte
+		for bit, l := range luma[8*n:] {
+			c |= dark(l) << uint(7-bit)
 		}
+		dst[n] = c
 	}
-
-	return bytes, nil
 }
 
-func getPixelValue(x int, y int, pixels *[][]pixel) int {
-	row := (*pixels)[y]
-	pixel := row[x]
+// dark returns 1 if a dot of luminance l is printed, 0 otherwise.
+func dark(l uint8) byte {
+	// the subtraction wraps around below 128
+	return byte((uint32(l) - 128) >> 31)
+}
 
-	if pixel.R > 0 {
-		return 0
+// print an image as a raster bit image -- GS v 0, dots darker than 50%
+// gray are printed. Tall images are sent in bands of the profile's
+// BandHeight rows, waiting BandDelay and calling BandWait between them.
+// It returns ErrUnsupported if the profile sets NoGraphics.
+func (p *Printer) PrintImage(img image.Image) error {
+	if p.profile().NoGraphics {
+		return ErrUnsupported
 	}
-
-	return 1
+	b := img.Bounds()
+	if b.Empty() {
+		return fmt.Errorf("printer: empty image")
+	}
+	stride, data := packRaster(img)
+	for _, band := range rasterBands(b.Dy(), p.profile().BandHeight) {
+		if band[0] > 0 {
+			if err := p.waitBand(); err != nil {
+				return err
+			}
+		}
+		rows := band[1] - band[0]
+		header := []byte{gs, 'v', '0', 0, byte(stride), byte(stride >> 8), byte(rows), byte(rows >> 8)}
+		if _, err := p.Write(append(header, data[band[0]*stride:band[1]*stride]...)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func rgbaToPixel(r uint32, g uint32, b uint32, a uint32) pixel {
-	return pixel{int(r >> 8), int(g >> 8), int(b >> 8), int(a >> 8)}
+// rasterBands splits the rows of an image height rows tall in bands of
+// at most band rows, returned as the first row and the row after the
+// last. Bands are at most 0xFFFF rows, the most GS v 0 accepts.
+func rasterBands(height, band int) [][2]int {
+	if band <= 0 || band > 0xFFFF {
+		band = 0xFFFF
+	}
+	var bands [][2]int
+	for y := 0; y < height; y += band {
+		bands = append(bands, [2]int{y, min(y+band, height)})
+	}
+	return bands
 }
 
-type pixel struct {
-	R int
-	G int
-	B int
-	A int
+func (p *Printer) waitBand() error {
+	if d := p.profile().BandDelay; d > 0 {
+		time.Sleep(d)
+	}
+	if p.BandWait != nil {
+		return p.BandWait()
+	}
+	return nil
 }
 
-func getPixels(img image.Image) (int, int, [][]pixel) {
-
-	bounds := img.Bounds()
-	width, height := bounds.Max.X, bounds.Max.Y
-
-	var pixels [][]pixel
-	for y := 0; y < height; y++ {
-		var row []pixel
-		for x := 0; x < width; x++ {
-			row = append(row, rgbaToPixel(img.At(x, y).RGBA()))
-		}
-		pixels = append(pixels, row)
+func min(a, b int) int {
+	if a < b {
+		return a
 	}
-
-	return width, height, pixels
+	return b
 }
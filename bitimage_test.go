@@ -0,0 +1,102 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// checkPackRaster checks data packed from img against the luminances
+// returned by At, ignoring dots whose luminance is so close to 50% gray
+// that rounding decides.
+func checkPackRaster(t *testing.T, img image.Image, data []byte) {
+	b := img.Bounds()
+	stride := (b.Dx() + 7) / 8
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			r, g, bl, a := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			l := luminance(r+0xffff-a, g+0xffff-a, bl+0xffff-a)
+			if l == 127 || l == 128 {
+				continue
+			}
+			if got := data[y*stride+x/8]&(0x80>>uint(x%8)) != 0; got != (l < 128) {
+				t.Errorf("%T %v: dot %d,%d of luminance %d printed = %v", img, b, x, y, l, got)
+				return
+			}
+		}
+	}
+}
+
+func randomImage(w, h int) *image.NRGBA {
+	rnd := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(3, 5, 3+w, 5+h))
+	rnd.Read(img.Pix)
+	return img
+}
+
+func TestPackRaster(t *testing.T) {
+	src := randomImage(37, 11)
+	rgba := image.NewRGBA(src.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+	gray := image.NewGray(src.Bounds())
+	draw.Draw(gray, gray.Bounds(), src, src.Bounds().Min, draw.Src)
+	pal := image.NewPaletted(src.Bounds(), color.Palette{color.Black, color.White, color.Gray{100}, color.NRGBA{0, 0, 0, 40}})
+	draw.Draw(pal, pal.Bounds(), src, src.Bounds().Min, draw.Src)
+	big := randomImage(576, 300)
+	for _, img := range []image.Image{src, rgba, gray, pal, big, big.SubImage(image.Rect(10, 20, 300, 200))} {
+		stride, data := packRaster(img)
+		if stride != (img.Bounds().Dx()+7)/8 {
+			t.Errorf("%T: stride = %d", img, stride)
+		}
+		checkPackRaster(t, img, data)
+	}
+}
+
+// logo returns an opaque image the size of a large logo.
+func logo() *image.NRGBA {
+	img := randomImage(576, 576)
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xFF
+	}
+	return img
+}
+
+func BenchmarkPackRaster(b *testing.B) {
+	img := logo()
+	b.SetBytes(int64(len(img.Pix)))
+	for i := 0; i < b.N; i++ {
+		packRaster(img)
+	}
+}
+
+func BenchmarkDither(b *testing.B) {
+	img := logo()
+	b.SetBytes(int64(len(img.Pix)))
+	for i := 0; i < b.N; i++ {
+		Dither(img)
+	}
+}
+
+func TestRasterBands(t *testing.T) {
+	tests := []struct {
+		height, band int
+		want         [][2]int
+	}{
+		{100, 0, [][2]int{{0, 100}}},
+		{100, 40, [][2]int{{0, 40}, {40, 80}, {80, 100}}},
+		{100, 50, [][2]int{{0, 50}, {50, 100}}},
+		{70000, 0, [][2]int{{0, 0xFFFF}, {0xFFFF, 70000}}},
+	}
+	for _, test := range tests {
+		if got := rasterBands(test.height, test.band); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("rasterBands(%d, %d) = %v, want %v", test.height, test.band, got, test.want)
+		}
+	}
+}
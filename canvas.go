@@ -0,0 +1,214 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"image"
+	"strings"
+)
+
+// FontSpec describes a TrueType font used to draw text on a DC or a
+// preview.
+type FontSpec struct {
+	Family string // typeface name, such as "Arial"
+	Size   int    // in points
+	Weight int    // 100 to 900, FW_NORMAL if zero
+	Italic bool
+}
+
+const (
+	FW_NORMAL = 400
+	FW_BOLD   = 700
+)
+
+// Align is the horizontal alignment of text.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Column describes a column of a table drawn with DC.TableRow, or of a
+// row of a Receipt.
+type Column struct {
+	Width int // in points, or in characters for a Receipt
+	Align Align
+}
+
+// canvas is a surface pages are drawn on, either a DC or an in-memory
+// preview. Coordinates are in points from the top-left corner of the
+// paper.
+type canvas interface {
+	StartPage() error
+	EndPage() error
+
+	// page areas, see PageLayout
+	Body() image.Rectangle
+	Header() image.Rectangle
+	Footer() image.Rectangle
+
+	SetFont(f FontSpec) error
+	MeasureText(s string) (width, height int, err error)
+	TextOut(x, y int, s string) error
+	DrawImage(r image.Rectangle, img image.Image) error
+	Line(x1, y1, x2, y2 int) error
+}
+
+// pageAreas returns the header, body and footer areas of a page of the
+// given size in points with layout l applied, restricted to printable.
+func pageAreas(size image.Point, printable image.Rectangle, l PageLayout) (header, body, footer image.Rectangle) {
+	m := l.Margins
+	r := image.Rect(m.Left, m.Top, size.X-m.Right, size.Y-m.Bottom).Intersect(printable)
+	header, body, footer = r, r, r
+	header.Max.Y = r.Min.Y + l.HeaderHeight
+	body.Min.Y += l.HeaderHeight
+	body.Max.Y -= l.FooterHeight
+	footer.Min.Y = r.Max.Y - l.FooterHeight
+	return header, body, footer
+}
+
+// measureLine is like canvas.MeasureText but also measures the height of empty
+// lines.
+func measureLine(c canvas, line string) (width, height int, err error) {
+	if line == "" {
+		_, height, err = c.MeasureText(" ")
+		return 0, height, err
+	}
+	return c.MeasureText(line)
+}
+
+// wrapText breaks s into lines no wider than width in the current font.
+// Lines are broken at spaces where possible and at newlines in s.
+func wrapText(c canvas, s string, width int) ([]string, error) {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			next := word
+			if line != "" {
+				next = line + " " + word
+			}
+			w, _, err := c.MeasureText(next)
+			if err != nil {
+				return nil, err
+			}
+			if w <= width {
+				line = next
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			// break words that do not fit on a line of their own
+			line, err = breakWord(c, word, width, &lines)
+			if err != nil {
+				return nil, err
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// breakWord appends the parts of word wider than width to lines and
+// returns the remainder.
+func breakWord(c canvas, word string, width int, lines *[]string) (string, error) {
+	r := []rune(word)
+	for len(r) > 1 {
+		n := len(r)
+		for ; n > 1; n-- {
+			w, _, err := c.MeasureText(string(r[:n]))
+			if err != nil {
+				return "", err
+			}
+			if w <= width {
+				break
+			}
+		}
+		if n == len(r) {
+			break
+		}
+		*lines = append(*lines, string(r[:n]))
+		r = r[n:]
+	}
+	return string(r), nil
+}
+
+// textBox draws s wrapped to the width of r and aligned within it,
+// starting at the top of r. Lines that do not fit in r are not drawn. It
+// returns the height of the drawn text.
+func textBox(c canvas, r image.Rectangle, s string, align Align) (int, error) {
+	lines, err := wrapText(c, s, r.Dx())
+	if err != nil {
+		return 0, err
+	}
+	y := r.Min.Y
+	for _, line := range lines {
+		w, h, err := measureLine(c, line)
+		if err != nil {
+			return y - r.Min.Y, err
+		}
+		if y+h > r.Max.Y {
+			break
+		}
+		if err := c.TextOut(alignX(r.Min.X, r.Dx(), w, align), y, line); err != nil {
+			return y - r.Min.Y, err
+		}
+		y += h
+	}
+	return y - r.Min.Y, nil
+}
+
+// textHeight returns the height of s wrapped to width.
+func textHeight(c canvas, s string, width int) (int, error) {
+	lines, err := wrapText(c, s, width)
+	if err != nil {
+		return 0, err
+	}
+	height := 0
+	for _, line := range lines {
+		_, h, err := measureLine(c, line)
+		if err != nil {
+			return 0, err
+		}
+		height += h
+	}
+	return height, nil
+}
+
+// tableRow draws one row of a table at x, y, wrapping every cell to the
+// width of its column. It returns the height of the row.
+func tableRow(c canvas, x, y int, cols []Column, cells []string) (int, error) {
+	height := 0
+	for i, col := range cols {
+		if i >= len(cells) {
+			break
+		}
+		r := image.Rect(x, y, x+col.Width, y+1<<20)
+		h, err := textBox(c, r, cells[i], col.Align)
+		if err != nil {
+			return height, err
+		}
+		if h > height {
+			height = h
+		}
+		x += col.Width
+	}
+	return height, nil
+}
+
+// alignX returns the x position of a width w item aligned in a box of
+// the given width starting at x.
+func alignX(x, width, w int, align Align) int {
+	switch align {
+	case AlignCenter:
+		return x + (width-w)/2
+	case AlignRight:
+		return x + width - w
+	}
+	return x
+}
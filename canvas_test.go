@@ -0,0 +1,122 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"image"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+// fakeCanvas is a canvas with a fixed-width font of 10 by 12 points,
+// recording the text drawn on it.
+type fakeCanvas struct {
+	texts []canvasText
+}
+
+type canvasText struct {
+	x, y int
+	s    string
+}
+
+func (c *fakeCanvas) StartPage() error                                   { return nil }
+func (c *fakeCanvas) EndPage() error                                     { return nil }
+func (c *fakeCanvas) Body() image.Rectangle                              { return image.Rectangle{} }
+func (c *fakeCanvas) Header() image.Rectangle                            { return image.Rectangle{} }
+func (c *fakeCanvas) Footer() image.Rectangle                            { return image.Rectangle{} }
+func (c *fakeCanvas) SetFont(f FontSpec) error                           { return nil }
+func (c *fakeCanvas) DrawImage(r image.Rectangle, img image.Image) error { return nil }
+func (c *fakeCanvas) Line(x1, y1, x2, y2 int) error                      { return nil }
+
+func (c *fakeCanvas) MeasureText(s string) (width, height int, err error) {
+	return 10 * utf8.RuneCountInString(s), 12, nil
+}
+
+func (c *fakeCanvas) TextOut(x, y int, s string) error {
+	c.texts = append(c.texts, canvasText{x, y, s})
+	return nil
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"hello world", 110, []string{"hello world"}},
+		{"hello world", 100, []string{"hello", "world"}},
+		{"hello world", 50, []string{"hello", "world"}},
+		{"a b c d", 30, []string{"a b", "c d"}},
+		{"two\n\nparas", 100, []string{"two", "", "paras"}},
+		{"  spaced   out  ", 100, []string{"spaced out"}},
+		{"", 100, []string{""}},
+		// words wider than a line are broken, the rest continues the line
+		{"abcdefghij xy", 40, []string{"abcd", "efgh", "ij", "xy"}},
+		{"ab cdefghij", 40, []string{"ab", "cdef", "ghij"}},
+		{"çöğüşışık", 50, []string{"çöğüş", "ışık"}},
+	}
+	for _, test := range tests {
+		got, err := wrapText(new(fakeCanvas), test.s, test.width)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("wrapText(%q, %d) = %q, want %q", test.s, test.width, got, test.want)
+		}
+	}
+}
+
+func TestBreakWord(t *testing.T) {
+	tests := []struct {
+		word  string
+		width int
+		lines []string
+		rest  string
+	}{
+		{"abc", 30, nil, "abc"},
+		{"abcdefg", 30, []string{"abc", "def"}, "g"},
+		{"abcdef", 30, []string{"abc"}, "def"},
+		// a line holds at least one rune, even if it does not fit
+		{"abc", 5, []string{"a", "b"}, "c"},
+	}
+	for _, test := range tests {
+		var lines []string
+		rest, err := breakWord(new(fakeCanvas), test.word, test.width, &lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(lines, test.lines) || rest != test.rest {
+			t.Errorf("breakWord(%q, %d) = %q, %q, want %q, %q", test.word, test.width, lines, rest, test.lines, test.rest)
+		}
+	}
+}
+
+func TestTableRow(t *testing.T) {
+	c := new(fakeCanvas)
+	cols := []Column{
+		{Width: 60, Align: AlignLeft},
+		{Width: 40, Align: AlignCenter},
+		{Width: 50, Align: AlignRight},
+	}
+	h, err := tableRow(c, 5, 20, cols, []string{"tea with milk", "2", "3.50", "ignored"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first cell wraps to three lines, setting the row height
+	if h != 36 {
+		t.Errorf("height = %d, want 36", h)
+	}
+	want := []canvasText{
+		{5, 20, "tea"},
+		{5, 32, "with"},
+		{5, 44, "milk"},
+		{5 + 60 + 15, 20, "2"},
+		{5 + 60 + 40 + 10, 20, "3.50"},
+	}
+	if !reflect.DeepEqual(c.texts, want) {
+		t.Errorf("drew %v, want %v", c.texts, want)
+	}
+}
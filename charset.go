@@ -0,0 +1,100 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+)
+
+// CharSet is an international character set selected with ESC R. It
+// replaces a few ASCII characters, such as '#' and '$', with national
+// ones.
+type CharSet uint8
+
+const (
+	CharSetUSA             CharSet = 0
+	CharSetFrance          CharSet = 1
+	CharSetGermany         CharSet = 2
+	CharSetUK              CharSet = 3
+	CharSetDenmarkI        CharSet = 4
+	CharSetSweden          CharSet = 5
+	CharSetItaly           CharSet = 6
+	CharSetSpainI          CharSet = 7
+	CharSetJapan           CharSet = 8
+	CharSetNorway          CharSet = 9
+	CharSetDenmarkII       CharSet = 10
+	CharSetSpainII         CharSet = 11
+	CharSetLatinAmerica    CharSet = 12
+	CharSetKorea           CharSet = 13
+	CharSetSloveniaCroatia CharSet = 14
+	CharSetChina           CharSet = 15
+	CharSetVietnam         CharSet = 16
+	CharSetArabia          CharSet = 17
+)
+
+var charSetNames = [...]string{
+	CharSetUSA:             "USA",
+	CharSetFrance:          "France",
+	CharSetGermany:         "Germany",
+	CharSetUK:              "UK",
+	CharSetDenmarkI:        "Denmark I",
+	CharSetSweden:          "Sweden",
+	CharSetItaly:           "Italy",
+	CharSetSpainI:          "Spain I",
+	CharSetJapan:           "Japan",
+	CharSetNorway:          "Norway",
+	CharSetDenmarkII:       "Denmark II",
+	CharSetSpainII:         "Spain II",
+	CharSetLatinAmerica:    "Latin America",
+	CharSetKorea:           "Korea",
+	CharSetSloveniaCroatia: "Slovenia/Croatia",
+	CharSetChina:           "China",
+	CharSetVietnam:         "Vietnam",
+	CharSetArabia:          "Arabia",
+}
+
+func (cs CharSet) String() string {
+	if cs.valid() {
+		return charSetNames[cs]
+	}
+	return fmt.Sprintf("CharSet(%d)", uint8(cs))
+}
+
+func (cs CharSet) valid() bool {
+	return int(cs) < len(charSetNames)
+}
+
+// langCharSets maps the language codes accepted by SetLang to character
+// sets.
+var langCharSets = map[string]CharSet{
+	"en":     CharSetUSA,
+	"fr":     CharSetFrance,
+	"de":     CharSetGermany,
+	"uk":     CharSetUK,
+	"da":     CharSetDenmarkI,
+	"sv":     CharSetSweden,
+	"it":     CharSetItaly,
+	"es":     CharSetSpainI,
+	"ja":     CharSetJapan,
+	"no":     CharSetNorway,
+	"da2":    CharSetDenmarkII,
+	"es2":    CharSetSpainII,
+	"es-419": CharSetLatinAmerica,
+	"ko":     CharSetKorea,
+	"sl":     CharSetSloveniaCroatia,
+	"hr":     CharSetSloveniaCroatia,
+	"zh":     CharSetChina,
+	"vi":     CharSetVietnam,
+	"ar":     CharSetArabia,
+}
+
+// set international character set -- ESC R
+func (p *Printer) SetCharSet(cs CharSet) error {
+	if !p.profile().SupportsCharSet(cs) {
+		return fmt.Errorf("printer: character set %v not supported by profile %s", cs, p.profile().Name)
+	}
+	_, err := p.Write([]byte{esc, 'R', byte(cs)})
+	return err
+}
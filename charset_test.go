@@ -0,0 +1,23 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestCharSet(t *testing.T) {
+	if got := CharSetSloveniaCroatia.String(); got != "Slovenia/Croatia" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := CharSet(99).String(); got != "CharSet(99)" {
+		t.Errorf("String() = %q", got)
+	}
+	if !DefaultProfile.SupportsCharSet(CharSetArabia) || DefaultProfile.SupportsCharSet(CharSet(99)) {
+		t.Error("DefaultProfile supports the wrong character sets")
+	}
+	pr := &Profile{Name: "test", CharSets: []CharSet{CharSetUSA, CharSetJapan}}
+	if !pr.SupportsCharSet(CharSetJapan) || pr.SupportsCharSet(CharSetKorea) {
+		t.Error("profile supports the wrong character sets")
+	}
+}
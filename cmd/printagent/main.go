@@ -0,0 +1,327 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Printagent is an in-store print agent: a Windows service serving the
+// printers of the machine over gRPC, see package printrpc, and optionally
+// a dashboard of their queues over HTTP, see printer.Dashboard.
+//
+// Usage:
+//
+//	printagent [-config file] command
+//
+// The commands are:
+//
+//	install  register the agent as a Windows service started at boot
+//	remove   unregister the service
+//	start    start the service
+//	stop     stop the service
+//	run      run the agent, as a service when started by the service
+//	         manager and in the foreground until Ctrl-C otherwise
+//
+// The configuration file defaults to printagent.json next to the
+// executable. It is a JSON object with the fields of Config, such as
+//
+//	{
+//		"Addr": "127.0.0.1:50051",
+//		"HTTPAddr": "127.0.0.1:8631",
+//		"Interval": "500ms",
+//		"LogFile": "C:\\ProgramData\\printagent\\printagent.log",
+//		"Quota": {
+//			"Default": {"Jobs": 100, "Period": "1h"},
+//			"Users": {"back-office": {}}
+//		},
+//		"DedupTTL": "10m"
+//	}
+//
+// Missing fields and a missing file leave the defaults.
+//
+// The agent keeps no queue of its own: documents submitted while a printer
+// is offline wait in the print queue of the Windows spooler, which prints
+// them once the printer is back.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/icobani/printer"
+	"github.com/icobani/printer/printrpc"
+	"google.golang.org/grpc"
+)
+
+const serviceName = "printagent"
+
+var configFlag = flag.String("config", "", "configuration `file`")
+
+// Config holds the settings of the agent.
+type Config struct {
+	// Addr is the TCP address the gRPC server listens on.
+	Addr string
+	// HTTPAddr, if set, is the TCP address the dashboard of the print
+	// queues is served on. The dashboard has no access control, keep it
+	// on a local address.
+	HTTPAddr string
+	// Interval is how often job status streams and the dashboard poll
+	// the print queues.
+	Interval Duration
+	// LogFile, if set, receives the log instead of the standard error
+	// or, as a service, the void.
+	LogFile string
+	// Quota, if set, limits the documents submitted per API key.
+	Quota *QuotaConfig
+	// DedupTTL, if set, is how long the document IDs of the submitted
+	// documents are remembered to reject them when submitted again.
+	DedupTTL Duration
+}
+
+// QuotaConfig configures the quotas of the API keys, see
+// printer.QuotaPolicy.
+type QuotaConfig struct {
+	// Default is the quota of the API keys not in Users.
+	Default Quota
+	// Users holds the quotas of specific API keys.
+	Users map[string]Quota
+	// Wait makes the documents beyond the quota wait for it to reset
+	// instead of failing.
+	Wait bool
+}
+
+// Quota is a printer.Quota with its period written as a string.
+type Quota struct {
+	Jobs   int
+	Pages  int
+	Bytes  int64
+	Period Duration
+}
+
+func (q Quota) quota() printer.Quota {
+	return printer.Quota{Jobs: q.Jobs, Pages: q.Pages, Bytes: q.Bytes, Period: time.Duration(q.Period)}
+}
+
+// policy returns the quota policy configured by c.
+func (c *QuotaConfig) policy() *printer.QuotaPolicy {
+	p := printer.NewQuotaPolicy(c.Default.quota())
+	p.Wait = c.Wait
+	if len(c.Users) > 0 {
+		p.Users = make(map[string]printer.Quota, len(c.Users))
+		for user, q := range c.Users {
+			p.Users[user] = q.quota()
+		}
+	}
+	return p
+}
+
+// Duration is a time.Duration written as a string such as "1s" in JSON.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+var defaultConfig = Config{
+	Addr:     "127.0.0.1:50051",
+	Interval: Duration(printrpc.DefaultInterval),
+}
+
+// configPath returns the path of the configuration file.
+func configPath() (string, error) {
+	if *configFlag != "" {
+		return filepath.Abs(*configFlag)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(exe), serviceName+".json"), nil
+}
+
+func loadConfig(path string) (*Config, error) {
+	c := defaultConfig
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &c, nil
+}
+
+// agent is a running print agent.
+type agent struct {
+	srv  *grpc.Server
+	http *http.Server // nil without dashboard
+	// stopDashboard stops the recording of the jobs of the dashboard.
+	stopDashboard context.CancelFunc
+	done          chan error
+	log           io.Closer
+}
+
+// startAgent starts serving the printers as configured by c.
+func startAgent(c *Config) (*agent, error) {
+	a := &agent{done: make(chan error, 2)}
+	if c.LogFile != "" {
+		f, err := os.OpenFile(c.LogFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		log.SetOutput(f)
+		a.log = f
+	}
+	l, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		a.close()
+		return nil, err
+	}
+	var hl net.Listener
+	if c.HTTPAddr != "" {
+		if hl, err = net.Listen("tcp", c.HTTPAddr); err != nil {
+			l.Close()
+			a.close()
+			return nil, err
+		}
+	}
+	s := &printrpc.Server{Interval: time.Duration(c.Interval)}
+	if c.Quota != nil {
+		s.Quota = c.Quota.policy()
+	}
+	if c.DedupTTL > 0 {
+		s.Dedup = printer.NewDedup(time.Duration(c.DedupTTL))
+	}
+	a.srv = grpc.NewServer()
+	printrpc.RegisterPrintServiceServer(a.srv, s)
+	log.Printf("serving printers on %s", l.Addr())
+	go func() {
+		a.done <- a.srv.Serve(l)
+	}()
+	if hl != nil {
+		d := printer.NewDashboard()
+		d.Interval = time.Duration(c.Interval)
+		ctx, cancel := context.WithCancel(context.Background())
+		a.stopDashboard = cancel
+		go d.Run(ctx)
+		a.http = &http.Server{Handler: d}
+		log.Printf("serving dashboard on http://%s/", hl.Addr())
+		go func() {
+			if err := a.http.Serve(hl); err != http.ErrServerClosed {
+				a.done <- err
+			}
+		}()
+	}
+	return a, nil
+}
+
+// stop stops the agent, letting the pending calls finish for a while.
+func (a *agent) stop() {
+	stopped := make(chan struct{})
+	go func() {
+		a.srv.GracefulStop()
+		close(stopped)
+	}()
+	if a.http != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := a.http.Shutdown(ctx); err != nil {
+			a.http.Close()
+		}
+		cancel()
+		a.stopDashboard()
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		a.srv.Stop()
+	}
+	log.Printf("stopped")
+	a.close()
+}
+
+// close closes the log file, sending the log to stderr again.
+func (a *agent) close() {
+	if a.log != nil {
+		log.SetOutput(os.Stderr)
+		a.log.Close()
+	}
+}
+
+// runForeground runs the agent configured by the file path until Ctrl-C.
+func runForeground(path string) error {
+	c, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	a, err := startAgent(c)
+	if err != nil {
+		return err
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	select {
+	case <-sig:
+	case err = <-a.done:
+	}
+	a.stop()
+	return err
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: printagent [-config file] install|remove|start|stop|run\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 1 {
+		usage()
+	}
+	path, err := configPath()
+	if err != nil {
+		log.Fatal(err)
+	}
+	switch flag.Arg(0) {
+	case "install":
+		err = installService(path)
+	case "remove":
+		err = removeService()
+	case "start":
+		err = startService()
+	case "stop":
+		err = stopService()
+	case "run":
+		err = run(path)
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatalf("printagent: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package main
+
+import "errors"
+
+var errNoService = errors.New("services are only supported on Windows, use run")
+
+// run runs the agent configured by the file path until Ctrl-C.
+func run(path string) error {
+	return runForeground(path)
+}
+
+func installService(path string) error { return errNoService }
+func removeService() error             { return errNoService }
+func startService() error              { return errNoService }
+func stopService() error               { return errNoService }
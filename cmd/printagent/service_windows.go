@@ -0,0 +1,154 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// run runs the agent configured by the file path until it is stopped by
+// the service manager or, in the foreground, by Ctrl-C.
+func run(path string) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return err
+	}
+	if isService {
+		return svc.Run(serviceName, &handler{path: path})
+	}
+	return runForeground(path)
+}
+
+// handler runs the agent as a Windows service.
+type handler struct {
+	path string
+}
+
+func (h *handler) Execute(args []string, req <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+	c, err := loadConfig(h.path)
+	if err != nil {
+		log.Print(err)
+		return true, 1
+	}
+	a, err := startAgent(c)
+	if err != nil {
+		log.Print(err)
+		return true, 2
+	}
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+	status <- svc.Status{State: svc.Running, Accepts: accepted}
+	for {
+		select {
+		case r := <-req:
+			switch r.Cmd {
+			case svc.Interrogate:
+				status <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				a.stop()
+				return false, 0
+			}
+		case err := <-a.done:
+			// a server failed, stop the others and let the service
+			// manager restart us
+			log.Print(err)
+			a.stop()
+			return true, 3
+		}
+	}
+}
+
+func installService(path string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	if s, err := m.OpenService(serviceName); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s already exists", serviceName)
+	}
+	s, err := m.CreateService(serviceName, exe, mgr.Config{
+		DisplayName: "Print agent",
+		Description: "Serves the printers of this machine over gRPC.",
+		StartType:   mgr.StartAutomatic,
+	}, "-config", path, "run")
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	// restart after failures, such as the port being taken at boot
+	return s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+	}, uint32((24 * time.Hour).Seconds()))
+}
+
+func removeService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", serviceName)
+	}
+	defer s.Close()
+	return s.Delete()
+}
+
+func startService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return s.Start()
+}
+
+func stopService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	status, err := s.Control(svc.Stop)
+	if err != nil {
+		return err
+	}
+	for timeout := time.Now().Add(10 * time.Second); status.State != svc.Stopped; {
+		if time.Now().After(timeout) {
+			return fmt.Errorf("service %s did not stop", serviceName)
+		}
+		time.Sleep(300 * time.Millisecond)
+		if status, err = s.Query(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
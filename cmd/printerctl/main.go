@@ -0,0 +1,284 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Printerctl lists, inspects and drives the printers of a Windows
+// machine, for validating installs in the field.
+//
+// Usage:
+//
+//	printerctl [-p printer] command [arguments]
+//
+// The commands are:
+//
+//	list              list the printers, the default one marked with *
+//	status [printer]  show the settings and status of a printer
+//	print file        print file unmodified, as raw data
+//	jobs [printer]    list the print jobs of a printer
+//	cancel id         cancel a print job
+//	watch [printer]   show print jobs and default printer changes
+//	testpage [printer] print a test page
+//
+// Printers default to the -p flag, then to the default printer. Names
+// may be abbreviated as long as they match a single printer. With -uri,
+// print sends the file to a printer URI instead, see printer.OpenURI.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/icobani/printer"
+)
+
+var (
+	printerFlag = flag.String("p", "", "printer `name`")
+	uriFlag     = flag.String("uri", "", "printer `URI` used by print instead of a queue")
+	interval    = flag.Duration("interval", time.Second, "polling `interval` of watch")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `usage: printerctl [flags] command [arguments]
+
+commands:
+  list               list the printers, the default one marked with *
+  status [printer]   show the settings and status of a printer
+  print file         print file unmodified, as raw data
+  jobs [printer]     list the print jobs of a printer
+  cancel id          cancel a print job
+  watch [printer]    show print jobs and default printer changes
+  testpage [printer] print a test page
+
+flags:
+`)
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+	}
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	var err error
+	switch cmd {
+	case "list":
+		err = list()
+	case "status":
+		err = status(args)
+	case "print":
+		if len(args) != 1 {
+			usage()
+		}
+		err = print(args[0])
+	case "jobs":
+		err = jobs(args)
+	case "cancel":
+		if len(args) != 1 {
+			usage()
+		}
+		err = cancel(args[0])
+	case "watch":
+		err = watch(args)
+	case "testpage":
+		var name string
+		if name, err = printerName(args); err == nil {
+			err = printer.PrintTestPage(name)
+		}
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "printerctl:", err)
+		os.Exit(1)
+	}
+}
+
+// printerName returns the name of the printer selected by args, the -p
+// flag or else the default printer.
+func printerName(args []string) (string, error) {
+	name := *printerFlag
+	switch len(args) {
+	case 0:
+	case 1:
+		name = args[0]
+	default:
+		usage()
+	}
+	if name == "" {
+		return printer.Default()
+	}
+	return printer.Resolve(name)
+}
+
+func open(args []string) (*printer.Printer, error) {
+	name, err := printerName(args)
+	if err != nil {
+		return nil, err
+	}
+	return printer.Open(name)
+}
+
+func list() error {
+	printers, err := printer.Enumerate(printer.EnumOptions{Level: 2})
+	if err != nil {
+		return err
+	}
+	def, _ := printer.Default()
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "\tNAME\tPORT\tDRIVER\tJOBS")
+	for _, p := range printers {
+		mark := ""
+		if p.Name == def {
+			mark = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", mark, p.Name, p.PortName, p.DriverName, p.Jobs)
+	}
+	return w.Flush()
+}
+
+func status(args []string) error {
+	p, err := open(args)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	info, err := p.Info()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", info.Name)
+	fmt.Fprintf(w, "Port:\t%s\n", info.PortName)
+	fmt.Fprintf(w, "Driver:\t%s\n", info.DriverName)
+	if info.ShareName != "" {
+		fmt.Fprintf(w, "Share:\t%s\n", info.ShareName)
+	}
+	if info.Location != "" {
+		fmt.Fprintf(w, "Location:\t%s\n", info.Location)
+	}
+	fmt.Fprintf(w, "Attributes:\t%v\n", info.Attributes)
+	if info.Status == 0 {
+		fmt.Fprintf(w, "Status:\tready\n")
+	} else {
+		fmt.Fprintf(w, "Status:\t%#x\n", info.Status)
+	}
+	fmt.Fprintf(w, "Jobs:\t%d\n", info.Jobs)
+	if class, err := p.DeviceClass(); err == nil {
+		fmt.Fprintf(w, "Class:\t%v\n", class)
+	}
+	if id, err := p.DeviceID(); err == nil {
+		fmt.Fprintf(w, "Device:\t%s %s\n", id.Manufacturer, id.Model)
+	}
+	if bs, err := p.BidiStatus(); err == nil && bs.State != "" {
+		fmt.Fprintf(w, "Device status:\t%s\n", bs.State)
+	}
+	return w.Flush()
+}
+
+func print(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if *uriFlag == "" {
+		name, err := printerName(nil)
+		if err != nil {
+			return err
+		}
+		return printer.PrintRaw(name, file, data)
+	}
+	p, err := printer.OpenURI(*uriFlag)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	return p.PrintRaw(file, data)
+}
+
+func jobs(args []string) error {
+	p, err := open(args)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	jobs, err := p.Jobs()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDOCUMENT\tUSER\tSTATUS\tPAGES\tSUBMITTED")
+	for _, j := range jobs {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d/%d\t%s\n", j.JobID, j.DocumentName, j.UserName, j.Status,
+			j.PagesPrinted, j.TotalPages, j.Submitted.Local().Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func cancel(arg string) error {
+	id, err := strconv.ParseUint(arg, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid job id %q", arg)
+	}
+	p, err := open(nil)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	return p.CancelJob(uint32(id))
+}
+
+func watch(args []string) error {
+	p, err := open(args)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		cancel()
+	}()
+	events, err := p.WatchJobs(ctx, *interval)
+	if err != nil {
+		return err
+	}
+	defaults, err := printer.WatchDefault(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				// closed after Ctrl-C is a normal shutdown
+				if errors.Is(ctx.Err(), context.Canceled) {
+					return nil
+				}
+				return errors.New("print queue cannot be read anymore")
+			}
+			fmt.Printf("%s job %d %-7s %s: %s\n", time.Now().Format("15:04:05"), e.Job.JobID, e.Kind, e.Job.DocumentName, e.Job.Status)
+		case d, ok := <-defaults:
+			if !ok {
+				defaults = nil
+				continue
+			}
+			fmt.Printf("%s default printer %q -> %q\n", time.Now().Format("15:04:05"), d.Old, d.New)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Code pages, as numbered by ESC t, that the package encodes and decodes.
+const (
+	CodePagePC437      = 0 // USA, standard Europe
+	CodePagePC850      = 2 // multilingual
+	CodePagePC860      = 3 // Portuguese
+	CodePagePC863      = 4 // Canadian French
+	CodePagePC865      = 5 // Nordic
+	CodePageISO8859_7  = 15
+	CodePageWPC1252    = 16
+	CodePagePC866      = 17 // Cyrillic
+	CodePagePC852      = 18 // Latin 2
+	CodePagePC858      = 19 // PC850 with €
+	CodePagePC855      = 34
+	CodePagePC862      = 36 // Hebrew
+	CodePageISO8859_2  = 39
+	CodePageISO8859_15 = 40
+	CodePageWPC1250    = 45
+	CodePageWPC1251    = 46
+	CodePageWPC1253    = 47
+	CodePageWPC1254    = 48 // Turkish
+	CodePageWPC1255    = 49
+	CodePageWPC1256    = 50
+	CodePageWPC1257    = 51
+	CodePageWPC1258    = 52
+)
+
+// codePages maps the code pages selected with ESC t to their encodings.
+var codePages = map[byte]*charmap.Charmap{
+	CodePagePC437:      charmap.CodePage437,
+	CodePagePC850:      charmap.CodePage850,
+	CodePagePC860:      charmap.CodePage860,
+	CodePagePC863:      charmap.CodePage863,
+	CodePagePC865:      charmap.CodePage865,
+	CodePageISO8859_7:  charmap.ISO8859_7,
+	CodePageWPC1252:    charmap.Windows1252,
+	CodePagePC866:      charmap.CodePage866,
+	CodePagePC852:      charmap.CodePage852,
+	CodePagePC858:      charmap.CodePage858,
+	CodePagePC855:      charmap.CodePage855,
+	CodePagePC862:      charmap.CodePage862,
+	CodePageISO8859_2:  charmap.ISO8859_2,
+	CodePageISO8859_15: charmap.ISO8859_15,
+	CodePageWPC1250:    charmap.Windows1250,
+	CodePageWPC1251:    charmap.Windows1251,
+	CodePageWPC1253:    charmap.Windows1253,
+	CodePageWPC1254:    charmap.Windows1254,
+	CodePageWPC1255:    charmap.Windows1255,
+	CodePageWPC1256:    charmap.Windows1256,
+	CodePageWPC1257:    charmap.Windows1257,
+	CodePageWPC1258:    charmap.Windows1258,
+}
+
+// EncodeString encodes the UTF-8 text s in the code page n, replacing the
+// characters it lacks with '?', so applications need no encoding tables
+// of their own to prepare text, such as for a Recorder replay or a
+// template. Printer.WriteString encodes text itself.
+func EncodeString(n uint8, s string) ([]byte, error) {
+	cp, ok := codePages[n]
+	if !ok {
+		return nil, fmt.Errorf("printer: unknown code page %d", n)
+	}
+	return encodeText(cp, s), nil
+}
+
+// DecodeString decodes b, text in the code page n, to UTF-8.
+func DecodeString(n uint8, b []byte) (string, error) {
+	cp, ok := codePages[n]
+	if !ok {
+		return "", fmt.Errorf("printer: unknown code page %d", n)
+	}
+	s, err := cp.NewDecoder().Bytes(b)
+	return string(s), err
+}
+
+// encodeText encodes s in cp, replacing the characters it lacks with '?'.
+func encodeText(cp *charmap.Charmap, s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		c, ok := cp.EncodeRune(r)
+		if !ok {
+			c = '?'
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+// set the character code table -- ESC t n, the text written by
+// WriteString is then encoded in it
+func (p *Printer) SetCodePage(n uint8) error {
+	_, err := p.Write([]byte{esc, 't', n})
+	if err == nil {
+		p.codePage = n
+	}
+	return err
+}
+
+// CodePage returns the code page selected with SetCodePage or Init.
+func (p *Printer) CodePage() uint8 {
+	return p.codePage
+}
+
+// encode encodes the UTF-8 text s in the code page selected on p. Currency
+// symbols the code page lacks are printed from another code page of the
+// profile, or spelled out, see CurrencyFallbacks; other characters it
+// lacks become '?'. s is returned unchanged if it is ASCII or not valid
+// UTF-8, such as text already encoded, or if the package does not know
+// the code page.
+func (p *Printer) encode(s string) string {
+	if isASCII(s) || !utf8.ValidString(s) {
+		return s
+	}
+	cp, ok := codePages[p.codePage]
+	if !ok {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if c, ok := cp.EncodeRune(r); ok {
+			b = append(b, c)
+			continue
+		}
+		if n, c, ok := p.currencyCodePage(r); ok {
+			b = append(b, esc, 't', n, c, esc, 't', p.codePage)
+			continue
+		}
+		if f, ok := CurrencyFallbacks[r]; ok {
+			b = append(b, f...)
+			continue
+		}
+		b = append(b, '?')
+	}
+	return string(b)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
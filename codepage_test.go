@@ -0,0 +1,49 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestEncodeString(t *testing.T) {
+	tests := []struct {
+		codePage uint8
+		text     string
+		want     string
+	}{
+		{CodePagePC437, "Grüße £", "Gr\x81\xE1e \x9C"},
+		{CodePagePC858, "5 €", "5 \xD5"},
+		{CodePagePC866, "Итого", "\x88\xE2\xAE\xA3\xAE"},
+		{CodePageWPC1254, "Şişli", "\xDEi\xFEli"},
+		{CodePagePC437, "Şişli", "?i?li"},
+	}
+	for _, tt := range tests {
+		b, err := EncodeString(tt.codePage, tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("EncodeString(%d, %q) = %q, want %q", tt.codePage, tt.text, b, tt.want)
+		}
+	}
+	if _, err := EncodeString(1, "カ"); err == nil {
+		t.Error("no error for an unknown code page")
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	for n := range codePages {
+		const text = "Total 12.50\n"
+		b, err := EncodeString(n, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s, err := DecodeString(n, b); err != nil || s != text {
+			t.Errorf("code page %d: got %q, %v", n, s, err)
+		}
+	}
+	if s, _ := DecodeString(CodePagePC858, []byte("\x9C5 \xD5")); s != "£5 €" {
+		t.Errorf("got %q", s)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CostRate is the price of printing, in the smallest unit of a currency,
+// such as cents.
+type CostRate struct {
+	PerJob  int64
+	PerPage int64
+}
+
+// CostModel prices the jobs completed on printers, see AccountJobs.
+type CostModel struct {
+	// Default is the rate of the printers not in Printers.
+	Default CostRate
+	// Printers holds the rates of printers by name, ignoring case.
+	Printers map[string]CostRate
+	// Department, if set, returns the department charged for the jobs
+	// of a user, such as from a directory lookup.
+	Department func(user string) string
+}
+
+// rate returns the rate of the printer called name.
+func (m *CostModel) rate(name string) CostRate {
+	for n, r := range m.Printers {
+		if strings.EqualFold(n, name) {
+			return r
+		}
+	}
+	return m.Default
+}
+
+// Cost returns the accounting record of the job completed on the
+// printer called name. Jobs that do not report the pages printed are
+// charged for their total pages.
+func (m *CostModel) Cost(name string, j JobInfo) *CostRecord {
+	pages := j.PagesPrinted
+	if pages == 0 {
+		pages = j.TotalPages
+	}
+	r := m.rate(name)
+	rec := &CostRecord{
+		Time:     time.Now(),
+		Printer:  name,
+		JobID:    j.JobID,
+		Document: j.DocumentName,
+		User:     j.UserName,
+		Pages:    pages,
+		Cost:     r.PerJob + r.PerPage*int64(pages),
+	}
+	if m.Department != nil {
+		rec.Department = m.Department(j.UserName)
+	}
+	return rec
+}
+
+// CostRecord is the accounting record of a completed print job.
+type CostRecord struct {
+	Time       time.Time
+	Printer    string
+	JobID      uint32
+	Document   string
+	User       string
+	Department string `json:",omitempty"`
+	Pages      uint32
+	Cost       int64
+}
+
+// CostSink receives CostRecords, to persist them or forward them to an
+// accounting system. Implementations must be safe for concurrent use when
+// shared by several printers.
+type CostSink interface {
+	Record(r *CostRecord) error
+}
+
+// CostFunc adapts a function to a CostSink.
+type CostFunc func(r *CostRecord) error
+
+func (f CostFunc) Record(r *CostRecord) error {
+	return f(r)
+}
+
+// AccountJobs prices the jobs completed on p with m and sends their
+// records to sink until ctx is done, polling the queue every interval.
+// Errors of sink are passed to onError, if set, and do not stop the
+// accounting.
+func (p *Printer) AccountJobs(ctx context.Context, interval time.Duration, m *CostModel, sink CostSink, onError func(r *CostRecord, err error)) error {
+	events, err := p.WatchJobs(ctx, interval)
+	if err != nil {
+		return err
+	}
+	for e := range events {
+		if outcome, ok := jobOutcome(e); !ok || outcome != JobCompleted {
+			continue
+		}
+		r := m.Cost(p.name, e.Job)
+		if err := sink.Record(r); err != nil && onError != nil {
+			onError(r, err)
+		}
+	}
+	return ctx.Err()
+}
+
+// CostTotal is the total cost of the jobs of a user or department.
+type CostTotal struct {
+	Name  string
+	Jobs  int
+	Pages int64
+	Cost  int64
+}
+
+// CostLedger is a CostSink summing the costs per user and per
+// department in memory, such as for a monthly report.
+type CostLedger struct {
+	mu          sync.Mutex
+	users, dpts map[string]*CostTotal
+}
+
+func (l *CostLedger) Record(r *CostRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.users == nil {
+		l.users = make(map[string]*CostTotal)
+		l.dpts = make(map[string]*CostTotal)
+	}
+	addCost(l.users, r.User, r)
+	if r.Department != "" {
+		addCost(l.dpts, r.Department, r)
+	}
+	return nil
+}
+
+func addCost(totals map[string]*CostTotal, name string, r *CostRecord) {
+	t := totals[name]
+	if t == nil {
+		t = &CostTotal{Name: name}
+		totals[name] = t
+	}
+	t.Jobs++
+	t.Pages += int64(r.Pages)
+	t.Cost += r.Cost
+}
+
+// Users returns the totals per user, in order of name.
+func (l *CostLedger) Users() []CostTotal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return sortedTotals(l.users)
+}
+
+// Departments returns the totals per department, in order of name.
+func (l *CostLedger) Departments() []CostTotal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return sortedTotals(l.dpts)
+}
+
+// Reset clears the totals, such as at the start of a period.
+func (l *CostLedger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.users, l.dpts = nil, nil
+}
+
+func sortedTotals(totals map[string]*CostTotal) []CostTotal {
+	s := make([]CostTotal, 0, len(totals))
+	for _, t := range totals {
+		s = append(s, *t)
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Name < s[j].Name })
+	return s
+}
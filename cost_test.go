@@ -0,0 +1,55 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCostModel(t *testing.T) {
+	m := &CostModel{
+		Default:  CostRate{PerPage: 5},
+		Printers: map[string]CostRate{"Color Laser": {PerJob: 10, PerPage: 20}},
+		Department: func(user string) string {
+			if user == "ann" || user == "bob" {
+				return "sales"
+			}
+			return ""
+		},
+	}
+	r := m.Cost("color laser", JobInfo{JobID: 3, UserName: "ann", DocumentName: "offer", TotalPages: 4, PagesPrinted: 3})
+	if r.Pages != 3 || r.Cost != 70 || r.Department != "sales" || r.JobID != 3 || r.Document != "offer" {
+		t.Errorf("got %+v", r)
+	}
+	r = m.Cost("Receipts", JobInfo{UserName: "eve", TotalPages: 2})
+	if r.Pages != 2 || r.Cost != 10 || r.Department != "" {
+		t.Errorf("got %+v", r)
+	}
+}
+
+func TestCostLedger(t *testing.T) {
+	var l CostLedger
+	for _, r := range []*CostRecord{
+		{User: "bob", Department: "sales", Pages: 2, Cost: 10},
+		{User: "ann", Department: "sales", Pages: 1, Cost: 5},
+		{User: "bob", Department: "sales", Pages: 3, Cost: 15},
+		{User: "eve", Pages: 1, Cost: 5},
+	} {
+		l.Record(r)
+	}
+	users := []CostTotal{{"ann", 1, 1, 5}, {"bob", 2, 5, 25}, {"eve", 1, 1, 5}}
+	if got := l.Users(); !reflect.DeepEqual(got, users) {
+		t.Errorf("users: got %v, want %v", got, users)
+	}
+	dpts := []CostTotal{{"sales", 3, 6, 30}}
+	if got := l.Departments(); !reflect.DeepEqual(got, dpts) {
+		t.Errorf("departments: got %v, want %v", got, dpts)
+	}
+	l.Reset()
+	if len(l.Users()) != 0 {
+		t.Error("totals not reset")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"sort"
+	"unicode"
+)
+
+// CurrencyFallbacks spell out the currency symbols that none of the code
+// pages of a printer has, such as '₺' missing from PC437, PC850, PC858
+// and WPC1254 alike.
+var CurrencyFallbacks = map[rune]string{
+	'€': "EUR",
+	'£': "GBP",
+	'¥': "JPY",
+	'¢': "c",
+	'₺': "TL",
+	'₹': "Rs",
+	'₽': "RUB",
+	'₩': "KRW",
+	'₪': "ILS",
+	'₫': "VND",
+	'₴': "UAH",
+	'₱': "PHP",
+	'₦': "NGN",
+	'₿': "BTC",
+}
+
+// currencyCodePage returns a code page of the profile of p that has the
+// currency symbol r, and the code of r in it.
+func (p *Printer) currencyCodePage(r rune) (uint8, byte, bool) {
+	if !unicode.Is(unicode.Sc, r) {
+		return 0, 0, false
+	}
+	for _, n := range p.profile().codePages() {
+		if cp, ok := codePages[n]; ok {
+			if c, ok := cp.EncodeRune(r); ok {
+				return n, c, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// codePages returns the code pages of pr, in order of preference.
+func (pr *Profile) codePages() []uint8 {
+	if pr.CodePages != nil {
+		return pr.CodePages
+	}
+	all := make([]uint8, 0, len(codePages))
+	for n := range codePages {
+		all = append(all, n)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	return all
+}
@@ -0,0 +1,67 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestCurrencySymbols(t *testing.T) {
+	// code of each symbol in PC437, PC850, PC858 and WPC1254, 0 if none
+	pages := []uint8{0, 2, 19, 48}
+	tests := []struct {
+		symbol rune
+		codes  [4]byte
+	}{
+		{'$', [4]byte{0x24, 0x24, 0x24, 0x24}},
+		{'£', [4]byte{0x9C, 0x9C, 0x9C, 0xA3}},
+		{'€', [4]byte{0, 0, 0xD5, 0x80}},
+		{'¥', [4]byte{0x9D, 0xBE, 0xBE, 0xA5}},
+		{'¢', [4]byte{0x9B, 0xBD, 0xBD, 0xA2}},
+		{'₺', [4]byte{0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		for i, n := range pages {
+			c, ok := codePages[n].EncodeRune(tt.symbol)
+			if !ok {
+				c = 0
+			}
+			if c != tt.codes[i] {
+				t.Errorf("%c in code page %d: got %#x, want %#x", tt.symbol, n, c, tt.codes[i])
+			}
+		}
+	}
+}
+
+func TestWriteCurrency(t *testing.T) {
+	tests := []struct {
+		codePage  uint8
+		codePages []uint8
+		text      string
+		want      string
+	}{
+		{0, nil, "£9.50", "\x9C9.50"},
+		{19, nil, "5,00 €", "5,00 \xD5"},
+		// € switches from PC437 to PC858 and back
+		{0, []uint8{0, 2, 19}, "€5", "\x1Bt\x13\xD5\x1Bt\x005"},
+		// no code page of the profile has €
+		{0, []uint8{0, 2}, "€5", "EUR5"},
+		{48, nil, "₺12,50", "TL12,50"},
+		{0, nil, "ä→", "\x84?"},
+		// already encoded
+		{0, nil, "\x9C1", "\x9C1"},
+	}
+	for _, tt := range tests {
+		ft := new(fakeTransport)
+		p := NewPrinter("fake", ft)
+		p.Profile = &Profile{CodePages: tt.codePages}
+		if err := p.SetCodePage(tt.codePage); err != nil {
+			t.Fatal(err)
+		}
+		ft.Reset()
+		p.WriteString(tt.text)
+		if got := ft.String(); got != tt.want {
+			t.Errorf("%q in code page %d: wrote %q, want %q", tt.text, tt.codePage, got, tt.want)
+		}
+	}
+}
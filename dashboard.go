@@ -0,0 +1,355 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	PRINTER_STATUS_PAUSED            = 0x00000001
+	PRINTER_STATUS_ERROR             = 0x00000002
+	PRINTER_STATUS_PENDING_DELETION  = 0x00000004
+	PRINTER_STATUS_PAPER_JAM         = 0x00000008
+	PRINTER_STATUS_PAPER_OUT         = 0x00000010
+	PRINTER_STATUS_MANUAL_FEED       = 0x00000020
+	PRINTER_STATUS_PAPER_PROBLEM     = 0x00000040
+	PRINTER_STATUS_OFFLINE           = 0x00000080
+	PRINTER_STATUS_IO_ACTIVE         = 0x00000100
+	PRINTER_STATUS_BUSY              = 0x00000200
+	PRINTER_STATUS_PRINTING          = 0x00000400
+	PRINTER_STATUS_OUTPUT_BIN_FULL   = 0x00000800
+	PRINTER_STATUS_NOT_AVAILABLE     = 0x00001000
+	PRINTER_STATUS_WAITING           = 0x00002000
+	PRINTER_STATUS_PROCESSING        = 0x00004000
+	PRINTER_STATUS_INITIALIZING      = 0x00008000
+	PRINTER_STATUS_WARMING_UP        = 0x00010000
+	PRINTER_STATUS_TONER_LOW         = 0x00020000
+	PRINTER_STATUS_NO_TONER          = 0x00040000
+	PRINTER_STATUS_PAGE_PUNT         = 0x00080000
+	PRINTER_STATUS_USER_INTERVENTION = 0x00100000
+	PRINTER_STATUS_OUT_OF_MEMORY     = 0x00200000
+	PRINTER_STATUS_DOOR_OPEN         = 0x00400000
+	PRINTER_STATUS_SERVER_UNKNOWN    = 0x00800000
+	PRINTER_STATUS_POWER_SAVE        = 0x01000000
+)
+
+var printerStatusNames = []struct {
+	flag uint32
+	name string
+}{
+	{PRINTER_STATUS_PAUSED, "paused"},
+	{PRINTER_STATUS_ERROR, "error"},
+	{PRINTER_STATUS_PENDING_DELETION, "pending deletion"},
+	{PRINTER_STATUS_PAPER_JAM, "paper jam"},
+	{PRINTER_STATUS_PAPER_OUT, "paper out"},
+	{PRINTER_STATUS_MANUAL_FEED, "manual feed"},
+	{PRINTER_STATUS_PAPER_PROBLEM, "paper problem"},
+	{PRINTER_STATUS_OFFLINE, "offline"},
+	{PRINTER_STATUS_IO_ACTIVE, "I/O active"},
+	{PRINTER_STATUS_BUSY, "busy"},
+	{PRINTER_STATUS_PRINTING, "printing"},
+	{PRINTER_STATUS_OUTPUT_BIN_FULL, "output bin full"},
+	{PRINTER_STATUS_NOT_AVAILABLE, "not available"},
+	{PRINTER_STATUS_WAITING, "waiting"},
+	{PRINTER_STATUS_PROCESSING, "processing"},
+	{PRINTER_STATUS_INITIALIZING, "initializing"},
+	{PRINTER_STATUS_WARMING_UP, "warming up"},
+	{PRINTER_STATUS_TONER_LOW, "toner low"},
+	{PRINTER_STATUS_NO_TONER, "no toner"},
+	{PRINTER_STATUS_PAGE_PUNT, "page punt"},
+	{PRINTER_STATUS_USER_INTERVENTION, "user intervention"},
+	{PRINTER_STATUS_OUT_OF_MEMORY, "out of memory"},
+	{PRINTER_STATUS_DOOR_OPEN, "door open"},
+	{PRINTER_STATUS_SERVER_UNKNOWN, "server unknown"},
+	{PRINTER_STATUS_POWER_SAVE, "power save"},
+}
+
+// PrinterStatusText describes the PRINTER_STATUS_ flags status, such as
+// "offline, paper out", or returns "ready" if none is set.
+func PrinterStatusText(status uint32) string {
+	var s []string
+	for _, n := range printerStatusNames {
+		if status&n.flag != 0 {
+			s = append(s, n.name)
+		}
+	}
+	if len(s) == 0 {
+		return "ready"
+	}
+	return strings.Join(s, ", ")
+}
+
+// Dashboard is an http.Handler serving a page showing the printers, their
+// status and queue with buttons to print the queued jobs again, and the
+// recent jobs. It can be mounted under any path, for example
+//
+//	d := printer.NewDashboard()
+//	go d.Run(ctx)
+//	http.Handle("/printers/", http.StripPrefix("/printers", d))
+//
+// Besides the page it serves status.json, the data shown on the page, and
+// accepts POST requests to restart a queued job with the printer and job
+// form values. Jobs that left the queue cannot be printed again, the
+// spooler deleted their data. The handler has no access control of its
+// own.
+type Dashboard struct {
+	// Pattern, if set, keeps only the printers whose name matches it, as
+	// EnumOptions.Pattern.
+	Pattern string
+	// Interval is how often Run polls the print queues and the page
+	// refreshes. Zero means 2 seconds.
+	Interval time.Duration
+	// History is the number of recent jobs kept per printer. Zero means
+	// 20.
+	History int
+
+	mu       sync.Mutex
+	recent   map[string][]DashboardJob // newest first
+	watching map[string]bool
+
+	// restart restarts a job, nil means restartJob
+	restart func(name string, id uint32) error
+}
+
+// NewDashboard returns a Dashboard showing all printers.
+func NewDashboard() *Dashboard {
+	return &Dashboard{}
+}
+
+// DashboardPrinter is a printer shown by a Dashboard.
+type DashboardPrinter struct {
+	Name   string
+	Port   string
+	Status string
+	Jobs   []JobInfo
+	Recent []DashboardJob
+	Error  string `json:",omitempty"`
+}
+
+// DashboardJob is a job that left the queue of a printer.
+type DashboardJob struct {
+	Job     JobInfo
+	Outcome JobOutcome
+	Time    time.Time
+}
+
+func (d *Dashboard) interval() time.Duration {
+	if d.Interval <= 0 {
+		return 2 * time.Second
+	}
+	return d.Interval
+}
+
+// Run watches the queues of the printers, recording the recent jobs shown
+// by the dashboard, until ctx is done. Printers installed while it runs
+// are picked up.
+func (d *Dashboard) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval())
+	defer ticker.Stop()
+	for {
+		printers, err := Enumerate(EnumOptions{Pattern: d.Pattern})
+		if err == nil {
+			for _, pi := range printers {
+				d.watch(ctx, pi.Name)
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// watch records the jobs of the printer name in the background, unless
+// they are already being recorded.
+func (d *Dashboard) watch(ctx context.Context, name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.watching[name] {
+		return
+	}
+	p, err := Open(name)
+	if err != nil {
+		return
+	}
+	events, err := p.WatchJobs(ctx, d.interval())
+	if err != nil {
+		p.Close()
+		return
+	}
+	if d.watching == nil {
+		d.watching = make(map[string]bool)
+		d.recent = make(map[string][]DashboardJob)
+	}
+	d.watching[name] = true
+	go func() {
+		defer p.Close()
+		for e := range events {
+			if e.Kind == JobRemoved {
+				outcome, _ := jobOutcome(e)
+				d.record(name, DashboardJob{Job: e.Job, Outcome: outcome, Time: time.Now()})
+			}
+		}
+		// retried on the next poll of Run
+		d.mu.Lock()
+		delete(d.watching, name)
+		d.mu.Unlock()
+	}()
+}
+
+func (d *Dashboard) record(name string, j DashboardJob) {
+	history := d.History
+	if history <= 0 {
+		history = 20
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	recent := append([]DashboardJob{j}, d.recent[name]...)
+	if len(recent) > history {
+		recent = recent[:history]
+	}
+	d.recent[name] = recent
+}
+
+// Printers returns the printers shown by the dashboard.
+func (d *Dashboard) Printers() ([]DashboardPrinter, error) {
+	printers, err := Enumerate(EnumOptions{Level: 2, Pattern: d.Pattern})
+	if err != nil {
+		return nil, err
+	}
+	dps := make([]DashboardPrinter, len(printers))
+	for i, pi := range printers {
+		dp := DashboardPrinter{
+			Name:   pi.Name,
+			Port:   pi.PortName,
+			Status: PrinterStatusText(pi.Status),
+		}
+		if p, err := Open(pi.Name); err != nil {
+			dp.Error = err.Error()
+		} else {
+			if dp.Jobs, err = p.Jobs(); err != nil {
+				dp.Error = err.Error()
+			}
+			p.Close()
+		}
+		d.mu.Lock()
+		dp.Recent = append([]DashboardJob(nil), d.recent[pi.Name]...)
+		d.mu.Unlock()
+		dps[i] = dp
+	}
+	return dps, nil
+}
+
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "POST":
+		d.reprint(w, r)
+	case strings.HasSuffix(r.URL.Path, "/status.json"):
+		printers, err := d.Printers()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(printers)
+	default:
+		printers, err := d.Printers()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		dashboardTemplate.Execute(w, map[string]interface{}{
+			"Refresh":  int(d.interval().Seconds() + 0.5),
+			"Printers": printers,
+		})
+	}
+}
+
+// reprint restarts the job given by the printer and job form values and
+// redirects back to the page.
+func (d *Dashboard) reprint(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("printer")
+	id, err := strconv.ParseUint(r.FormValue("job"), 10, 32)
+	if name == "" || err != nil {
+		http.Error(w, "missing printer or job", http.StatusBadRequest)
+		return
+	}
+	restart := d.restart
+	if restart == nil {
+		restart = restartJob
+	}
+	if err := restart(name, uint32(id)); err != nil {
+		http.Error(w, "cannot reprint: "+err.Error(), http.StatusConflict)
+		return
+	}
+	// r.URL.Path lacks the prefix removed by http.StripPrefix, the
+	// request URI is the page as the browser sees it
+	http.Redirect(w, r, r.RequestURI, http.StatusSeeOther)
+}
+
+// restartJob restarts the job id of the printer name.
+func restartJob(name string, id uint32) error {
+	p, err := Open(name)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	return p.RestartJob(id)
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Local().Format("15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>Printers</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border-bottom: 1px solid #ddd; padding: 2px 8px; text-align: left; }
+.status { color: #666; }
+.failed, .cancelled, .error { color: #b00; }
+</style>
+</head>
+<body>
+{{range .Printers}}
+<h2>{{.Name}} <span class="status">{{.Port}}, {{.Status}}, {{len .Jobs}} queued</span></h2>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{$printer := .Name}}
+{{if .Jobs}}
+<table>
+<tr><th>Job</th><th>Document</th><th>User</th><th>Status</th><th>Pages</th><th>Progress</th><th>Submitted</th><th></th></tr>
+{{range .Jobs}}
+<tr><td>{{.JobID}}</td><td>{{.DocumentName}}</td><td>{{.UserName}}</td><td>{{.Status}}</td><td>{{.PagesPrinted}}/{{.TotalPages}}</td><td><progress max="100" value="{{printf "%.0f" .Progress}}"></progress></td><td>{{time .Submitted}}</td>
+<td><form method="post"><input type="hidden" name="printer" value="{{$printer}}"><input type="hidden" name="job" value="{{.JobID}}"><button>Reprint</button></form></td></tr>
+{{end}}
+</table>
+{{end}}
+{{if .Recent}}
+<table>
+<tr><th>Job</th><th>Document</th><th>User</th><th>Outcome</th><th>Finished</th></tr>
+{{range .Recent}}
+<tr><td>{{.Job.JobID}}</td><td>{{.Job.DocumentName}}</td><td>{{.Job.UserName}}</td><td class="{{.Outcome}}">{{.Outcome}}</td><td>{{time .Time}}</td></tr>
+{{end}}
+</table>
+{{end}}
+{{else}}
+<p>No printers.</p>
+{{end}}
+</body>
+</html>
+`))
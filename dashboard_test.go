@@ -0,0 +1,90 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrinterStatusText(t *testing.T) {
+	tests := []struct {
+		status uint32
+		want   string
+	}{
+		{0, "ready"},
+		{PRINTER_STATUS_OFFLINE, "offline"},
+		{PRINTER_STATUS_PAPER_OUT | PRINTER_STATUS_OFFLINE, "paper out, offline"},
+	}
+	for _, tt := range tests {
+		if got := PrinterStatusText(tt.status); got != tt.want {
+			t.Errorf("PrinterStatusText(%#x) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestDashboardTemplate(t *testing.T) {
+	var b bytes.Buffer
+	err := dashboardTemplate.Execute(&b, map[string]interface{}{
+		"Refresh": 2,
+		"Printers": []DashboardPrinter{{
+			Name:   "Kitchen <1>",
+			Port:   "USB001",
+			Status: "ready",
+			Jobs:   []JobInfo{{JobID: 4, DocumentName: "Order 12", Submitted: time.Now()}},
+			Recent: []DashboardJob{{Job: JobInfo{JobID: 3}, Outcome: JobFailed, Time: time.Now()}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, s := range []string{
+		`content="2"`,
+		`Kitchen &lt;1&gt;`,
+		`1 queued`,
+		`Order 12`,
+		`class="failed"`,
+		`name="job" value="4"`,
+	} {
+		if !strings.Contains(page, s) {
+			t.Errorf("page does not contain %s", s)
+		}
+	}
+	// the data of the jobs that left the queue is gone
+	if strings.Contains(page, `name="job" value="3"`) {
+		t.Error("page offers to reprint a job that left the queue")
+	}
+}
+
+func TestDashboardReprintRedirect(t *testing.T) {
+	var restarted []string
+	d := NewDashboard()
+	d.restart = func(name string, id uint32) error {
+		restarted = append(restarted, fmt.Sprint(name, " ", id))
+		return nil
+	}
+	h := http.StripPrefix("/printers", d)
+	form := url.Values{"printer": {"Kitchen"}, "job": {"4"}}
+	r := httptest.NewRequest("POST", "/printers/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status %d, want %d", w.Code, http.StatusSeeOther)
+	}
+	if got := w.Header().Get("Location"); got != "/printers/" {
+		t.Errorf("redirected to %q, want %q", got, "/printers/")
+	}
+	if len(restarted) != 1 || restarted[0] != "Kitchen 4" {
+		t.Errorf("restarted %v, want [Kitchen 4]", restarted)
+	}
+}
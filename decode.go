@@ -0,0 +1,502 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// DecodeESCPOS rebuilds a receipt from the ESC/POS commands in data, such
+// as a captured print job, so it can be previewed. Text, formatting,
+// feeds, cuts, drawer pulses, barcodes, QR codes and raster images are
+// decoded; other commands are skipped.
+func DecodeESCPOS(data []byte) (*Receipt, error) {
+	d := decoder{data: data, r: NewReceipt(""), cp: charmap.CodePage437}
+	d.reset()
+	if err := d.decode(); err != nil {
+		return nil, err
+	}
+	d.flush(false)
+	if d.upsideDown {
+		// printed last block first, see Receipt.UpsideDown
+		n := len(d.r.blocks)
+		for n > 0 && (d.r.blocks[n-1].kind == receiptFeed || d.r.blocks[n-1].kind == receiptCut || d.r.blocks[n-1].kind == receiptPulse) {
+			n--
+		}
+		d.r.blocks = append(reverseBlocks(d.r.blocks[:n]), d.r.blocks[n:]...)
+		d.r.UpsideDown = true
+	}
+	return d.r, nil
+}
+
+// ESCPOSPages returns the number of pages of the raw ESC/POS data data:
+// the pages ended by form feeds, a final form feed starting no page.
+// Unlike a plain count of 0x0C bytes, form feeds in the parameters of
+// commands, such as raster images and barcode data, are ignored. Data that
+// cannot be decoded counts as a single page.
+func ESCPOSPages(data []byte) int {
+	d := decoder{data: data, r: NewReceipt(""), cp: charmap.CodePage437}
+	d.reset()
+	if err := d.decode(); err != nil {
+		return 1
+	}
+	n := d.formFeeds
+	if d.lastFormFeed != len(data)-1 {
+		n++
+	}
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+	r    *Receipt
+	cp   *charmap.Charmap
+
+	line       []byte
+	upsideDown bool
+
+	// for ESCPOSPages
+	formFeeds    int
+	lastFormFeed int // offset of the last form feed
+
+	// for RedactESCPOS
+	cmd    int // offset of the command being decoded
+	lineNo int // number of the line being decoded
+	spans  []textSpan
+	params []paramSpan
+
+	// QR code
+	qrData     string
+	qrSize     uint8
+	qrEC       uint8
+	barcodeOpt BarcodeOptions
+}
+
+func (d *decoder) reset() {
+	d.r.style = Style{Width: 1, Height: 1}
+	d.qrSize, d.qrEC = 3, QRCodeErrorCorrectionLevelL
+	d.barcodeOpt = BarcodeOptions{}
+}
+
+// next returns the next n bytes.
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, fmt.Errorf("printer: truncated ESC/POS command at offset %d", d.pos)
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) byte1() (byte, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// flush adds the pending line as a text block. An empty line is added as
+// a feed if empty is set.
+func (d *decoder) flush(empty bool) {
+	d.lineNo++
+	if len(d.line) == 0 {
+		if empty {
+			d.feed(1)
+		}
+		return
+	}
+	s, _ := d.cp.NewDecoder().Bytes(d.line)
+	d.line = d.line[:0]
+	d.r.Text(string(s))
+}
+
+// feed adds n empty lines, merged with a preceding feed.
+func (d *decoder) feed(n int) {
+	if n <= 0 {
+		return
+	}
+	if k := len(d.r.blocks); k > 0 && d.r.blocks[k-1].kind == receiptFeed {
+		d.r.blocks[k-1].lines += n
+		return
+	}
+	d.r.Feed(n)
+}
+
+// toggle returns the state set by the parameter of toggles such as ESC E,
+// which accept 0 and 1 as well as '0' and '1'.
+func toggle(n byte) bool {
+	return n&1 == 1
+}
+
+func (d *decoder) decode() error {
+	for d.pos < len(d.data) {
+		d.cmd = d.pos
+		c := d.data[d.pos]
+		d.pos++
+		var err error
+		switch c {
+		case '\n':
+			d.flush(true)
+		case '\r':
+		case '\f':
+			d.flush(false)
+			d.formFeeds++
+			d.lastFormFeed = d.cmd
+		case '\t':
+			n := len(d.line)
+			d.line = append(d.line, strings.Repeat(" ", 8-n%8)...)
+		case esc:
+			err = d.escCommand()
+		case gs:
+			err = d.gsCommand()
+		case fs:
+			err = d.fsCommand()
+		case DLE:
+			err = d.dleCommand()
+		default:
+			if c >= 0x20 {
+				d.line = append(d.line, c)
+				d.addText()
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skip skips n parameter bytes.
+func (d *decoder) skip(n int) error {
+	_, err := d.next(n)
+	return err
+}
+
+// skipBlock skips a block of parameters preceded by its 16 bit length.
+func (d *decoder) skipBlock() ([]byte, error) {
+	l, err := d.next(2)
+	if err != nil {
+		return nil, err
+	}
+	return d.next(int(l[0]) | int(l[1])<<8)
+}
+
+func (d *decoder) escCommand() error {
+	c, err := d.byte1()
+	if err != nil {
+		return err
+	}
+	s := &d.r.style
+	switch c {
+	case '@':
+		d.flush(false)
+		d.reset()
+		d.cp = charmap.CodePage437
+	case 'M', 'E', 'G', '-', 'a', '{', 'V', 't', '!', 'd':
+		n, err := d.byte1()
+		if err != nil {
+			return err
+		}
+		switch c {
+		case 'M':
+			if n >= '0' && n <= '4' {
+				n -= '0'
+			}
+			s.Font = Font(n)
+		case 'E', 'G':
+			s.Emphasize = toggle(n)
+		case '-':
+			if n >= '0' {
+				n -= '0'
+			}
+			s.Underline = n
+		case 'a':
+			if n >= '0' {
+				n -= '0'
+			}
+			s.Align = Align(n % 3)
+		case '{':
+			// the blocks are reversed once the whole receipt is decoded
+			d.upsideDown = d.upsideDown || toggle(n)
+		case 'V':
+			s.Rotate = toggle(n)
+		case 't':
+			if cp, ok := codePages[n]; ok {
+				d.cp = cp
+			}
+		case '!':
+			s.Font = Font(n & 1)
+			s.Emphasize = n&0x08 != 0
+			s.Height, s.Width = 1, 1
+			if n&0x10 != 0 {
+				s.Height = 2
+			}
+			if n&0x20 != 0 {
+				s.Width = 2
+			}
+			s.Underline = n >> 7
+		case 'd':
+			printed := len(d.line) > 0
+			d.flush(false)
+			if printed && n > 0 {
+				n--
+			}
+			d.feed(int(n))
+		}
+	case 'p':
+		d.flush(false)
+		d.r.Pulse()
+		return d.skip(3)
+	case 'i', 'm':
+		d.flush(false)
+		d.r.Cut()
+	case 'J', 'R', ' ', '3', 'U', 'T', 'r', '%', '?', '=', 'K':
+		return d.skip(1)
+	case '$', '\\', 'B':
+		return d.skip(2)
+	case 'c':
+		return d.skip(2)
+	case 'W':
+		return d.skip(8)
+	case 'D':
+		// tab positions, terminated by NUL
+		for {
+			b, err := d.byte1()
+			if err != nil || b == 0 {
+				return err
+			}
+		}
+	case '*':
+		p, err := d.next(3)
+		if err != nil {
+			return err
+		}
+		n := int(p[1]) | int(p[2])<<8
+		if p[0] >= 32 {
+			n *= 3
+		}
+		return d.skip(n)
+	case '(':
+		if _, err := d.byte1(); err != nil {
+			return err
+		}
+		_, err := d.skipBlock()
+		return err
+	}
+	return nil
+}
+
+func (d *decoder) gsCommand() error {
+	c, err := d.byte1()
+	if err != nil {
+		return err
+	}
+	s := &d.r.style
+	switch c {
+	case '!':
+		n, err := d.byte1()
+		if err != nil {
+			return err
+		}
+		s.Width, s.Height = n>>4+1, n&0x0F+1
+	case 'B':
+		n, err := d.byte1()
+		if err != nil {
+			return err
+		}
+		s.Reverse = toggle(n)
+	case 'h', 'w', 'H', 'f':
+		n, err := d.byte1()
+		if err != nil {
+			return err
+		}
+		switch c {
+		case 'h':
+			d.barcodeOpt.Height = n
+		case 'w':
+			d.barcodeOpt.Width = n
+		case 'H':
+			if n >= '0' {
+				n -= '0'
+			}
+			d.barcodeOpt.HRI, d.barcodeOpt.SetHRI = HRIPosition(n), true
+		case 'f':
+			d.barcodeOpt.HRIFont = Font(n)
+		}
+	case 'V':
+		m, err := d.byte1()
+		if err != nil {
+			return err
+		}
+		if m >= 65 {
+			if err := d.skip(1); err != nil {
+				return err
+			}
+		}
+		d.flush(false)
+		d.r.Cut()
+	case 'k':
+		return d.barcode()
+	case 'v':
+		return d.rasterImage()
+	case '(':
+		fn, err := d.byte1()
+		if err != nil {
+			return err
+		}
+		b, err := d.skipBlock()
+		if err != nil {
+			return err
+		}
+		if fn == 'k' && len(b) >= 3 && b[0] == 49 {
+			if b[1] == 80 {
+				// GS ( k pL pH 49 80 48 d1...dk
+				start := d.pos - len(b)
+				d.params = append(d.params, paramSpan{start: start + 3, end: d.pos, lenAt: start - 2, lenSize: 2, lenExtra: 3})
+			}
+			d.qrCode(b[1], b[2:])
+		}
+	case '8':
+		// GS 8 L p1 p2 p3 p4
+		p, err := d.next(5)
+		if err != nil {
+			return err
+		}
+		return d.skip(int(p[1]) | int(p[2])<<8 | int(p[3])<<16 | int(p[4])<<24)
+	case '*':
+		p, err := d.next(2)
+		if err != nil {
+			return err
+		}
+		return d.skip(int(p[0]) * int(p[1]) * 8)
+	case 'L', 'W', '$', 'P', 'x':
+		return d.skip(2)
+	default:
+		return d.skip(1)
+	}
+	return nil
+}
+
+// qrCode handles GS ( k function fn of QR codes.
+func (d *decoder) qrCode(fn byte, p []byte) {
+	switch fn {
+	case 67:
+		d.qrSize = p[0]
+	case 69:
+		d.qrEC = p[0]
+	case 80:
+		if len(p) > 1 {
+			d.qrData = string(p[1:])
+		}
+	case 81:
+		d.flush(false)
+		d.r.QRCode(d.qrData, d.qrSize, d.qrEC)
+	}
+}
+
+// barcode handles GS k.
+func (d *decoder) barcode() error {
+	m, err := d.byte1()
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if m <= 6 {
+		// function A, terminated by NUL
+		start := d.pos
+		for d.pos < len(d.data) && d.data[d.pos] != 0 {
+			d.pos++
+		}
+		data = d.data[start:d.pos]
+		d.params = append(d.params, paramSpan{start: start, end: d.pos, lenAt: -1})
+		d.pos++
+		m += 65
+	} else {
+		n, err := d.byte1()
+		if err != nil {
+			return err
+		}
+		if data, err = d.next(int(n)); err != nil {
+			return err
+		}
+		d.params = append(d.params, paramSpan{start: d.pos - int(n), end: d.pos, lenAt: d.pos - int(n) - 1, lenSize: 1})
+	}
+	d.flush(false)
+	d.r.SetBarcodeOptions(d.barcodeOpt)
+	d.r.Barcode(string(data), BarcodeType(m))
+	return nil
+}
+
+// rasterImage handles GS v 0.
+func (d *decoder) rasterImage() error {
+	p, err := d.next(6)
+	if err != nil {
+		return err
+	}
+	x := int(p[2]) | int(p[3])<<8
+	y := int(p[4]) | int(p[5])<<8
+	bits, err := d.next(x * y)
+	if err != nil {
+		return err
+	}
+	img := image.NewGray(image.Rect(0, 0, 8*x, y))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	for row := 0; row < y; row++ {
+		for col := 0; col < 8*x; col++ {
+			if bits[row*x+col/8]&(0x80>>uint(col%8)) != 0 {
+				img.SetGray(col, row, color.Gray{})
+			}
+		}
+	}
+	d.flush(false)
+	d.r.Image(img)
+	return nil
+}
+
+func (d *decoder) fsCommand() error {
+	c, err := d.byte1()
+	if err != nil {
+		return err
+	}
+	switch c {
+	case '!', 'C', '-', 'W':
+		return d.skip(1)
+	case 'S', 'p':
+		return d.skip(2)
+	case '(':
+		if _, err := d.byte1(); err != nil {
+			return err
+		}
+		_, err := d.skipBlock()
+		return err
+	}
+	return nil
+}
+
+func (d *decoder) dleCommand() error {
+	c, err := d.byte1()
+	if err != nil {
+		return err
+	}
+	switch c {
+	case EOT, 0x05:
+		return d.skip(1)
+	case 0x14:
+		return d.skip(3)
+	}
+	return nil
+}
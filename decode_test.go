@@ -0,0 +1,125 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"testing"
+)
+
+func TestDecodeESCPOS(t *testing.T) {
+	data := "\x1B@\x1Ba\x01\x1BE\x01Shop\n\x1BE\x00\x1Ba\x00\x1D!\x11Big\n\x1D!\x00\n\n" +
+		"\x1Bt\x10caf\xE9\x1Bd\x03" +
+		"\x1D(k\x04\x001A2\x00\x1D(k\x03\x001C\x05\x1D(k\x03\x001E0\x1D(k\x07\x001P0abcd\x1D(k\x03\x001Q0" +
+		"\x1Dh\x50\x1DkE\x03ABC" +
+		"\x1Dv0\x00\x01\x00\x02\x00\x80\x01" +
+		"\x1Bp\x00\x0A\xFF\x1DVA\x03"
+	r, err := DecodeESCPOS([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		kind receiptBlockKind
+		text string
+	}{
+		{receiptText, "Shop"},
+		{receiptText, "Big"},
+		{receiptFeed, ""},
+		{receiptText, "café"},
+		{receiptFeed, ""},
+		{receiptQRCode, "abcd"},
+		{receiptBarcode, "ABC"},
+		{receiptImage, ""},
+		{receiptPulse, ""},
+		{receiptCut, ""},
+	}
+	if len(r.blocks) != len(want) {
+		t.Fatalf("got %d blocks, want %d: %+v", len(r.blocks), len(want), r.blocks)
+	}
+	for i, w := range want {
+		b := r.blocks[i]
+		if b.kind != w.kind || b.text != w.text {
+			t.Errorf("block %d = %v %q, want %v %q", i, b.kind, b.text, w.kind, w.text)
+		}
+	}
+	if s := r.blocks[0].style; s.Align != AlignCenter || !s.Emphasize {
+		t.Errorf("block 0 style = %+v", s)
+	}
+	if s := r.blocks[1].style; s.Width != 2 || s.Height != 2 || s.Emphasize {
+		t.Errorf("block 1 style = %+v", s)
+	}
+	if n := r.blocks[2].lines; n != 2 {
+		t.Errorf("feed = %d lines, want 2", n)
+	}
+	if n := r.blocks[4].lines; n != 2 {
+		t.Errorf("ESC d 3 after text = %d lines, want 2", n)
+	}
+	if b := r.blocks[5]; b.size != 5 || b.ec != QRCodeErrorCorrectionLevelL {
+		t.Errorf("QR code size %d ec %d", b.size, b.ec)
+	}
+	if b := r.blocks[6]; b.barcode != BarcodeCODE39 || b.barcodeOpts.Height != 0x50 {
+		t.Errorf("barcode %v options %+v", b.barcode, b.barcodeOpts)
+	}
+	img := r.blocks[7].img
+	if b := img.Bounds(); b.Dx() != 8 || b.Dy() != 2 {
+		t.Errorf("image bounds %v", b)
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r != 0 {
+		t.Errorf("pixel 0,0 is not black")
+	}
+	if r, _, _, _ := img.At(7, 0).RGBA(); r == 0 {
+		t.Errorf("pixel 7,0 is black")
+	}
+	if r, _, _, _ := img.At(7, 1).RGBA(); r != 0 {
+		t.Errorf("pixel 7,1 is not black")
+	}
+}
+
+func TestDecodeESCPOSUpsideDown(t *testing.T) {
+	r, err := DecodeESCPOS([]byte("\x1B{\x01two\none\n\x1B{\x00\x1DV\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.UpsideDown || len(r.blocks) != 3 || r.blocks[0].text != "one" || r.blocks[1].text != "two" || r.blocks[2].kind != receiptCut {
+		t.Errorf("got %+v", r.blocks)
+	}
+}
+
+func TestDecodeESCPOSTruncated(t *testing.T) {
+	if _, err := DecodeESCPOS([]byte("abc\x1Dv0\x00\x10\x00")); err == nil {
+		t.Error("truncated raster image decoded without error")
+	}
+}
+
+func TestDecodeESCPOSFeedZeroAfterText(t *testing.T) {
+	r, err := DecodeESCPOS([]byte("abc\x1Bd\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.blocks) != 1 || r.blocks[0].text != "abc" {
+		t.Errorf("got %+v", r.blocks)
+	}
+}
+
+func TestESCPOSPages(t *testing.T) {
+	for _, tt := range []struct {
+		data  string
+		pages int
+	}{
+		{"", 1},
+		{"receipt", 1},
+		{"receipt\f", 1},
+		{"one\ftwo", 2},
+		{"one\ftwo\fthree\f", 3},
+		// form feeds in command parameters
+		{"\x1Dh\x0C\x1DkE\x03A\x0CB\n", 1},
+		{"\x1Dv0\x00\x01\x00\x02\x00\x0C\x0C\fnext", 2},
+		// not ESC/POS
+		{"\x1Dv0\x00\x10\x00\f\f", 1},
+	} {
+		if got := ESCPOSPages([]byte(tt.data)); got != tt.pages {
+			t.Errorf("ESCPOSPages(%q) = %d, want %d", tt.data, got, tt.pages)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDuplicate is returned when a document with the same key was printed
+// recently, see Dedup.
+var ErrDuplicate = errors.New("printer: duplicate document")
+
+// Dedup remembers the keys of the documents printed for a while, so a
+// document submitted again, such as by a retried webhook, is not printed
+// twice. The keys are chosen by the caller, typically an order or ticket
+// ID. A Dedup is safe for concurrent use and may be shared by printers.
+type Dedup struct {
+	// TTL is how long a key is remembered. Zero means 10 minutes.
+	TTL time.Duration
+
+	mu   sync.Mutex
+	keys map[string]time.Time // expiry
+	now  func() time.Time
+}
+
+// NewDedup returns a Dedup remembering keys for ttl.
+func NewDedup(ttl time.Duration) *Dedup {
+	return &Dedup{TTL: ttl}
+}
+
+func (d *Dedup) ttl() time.Duration {
+	if d.TTL <= 0 {
+		return 10 * time.Minute
+	}
+	return d.TTL
+}
+
+// Claim records key and reports whether it was not already recorded in
+// the last TTL. Only the first of concurrent claims of a key succeeds.
+func (d *Dedup) Claim(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	if d.now != nil {
+		now = d.now()
+	}
+	if d.keys == nil {
+		d.keys = make(map[string]time.Time)
+	}
+	for k, exp := range d.keys {
+		if !now.Before(exp) {
+			delete(d.keys, k)
+		}
+	}
+	if _, ok := d.keys[key]; ok {
+		return false
+	}
+	d.keys[key] = now.Add(d.ttl())
+	return true
+}
+
+// Forget removes key, so the document can be printed again, for instance
+// after it failed to print.
+func (d *Dedup) Forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.keys, key)
+}
+
+// PrintOnce calls print, which prints a document, unless a document with
+// key was printed in the last TTL of p.Dedup, in which case it returns
+// ErrDuplicate. The key is forgotten if print fails, so the document can
+// be retried. Without p.Dedup, or with an empty key, print is always
+// called.
+func (p *Printer) PrintOnce(key string, print func() error) error {
+	d := p.Dedup
+	if d == nil || key == "" {
+		return print()
+	}
+	if !d.Claim(key) {
+		return ErrDuplicate
+	}
+	err := print()
+	if err != nil {
+		d.Forget(key)
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDedup(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewDedup(time.Minute)
+	d.now = func() time.Time { return now }
+	if !d.Claim("ticket-1") {
+		t.Fatal("first claim failed")
+	}
+	if d.Claim("ticket-1") {
+		t.Error("second claim succeeded")
+	}
+	if !d.Claim("ticket-2") {
+		t.Error("claim of another key failed")
+	}
+	now = now.Add(time.Minute)
+	if !d.Claim("ticket-1") {
+		t.Error("claim after the TTL failed")
+	}
+	d.Forget("ticket-2")
+	if !d.Claim("ticket-2") {
+		t.Error("claim of a forgotten key failed")
+	}
+}
+
+func TestPrintOnce(t *testing.T) {
+	p := new(Printer)
+	p.Dedup = NewDedup(0)
+	printed := 0
+	print := func() error { printed++; return nil }
+	if err := p.PrintOnce("order-7", print); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.PrintOnce("order-7", print); err != ErrDuplicate {
+		t.Errorf("got %v, want ErrDuplicate", err)
+	}
+	if printed != 1 {
+		t.Errorf("printed %d times", printed)
+	}
+
+	fail := errors.New("paper end")
+	if err := p.PrintOnce("order-8", func() error { return fail }); err != fail {
+		t.Errorf("got %v", err)
+	}
+	if err := p.PrintOnce("order-8", print); err != nil {
+		t.Errorf("retry after a failure: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "strings"
+
+// DeviceID is an IEEE 1284 device ID, as reported by the printer itself.
+type DeviceID struct {
+	Manufacturer string   // MFG
+	Model        string   // MDL
+	CommandSet   []string // CMD, such as "ESC/POS" or "PCL"
+	Class        string   // CLS
+	Description  string   // DES
+	// Fields holds all the fields by key, including the above.
+	Fields map[string]string
+	// Raw is the device ID as reported.
+	Raw string
+}
+
+// ParseDeviceID parses an IEEE 1284 device ID such as
+// "MFG:EPSON;CMD:ESC/POS;MDL:TM-T20;CLS:PRINTER;". The long key names,
+// such as MANUFACTURER and COMMAND SET, are accepted too.
+func ParseDeviceID(s string) *DeviceID {
+	id := &DeviceID{Raw: s, Fields: make(map[string]string)}
+	for _, field := range strings.Split(s, ";") {
+		i := strings.IndexByte(field, ':')
+		if i < 0 {
+			continue
+		}
+		key := strings.ToUpper(strings.TrimSpace(field[:i]))
+		value := strings.TrimSpace(field[i+1:])
+		id.Fields[key] = value
+		switch key {
+		case "MFG", "MANUFACTURER":
+			id.Manufacturer = value
+		case "MDL", "MODEL":
+			id.Model = value
+		case "CMD", "COMMAND SET":
+			id.CommandSet = nil
+			for _, cmd := range strings.Split(value, ",") {
+				if cmd = strings.TrimSpace(cmd); cmd != "" {
+					id.CommandSet = append(id.CommandSet, cmd)
+				}
+			}
+		case "CLS", "CLASS":
+			id.Class = value
+		case "DES", "DESCRIPTION":
+			id.Description = value
+		}
+	}
+	return id
+}
+
+// Supports reports whether the command set of the device contains cmd,
+// ignoring case.
+func (id *DeviceID) Supports(cmd string) bool {
+	for _, c := range id.CommandSet {
+		if strings.EqualFold(c, cmd) {
+			return true
+		}
+	}
+	return false
+}
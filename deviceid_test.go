@@ -0,0 +1,28 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDeviceID(t *testing.T) {
+	id := ParseDeviceID("MFG:EPSON;CMD:ESC/POS, PCL ;MDL:TM-T20II;CLS:PRINTER;DES:EPSON TM-T20II;")
+	if id.Manufacturer != "EPSON" || id.Model != "TM-T20II" || id.Class != "PRINTER" || id.Description != "EPSON TM-T20II" {
+		t.Errorf("ParseDeviceID = %+v", id)
+	}
+	if want := []string{"ESC/POS", "PCL"}; !reflect.DeepEqual(id.CommandSet, want) {
+		t.Errorf("CommandSet = %q, want %q", id.CommandSet, want)
+	}
+	if !id.Supports("esc/pos") || id.Supports("PostScript") {
+		t.Errorf("Supports is wrong for %q", id.CommandSet)
+	}
+
+	id = ParseDeviceID("MANUFACTURER:Star;COMMAND SET:STAR;MODEL:TSP143")
+	if id.Manufacturer != "Star" || id.Model != "TSP143" || !id.Supports("STAR") {
+		t.Errorf("ParseDeviceID with long keys = %+v", id)
+	}
+}
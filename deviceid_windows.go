@@ -0,0 +1,173 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// deviceGUID lets the generated syscalls refer to windows.GUID.
+type deviceGUID = windows.GUID
+
+type SP_DEVICE_INTERFACE_DATA struct {
+	CbSize             uint32
+	InterfaceClassGuid windows.GUID
+	Flags              uint32
+	Reserved           uintptr
+}
+
+const (
+	DIGCF_PRESENT         = 0x00000002
+	DIGCF_DEVICEINTERFACE = 0x00000010
+
+	DIREG_DEV = 1
+
+	IOCTL_USBPRINT_GET_1284_ID = 0x220034
+)
+
+// GUID_DEVINTERFACE_USBPRINT is the device interface class of USB printers.
+var GUID_DEVINTERFACE_USBPRINT = windows.GUID{
+	Data1: 0x28d78fad,
+	Data2: 0x5a12,
+	Data3: 0x11d1,
+	Data4: [8]byte{0xae, 0x5b, 0x00, 0x00, 0xf8, 0x03, 0xa8, 0xc2},
+}
+
+//sys	SetupDiGetClassDevs(guid *deviceGUID, enumerator *uint16, hwnd uintptr, flags uint32) (h syscall.Handle, err error) [failretval==syscall.InvalidHandle] = setupapi.SetupDiGetClassDevsW
+//sys	SetupDiDestroyDeviceInfoList(h syscall.Handle) (err error) = setupapi.SetupDiDestroyDeviceInfoList
+//sys	SetupDiEnumDeviceInterfaces(h syscall.Handle, devInfo uintptr, guid *deviceGUID, index uint32, data *SP_DEVICE_INTERFACE_DATA) (err error) = setupapi.SetupDiEnumDeviceInterfaces
+//sys	SetupDiGetDeviceInterfaceDetail(h syscall.Handle, data *SP_DEVICE_INTERFACE_DATA, detail *byte, detailN uint32, needed *uint32, devInfo uintptr) (err error) = setupapi.SetupDiGetDeviceInterfaceDetailW
+//sys	SetupDiOpenDeviceInterfaceRegKey(h syscall.Handle, data *SP_DEVICE_INTERFACE_DATA, reserved uint32, desired uint32) (key syscall.Handle, err error) [failretval==syscall.InvalidHandle] = setupapi.SetupDiOpenDeviceInterfaceRegKey
+
+// DeviceID queries the IEEE 1284 device ID of the printer. Only printers
+// attached to a USB port are supported; others return ErrUnsupported.
+func (p *Printer) DeviceID() (*DeviceID, error) {
+	info, err := p.Info()
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range splitPorts(info.PortName) {
+		if !strings.HasPrefix(strings.ToUpper(port), "USB") {
+			continue
+		}
+		path, err := usbPrintDevice(port)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			continue
+		}
+		s, err := query1284ID(path)
+		if err != nil {
+			return nil, err
+		}
+		return ParseDeviceID(s), nil
+	}
+	return nil, ErrUnsupported
+}
+
+// usbPrintDevice returns the path of the USB printer device bound to port,
+// such as "USB001", or "" if the device is not present.
+func usbPrintDevice(port string) (string, error) {
+	h, err := SetupDiGetClassDevs(&GUID_DEVINTERFACE_USBPRINT, nil, 0, DIGCF_PRESENT|DIGCF_DEVICEINTERFACE)
+	if err != nil {
+		return "", err
+	}
+	defer SetupDiDestroyDeviceInfoList(h)
+	for i := uint32(0); ; i++ {
+		data := SP_DEVICE_INTERFACE_DATA{CbSize: uint32(unsafe.Sizeof(SP_DEVICE_INTERFACE_DATA{}))}
+		err := SetupDiEnumDeviceInterfaces(h, 0, &GUID_DEVINTERFACE_USBPRINT, i, &data)
+		if err == syscall.Errno(windows.ERROR_NO_MORE_ITEMS) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !samePort(usbPortName(h, &data), port) {
+			continue
+		}
+		return deviceInterfacePath(h, &data)
+	}
+}
+
+// usbPortName returns the port name the USB printer interface data is
+// bound to, made of its "Base Name" and "Port Number" values.
+func usbPortName(h syscall.Handle, data *SP_DEVICE_INTERFACE_DATA) string {
+	kh, err := SetupDiOpenDeviceInterfaceRegKey(h, data, 0, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	k := registry.Key(kh)
+	defer k.Close()
+	base, _, err := k.GetStringValue("Base Name")
+	if err != nil {
+		return ""
+	}
+	n, _, err := k.GetIntegerValue("Port Number")
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s%03d", base, n)
+}
+
+// deviceInterfacePath returns the device path of the interface data.
+func deviceInterfacePath(h syscall.Handle, data *SP_DEVICE_INTERFACE_DATA) (string, error) {
+	var needed uint32
+	SetupDiGetDeviceInterfaceDetail(h, data, nil, 0, &needed, 0)
+	if needed < 6 {
+		return "", fmt.Errorf("printer: no device path for USB printer")
+	}
+	b := make([]uint16, (needed+1)/2)
+	// cbSize of SP_DEVICE_INTERFACE_DETAIL_DATA_W is 8 on 64-bit and 6 on
+	// 32-bit Windows
+	cbSize := uint32(6)
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		cbSize = 8
+	}
+	*(*uint32)(unsafe.Pointer(&b[0])) = cbSize
+	err := SetupDiGetDeviceInterfaceDetail(h, data, (*byte)(unsafe.Pointer(&b[0])), uint32(2*len(b)), &needed, 0)
+	if err != nil {
+		return "", err
+	}
+	return syscall.UTF16ToString(b[2:]), nil
+}
+
+// query1284ID reads the device ID from the USB printer device at path.
+func query1284ID(path string) (string, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return "", err
+	}
+	h, err := windows.CreateFile(name,
+		windows.GENERIC_READ|windows.GENERIC_WRITE, windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+		nil, windows.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	defer windows.CloseHandle(h)
+	b := make([]byte, 1024)
+	var n uint32
+	err = windows.DeviceIoControl(h, IOCTL_USBPRINT_GET_1284_ID, nil, 0, &b[0], uint32(len(b)), &n, nil)
+	if err != nil {
+		return "", err
+	}
+	// the ID is preceded by its length, including the length itself, in
+	// big endian
+	if n < 2 {
+		return "", fmt.Errorf("printer: empty device ID")
+	}
+	size := int(binary.BigEndian.Uint16(b))
+	if size > int(n) || size < 2 {
+		size = int(n)
+	}
+	return string(b[2:size]), nil
+}
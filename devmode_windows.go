@@ -0,0 +1,197 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// DEVMODE is the printer variant of the Windows DEVMODEW structure.
+// Driver private data (DriverExtra bytes) follows it in memory.
+type DEVMODE struct {
+	DeviceName       [32]uint16
+	SpecVersion      uint16
+	DriverVersion    uint16
+	Size             uint16
+	DriverExtra      uint16
+	Fields           uint32
+	Orientation      int16
+	PaperSize        int16
+	PaperLength      int16
+	PaperWidth       int16
+	Scale            int16
+	Copies           int16
+	DefaultSource    int16
+	PrintQuality     int16
+	Color            int16
+	Duplex           int16
+	YResolution      int16
+	TTOption         int16
+	Collate          int16
+	FormName         [32]uint16
+	LogPixels        uint16
+	BitsPerPel       uint32
+	PelsWidth        uint32
+	PelsHeight       uint32
+	Nup              uint32
+	DisplayFrequency uint32
+	ICMMethod        uint32
+	ICMIntent        uint32
+	MediaType        uint32
+	DitherType       uint32
+	Reserved1        uint32
+	Reserved2        uint32
+	PanningWidth     uint32
+	PanningHeight    uint32
+}
+
+type PRINTER_DEFAULTS struct {
+	Datatype      *uint16
+	DevMode       *DEVMODE
+	DesiredAccess uint32
+}
+
+const (
+	DM_ORIENTATION = 0x00000001
+	DM_PAPERSIZE   = 0x00000002
+	DM_COPIES      = 0x00000100
+	DM_DUPLEX      = 0x00001000
+	DM_COLLATE     = 0x00008000
+
+	DM_OUT_BUFFER = 2
+	DM_IN_BUFFER  = 8
+
+	DMCOLLATE_FALSE = 0
+	DMCOLLATE_TRUE  = 1
+
+	DC_DUPLEX  = 7
+	DC_COPIES  = 18
+	DC_COLLATE = 22
+)
+
+//sys	DocumentProperties(hwnd uintptr, h syscall.Handle, name *uint16, out *byte, in *byte, mode uint32) (n int32, err error) [failretval<0] = winspool.DocumentPropertiesW
+//sys	DeviceCapabilities(device *uint16, port *uint16, capability uint16, output *uint16, devmode *DEVMODE) (n int32, err error) [failretval==-1] = winspool.DeviceCapabilitiesW
+//sys	ResetPrinter(h syscall.Handle, defaults *PRINTER_DEFAULTS) (err error) = winspool.ResetPrinterW
+
+// devMode returns the DEVMODE used for documents started on p. The driver
+// defaults are fetched on first use.
+func (p *Printer) devMode() (*DEVMODE, error) {
+	if p.dm == nil {
+		u, err := newUTF16(p.name)
+		if err != nil {
+			return nil, err
+		}
+		defer u.free()
+		name := u.ptr()
+		n, err := DocumentProperties(0, p.h, name, nil, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, n)
+		_, err = DocumentProperties(0, p.h, name, &b[0], nil, DM_OUT_BUFFER)
+		if err != nil {
+			return nil, err
+		}
+		p.dm = b
+	}
+	return (*DEVMODE)(unsafe.Pointer(&p.dm[0])), nil
+}
+
+// applyDevMode has the driver validate changes made to the DEVMODE
+// returned by devMode and makes the result the default for documents
+// subsequently started on p.
+func (p *Printer) applyDevMode() error {
+	in := make([]byte, len(p.dm))
+	copy(in, p.dm)
+	u, err := newUTF16(p.name)
+	if err != nil {
+		return err
+	}
+	defer u.free()
+	_, err = DocumentProperties(0, p.h, u.ptr(), &p.dm[0], &in[0], DM_IN_BUFFER|DM_OUT_BUFFER)
+	if err != nil {
+		return err
+	}
+	d := PRINTER_DEFAULTS{
+		DevMode: (*DEVMODE)(unsafe.Pointer(&p.dm[0])),
+	}
+	return ResetPrinter(p.h, &d)
+}
+
+// capability queries the printer driver for capability c (one of the DC_
+// constants).
+func (p *Printer) capability(c uint16) (int32, error) {
+	u, err := newUTF16(p.name)
+	if err != nil {
+		return 0, err
+	}
+	defer u.free()
+	return DeviceCapabilities(u.ptr(), nil, c, nil, nil)
+}
+
+// SetCopies sets the number of copies printed for every document
+// subsequently started on p, and whether multiple copies are collated.
+// If the driver cannot produce the requested copies itself, the document
+// data is buffered and sent again by EndDocument instead.
+func (p *Printer) SetCopies(copies int, collate bool) error {
+	if copies < 1 || copies > 0x7fff {
+		return fmt.Errorf("printer: invalid number of copies: %d", copies)
+	}
+	max, err := p.capability(DC_COPIES)
+	if err != nil {
+		return err
+	}
+	driver := int(max) >= copies
+	if driver && collate && copies > 1 {
+		c, err := p.capability(DC_COLLATE)
+		if err != nil {
+			return err
+		}
+		driver = c == 1
+	}
+	dm, err := p.devMode()
+	if err != nil {
+		return err
+	}
+	dm.Fields |= DM_COPIES | DM_COLLATE
+	dm.Copies = 1
+	dm.Collate = DMCOLLATE_FALSE
+	p.copies = copies
+	if driver {
+		dm.Copies = int16(copies)
+		if collate {
+			dm.Collate = DMCOLLATE_TRUE
+		}
+		p.copies = 1
+	}
+	return p.applyDevMode()
+}
+
+// SetDuplex selects single or double-sided printing for every document
+// subsequently started on p. It returns ErrUnsupported if the driver
+// cannot print double-sided.
+func (p *Printer) SetDuplex(d Duplex) error {
+	switch d {
+	case Simplex:
+	case LongEdge, ShortEdge:
+		c, err := p.capability(DC_DUPLEX)
+		if err != nil {
+			return err
+		}
+		if c != 1 {
+			return ErrUnsupported
+		}
+	default:
+		return fmt.Errorf("printer: invalid duplex mode: %d", d)
+	}
+	dm, err := p.devMode()
+	if err != nil {
+		return err
+	}
+	dm.Fields |= DM_DUPLEX
+	dm.Duplex = int16(d)
+	return p.applyDevMode()
+}
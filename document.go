@@ -0,0 +1,362 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"image"
+	"strconv"
+	"strings"
+)
+
+// Document is a multi-page document printed through GDI. Content is added
+// in reading order and flows down the body of the page; whatever does not
+// fit on the current page continues on the next one.
+type Document struct {
+	Name    string
+	Options PageOptions
+
+	// Header and Footer are drawn centered in the header and footer
+	// bands of every page, see PageLayout. "{page}" is replaced with
+	// the page number.
+	Header string
+	Footer string
+
+	font  FontSpec
+	items []docItem
+}
+
+type docItemKind int
+
+const (
+	docText docItemKind = iota
+	docTable
+	docImage
+	docSpace
+	docLine
+	docPageBreak
+)
+
+// docItem is a piece of document content.
+type docItem struct {
+	kind  docItemKind
+	font  FontSpec
+	text  string
+	align Align
+
+	// table
+	cols   []Column
+	header []string
+	rows   [][]string
+
+	// image
+	img           image.Image
+	width, height int
+}
+
+// DefaultFont is the font used by a Document until SetFont is called.
+var DefaultFont = FontSpec{Family: "Arial", Size: 10}
+
+// NewDocument returns an empty document.
+func NewDocument(name string, opts PageOptions) *Document {
+	return &Document{
+		Name:    name,
+		Options: opts,
+		font:    DefaultFont,
+	}
+}
+
+// SetFont sets the font of the text and tables added after it.
+func (d *Document) SetFont(f FontSpec) {
+	d.font = f
+}
+
+// Text adds a paragraph of text wrapped to the page width.
+func (d *Document) Text(s string, align Align) {
+	d.items = append(d.items, docItem{kind: docText, font: d.font, text: s, align: align})
+}
+
+// Table adds a table. Rows are never split across pages and header, if
+// not nil, is repeated at the top of every page the table spans.
+func (d *Document) Table(cols []Column, header []string, rows [][]string) {
+	d.items = append(d.items, docItem{kind: docTable, font: d.font, cols: cols, header: header, rows: rows})
+}
+
+// Image adds img scaled to width by height points.
+func (d *Document) Image(img image.Image, width, height int, align Align) {
+	d.items = append(d.items, docItem{kind: docImage, img: img, width: width, height: height, align: align})
+}
+
+// Space adds height points of vertical space.
+func (d *Document) Space(height int) {
+	d.items = append(d.items, docItem{kind: docSpace, height: height})
+}
+
+// Line adds a horizontal line across the page.
+func (d *Document) Line() {
+	d.items = append(d.items, docItem{kind: docLine})
+}
+
+// AddPage starts a new page.
+func (d *Document) AddPage() {
+	d.items = append(d.items, docItem{kind: docPageBreak})
+}
+
+// Print prints d on p.
+func (d *Document) Print(p *Printer) error {
+	dc, err := p.NewDC(d.Options)
+	if err != nil {
+		return err
+	}
+	defer dc.Close()
+	return d.print(dc, "")
+}
+
+// BatchResult is the outcome of printing a document of a batch, see
+// PrintBatch.
+type BatchResult struct {
+	Name  string
+	JobID uint32 // 0 if the job could not be started
+	Err   error
+}
+
+// PrintBatch prints docs as one print job each, such as the reports of
+// an end-of-day run. Consecutive documents with the same Options share a
+// device context instead of opening one per document. A document failing
+// does not stop the batch; the results tell the job ID and error of
+// each document, in order.
+func (p *Printer) PrintBatch(docs []Document) []BatchResult {
+	results := make([]BatchResult, len(docs))
+	var dc *DC
+	var opts PageOptions // of dc
+	defer func() {
+		if dc != nil {
+			dc.Close()
+		}
+	}()
+	for i := range docs {
+		d := &docs[i]
+		results[i].Name = d.Name
+		if dc == nil || d.Options != opts {
+			if dc != nil {
+				dc.Close()
+				dc = nil
+			}
+			var err error
+			if dc, err = p.NewDC(d.Options); err != nil {
+				results[i].Err = err
+				continue
+			}
+			opts = d.Options
+		}
+		results[i].Err = d.print(dc, "")
+		results[i].JobID = dc.JobID()
+	}
+	return results
+}
+
+// print renders d as a print job on dc, printing to output if not empty.
+func (d *Document) print(dc *DC, output string) error {
+	err := dc.StartDoc(d.Name, output)
+	if err != nil {
+		return err
+	}
+	err = d.Render(dc)
+	if err != nil {
+		dc.AbortDoc()
+		return err
+	}
+	return dc.EndDoc()
+}
+
+// Render draws the pages of d on dc, which must have a document started.
+func (d *Document) Render(dc *DC) error {
+	return d.render(dc)
+}
+
+func (d *Document) render(c canvas) error {
+	r := docRenderer{d: d, c: c}
+	if err := r.startPage(); err != nil {
+		return err
+	}
+	for i := range d.items {
+		if err := r.item(&d.items[i]); err != nil {
+			return err
+		}
+	}
+	return r.endPage()
+}
+
+// docRenderer keeps track of the position on the page while a Document
+// is rendered.
+type docRenderer struct {
+	d    *Document
+	c    canvas
+	page int
+	body image.Rectangle
+	y    int
+}
+
+func (r *docRenderer) startPage() error {
+	r.page++
+	if err := r.c.StartPage(); err != nil {
+		return err
+	}
+	r.body = r.c.Body()
+	r.y = r.body.Min.Y
+	if err := r.band(r.c.Header(), r.d.Header); err != nil {
+		return err
+	}
+	return r.band(r.c.Footer(), r.d.Footer)
+}
+
+func (r *docRenderer) endPage() error {
+	return r.c.EndPage()
+}
+
+func (r *docRenderer) newPage() error {
+	if err := r.endPage(); err != nil {
+		return err
+	}
+	return r.startPage()
+}
+
+// band draws the header or footer text s in b.
+func (r *docRenderer) band(b image.Rectangle, s string) error {
+	if s == "" || b.Empty() {
+		return nil
+	}
+	if err := r.c.SetFont(DefaultFont); err != nil {
+		return err
+	}
+	s = strings.Replace(s, "{page}", strconv.Itoa(r.page), -1)
+	_, err := textBox(r.c, b, s, AlignCenter)
+	return err
+}
+
+// fits reports whether h more points fit on the page. Anything fits on
+// an empty page, so oversized content is clipped rather than looping.
+func (r *docRenderer) fits(h int) bool {
+	return r.y == r.body.Min.Y || r.y+h <= r.body.Max.Y
+}
+
+func (r *docRenderer) item(it *docItem) error {
+	switch it.kind {
+	case docText:
+		return r.text(it)
+	case docTable:
+		return r.table(it)
+	case docImage:
+		if !r.fits(it.height) {
+			if err := r.newPage(); err != nil {
+				return err
+			}
+		}
+		x := alignX(r.body.Min.X, r.body.Dx(), it.width, it.align)
+		err := r.c.DrawImage(image.Rect(x, r.y, x+it.width, r.y+it.height), it.img)
+		r.y += it.height
+		return err
+	case docSpace:
+		r.y += it.height
+		if r.y >= r.body.Max.Y {
+			return r.newPage()
+		}
+	case docLine:
+		err := r.c.Line(r.body.Min.X, r.y, r.body.Max.X, r.y)
+		r.y++
+		return err
+	case docPageBreak:
+		return r.newPage()
+	}
+	return nil
+}
+
+// text draws a paragraph line by line, breaking pages between lines.
+func (r *docRenderer) text(it *docItem) error {
+	if err := r.c.SetFont(it.font); err != nil {
+		return err
+	}
+	lines, err := wrapText(r.c, it.text, r.body.Dx())
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		w, h, err := measureLine(r.c, line)
+		if err != nil {
+			return err
+		}
+		if !r.fits(h) {
+			if err := r.newPage(); err != nil {
+				return err
+			}
+			if err := r.c.SetFont(it.font); err != nil {
+				return err
+			}
+		}
+		if err := r.c.TextOut(alignX(r.body.Min.X, r.body.Dx(), w, it.align), r.y, line); err != nil {
+			return err
+		}
+		r.y += h
+	}
+	return nil
+}
+
+// table draws a table row by row, repeating the header on new pages.
+func (r *docRenderer) table(it *docItem) error {
+	if err := r.c.SetFont(it.font); err != nil {
+		return err
+	}
+	if it.header != nil {
+		if err := r.row(it, it.header, false); err != nil {
+			return err
+		}
+	}
+	for _, cells := range it.rows {
+		if err := r.row(it, cells, it.header != nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// row draws one table row, starting a new page first if it does not fit.
+func (r *docRenderer) row(it *docItem, cells []string, header bool) error {
+	h, err := r.rowHeight(it.cols, cells)
+	if err != nil {
+		return err
+	}
+	if !r.fits(h) {
+		if err := r.newPage(); err != nil {
+			return err
+		}
+		if err := r.c.SetFont(it.font); err != nil {
+			return err
+		}
+		if header {
+			if err := r.row(it, it.header, false); err != nil {
+				return err
+			}
+		}
+	}
+	h, err = tableRow(r.c, r.body.Min.X, r.y, it.cols, cells)
+	r.y += h
+	return err
+}
+
+func (r *docRenderer) rowHeight(cols []Column, cells []string) (int, error) {
+	height := 0
+	for i, c := range cols {
+		if i >= len(cells) {
+			break
+		}
+		h, err := textHeight(r.c, cells[i], c.Width)
+		if err != nil {
+			return 0, err
+		}
+		if h > height {
+			height = h
+		}
+	}
+	return height, nil
+}
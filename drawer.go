@@ -0,0 +1,132 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"time"
+)
+
+// DrawerPin is the pin of the drawer kick connector a drawer is wired to.
+type DrawerPin uint8
+
+const (
+	DrawerPin2 DrawerPin = 0
+	DrawerPin5 DrawerPin = 1
+)
+
+// DrawerKick describes the pulse opening a cash drawer.
+type DrawerKick struct {
+	Pin DrawerPin
+	// On and Off are the durations the pulse is on and off, rounded down
+	// to 2ms and at most 510ms. Zero means DefaultDrawerKick's.
+	On, Off time.Duration
+	// Settle is the time waited after the pulse before the drawer state
+	// is checked by KickDrawer. Zero means 250ms.
+	Settle time.Duration
+	// OpenLow is set for drawers whose open switch pulls the connector's
+	// pin 3 low, inverting StatusDrawerOpen.
+	OpenLow bool
+}
+
+// DefaultDrawerKick opens the drawer on pin 2 with a 50ms pulse, which
+// suits most drawers.
+var DefaultDrawerKick = DrawerKick{Pin: DrawerPin2, On: 50 * time.Millisecond, Off: 500 * time.Millisecond}
+
+// bytes returns ESC p for k.
+func (k DrawerKick) bytes() ([]byte, error) {
+	if k.Pin != DrawerPin2 && k.Pin != DrawerPin5 {
+		return nil, fmt.Errorf("printer: invalid drawer pin: %d", k.Pin)
+	}
+	on, off := k.On, k.Off
+	if on == 0 {
+		on = DefaultDrawerKick.On
+	}
+	if off == 0 {
+		off = DefaultDrawerKick.Off
+	}
+	const unit, max = 2 * time.Millisecond, 255 * 2 * time.Millisecond
+	if on < unit || on > max || off < unit || off > max {
+		return nil, fmt.Errorf("printer: invalid drawer pulse %v on, %v off", on, off)
+	}
+	return []byte{esc, 'p', byte(k.Pin), byte(on / unit), byte(off / unit)}, nil
+}
+
+// DrawerState tells whether a cash drawer is open.
+type DrawerState int
+
+const (
+	// DrawerUnknown is returned when the printer status cannot be read,
+	// such as through the Windows spooler.
+	DrawerUnknown DrawerState = iota
+	DrawerClosed
+	DrawerOpen
+)
+
+func (s DrawerState) String() string {
+	switch s {
+	case DrawerClosed:
+		return "closed"
+	case DrawerOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// DrawerResult is the result of KickDrawer.
+type DrawerResult struct {
+	// Before and After are the states of the drawer before and after
+	// the pulse.
+	Before, After DrawerState
+	// Err is the error reading the printer status, if any.
+	Err error
+}
+
+// Opened reports whether the drawer was seen opening, or already open.
+func (r DrawerResult) Opened() bool {
+	return r.After == DrawerOpen
+}
+
+// open the cash drawer with the pulse k -- ESC p
+func (p *Printer) OpenDrawer(k DrawerKick) error {
+	b, err := k.bytes()
+	if err != nil {
+		return err
+	}
+	_, err = p.Write(b)
+	return err
+}
+
+// KickDrawer sends the pulse k and checks with DLE EOT 1 whether the
+// drawer opened, for POS flows that must confirm access to the cash. An
+// error is only returned if the pulse cannot be sent; status errors are
+// reported in the result, whose states are then DrawerUnknown.
+func (p *Printer) KickDrawer(k DrawerKick) (DrawerResult, error) {
+	var r DrawerResult
+	r.Before, r.Err = p.DrawerState(k.OpenLow)
+	if err := p.OpenDrawer(k); err != nil {
+		return r, err
+	}
+	settle := k.Settle
+	if settle <= 0 {
+		settle = 250 * time.Millisecond
+	}
+	time.Sleep(settle)
+	r.After, r.Err = p.DrawerState(k.OpenLow)
+	return r, nil
+}
+
+// DrawerState returns the state of the drawer, from the level of pin 3
+// of the drawer kick connector; openLow inverts it.
+func (p *Printer) DrawerState(openLow bool) (DrawerState, error) {
+	s, err := p.Status()
+	if err != nil {
+		return DrawerUnknown, err
+	}
+	if (s&StatusDrawerOpen != 0) != openLow {
+		return DrawerOpen, nil
+	}
+	return DrawerClosed, nil
+}
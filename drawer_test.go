@@ -0,0 +1,57 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpenDrawer(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	if err := p.OpenDrawer(DrawerKick{Pin: DrawerPin5, On: 100 * time.Millisecond, Off: 200 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ft.String(), "\x1Bp\x01\x32\x64"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+	if err := p.OpenDrawer(DrawerKick{Pin: 3}); err == nil {
+		t.Error("no error for pin 3")
+	}
+	if err := p.OpenDrawer(DrawerKick{On: time.Second}); err == nil {
+		t.Error("no error for a 1s pulse")
+	}
+}
+
+func TestKickDrawer(t *testing.T) {
+	tests := []struct {
+		status        []byte
+		openLow       bool
+		before, after DrawerState
+	}{
+		{[]byte{0x12, 0x16}, false, DrawerClosed, DrawerOpen},
+		{[]byte{0x12, 0x12}, false, DrawerClosed, DrawerClosed},
+		{[]byte{0x16, 0x12}, true, DrawerClosed, DrawerOpen},
+		{[]byte{0x12, 0xFF}, false, DrawerClosed, DrawerUnknown},
+	}
+	for _, tt := range tests {
+		ft := &fakeTransport{status: tt.status}
+		p := NewPrinter("fake", ft)
+		r, err := p.KickDrawer(DrawerKick{Settle: time.Millisecond, OpenLow: tt.openLow})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Before != tt.before || r.After != tt.after {
+			t.Errorf("status % x: got %v, %v, want %v, %v", tt.status, r.Before, r.After, tt.before, tt.after)
+		}
+		if (r.Err != nil) != (tt.after == DrawerUnknown) {
+			t.Errorf("status % x: error %v", tt.status, r.Err)
+		}
+		if got, want := ft.String(), "\x10\x04\x01\x1Bp\x00\x19\xfa\x10\x04\x01"; got != want {
+			t.Errorf("wrote %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type PRINTER_INFO_2 struct {
+	ServerName         *uint16
+	PrinterName        *uint16
+	ShareName          *uint16
+	PortName           *uint16
+	DriverName         *uint16
+	Comment            *uint16
+	Location           *uint16
+	DevMode            *DEVMODE
+	SepFile            *uint16
+	PrintProcessor     *uint16
+	Datatype           *uint16
+	Parameters         *uint16
+	SecurityDescriptor uintptr
+	Attributes         uint32
+	Priority           uint32
+	DefaultPriority    uint32
+	StartTime          uint32
+	UntilTime          uint32
+	Status             uint32
+	Jobs               uint32
+	AveragePPM         uint32
+}
+
+const (
+	APD_STRICT_UPGRADE      = 0x00000001
+	APD_STRICT_DOWNGRADE    = 0x00000002
+	APD_COPY_ALL_FILES      = 0x00000004
+	APD_COPY_NEW_FILES      = 0x00000008
+	APD_COPY_FROM_DIRECTORY = 0x00000010
+
+	UPDP_SILENT_UPLOAD = 0x00000001
+)
+
+//sys	UploadPrinterDriverPackage(server *uint16, infPath *uint16, env *uint16, flags uint32, hwnd uintptr, destInfPath *uint16, destInfPathN *uint32) (hr uint32) = winspool.UploadPrinterDriverPackageW
+//sys	InstallPrinterDriverFromPackage(server *uint16, infPath *uint16, driverName *uint16, env *uint16, flags uint32) (hr uint32) = winspool.InstallPrinterDriverFromPackageW
+//sys	AddPrinterDriverEx(server *uint16, level uint32, info *byte, flags uint32) (err error) = winspool.AddPrinterDriverExW
+//sys	AddPrinter(server *uint16, level uint32, info *byte) (h syscall.Handle, err error) = winspool.AddPrinterW
+
+// hresultError converts a HRESULT returned by the spooler to an error.
+func hresultError(hr uint32) error {
+	if hr == 0 {
+		return nil
+	}
+	if hr&0xffff0000 == 0x80070000 {
+		// HRESULT_FROM_WIN32
+		return syscall.Errno(hr & 0xffff)
+	}
+	return syscall.Errno(hr)
+}
+
+// StageDriverPackage copies the printer driver package described by the
+// INF file infPath into the driver store and returns the path of the INF
+// file in the store. It requires administrator rights.
+func StageDriverPackage(infPath string) (string, error) {
+	inf, err := windows.UTF16PtrFromString(infPath)
+	if err != nil {
+		return "", err
+	}
+	b := make([]uint16, syscall.MAX_PATH)
+	n := uint32(len(b))
+	hr := UploadPrinterDriverPackage(nil, inf, nil, UPDP_SILENT_UPLOAD, 0, &b[0], &n)
+	if err := hresultError(hr); err != nil {
+		return "", err
+	}
+	return syscall.UTF16ToString(b), nil
+}
+
+// InstallDriver stages the driver package infPath and installs the
+// printer driver named driverName from it, so queues can be created with
+// CreateQueue. The driver name must match a model listed in the INF file.
+// It requires administrator rights.
+func InstallDriver(infPath, driverName string) error {
+	staged, err := StageDriverPackage(infPath)
+	if err != nil {
+		return err
+	}
+	inf, err := windows.UTF16PtrFromString(staged)
+	if err != nil {
+		return err
+	}
+	driver, err := windows.UTF16PtrFromString(driverName)
+	if err != nil {
+		return err
+	}
+	hr := InstallPrinterDriverFromPackage(nil, inf, driver, nil, 0)
+	return hresultError(hr)
+}
+
+// AddDriver installs a printer driver from the files described by di with
+// AddPrinterDriverEx, for drivers not distributed as a driver package.
+// flags is a combination of the APD_ constants.
+func AddDriver(di *DRIVER_INFO_8, flags uint32) error {
+	return AddPrinterDriverEx(nil, 8, (*byte)(unsafe.Pointer(di)), flags)
+}
+
+// CreateQueue creates a printer queue named name using the installed
+// driver and the existing port, such as "USB001" or "LPT1:". The queue
+// accepts raw data, as sent by StartRawDocument.
+func CreateQueue(name, driver, port string) error {
+	var pi PRINTER_INFO_2
+	for _, f := range []struct {
+		p **uint16
+		s string
+	}{
+		{&pi.PrinterName, name},
+		{&pi.PortName, port},
+		{&pi.DriverName, driver},
+		{&pi.PrintProcessor, "winprint"},
+		{&pi.Datatype, "RAW"},
+	} {
+		s, err := windows.UTF16PtrFromString(f.s)
+		if err != nil {
+			return err
+		}
+		*f.p = s
+	}
+	h, err := AddPrinter(nil, 2, (*byte)(unsafe.Pointer(&pi)))
+	if err != nil {
+		return err
+	}
+	return ClosePrinter(h)
+}
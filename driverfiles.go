@@ -0,0 +1,19 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "time"
+
+// DriverFile describes a file of an installed printer driver.
+type DriverFile struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	// Version is the file version from the version resource, or empty if
+	// the file has none.
+	Version string
+	// Err is set if the file is missing or cannot be read.
+	Err error
+}
@@ -0,0 +1,177 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type VS_FIXEDFILEINFO struct {
+	Signature        uint32
+	StrucVersion     uint32
+	FileVersionMS    uint32
+	FileVersionLS    uint32
+	ProductVersionMS uint32
+	ProductVersionLS uint32
+	FileFlagsMask    uint32
+	FileFlags        uint32
+	FileOS           uint32
+	FileType         uint32
+	FileSubtype      uint32
+	FileDateMS       uint32
+	FileDateLS       uint32
+}
+
+//sys	GetPrinterDriverDirectory(server *uint16, env *uint16, level uint32, buf *uint16, bufN uint32, needed *uint32) (err error) = winspool.GetPrinterDriverDirectoryW
+//sys	GetFileVersionInfoSize(filename *uint16, handle *uint32) (n uint32, err error) = version.GetFileVersionInfoSizeW
+//sys	GetFileVersionInfo(filename *uint16, handle uint32, bufN uint32, buf *byte) (err error) = version.GetFileVersionInfoW
+//sys	VerQueryValue(block *byte, subBlock *uint16, buf **byte, bufN *uint32) (ok bool) = version.VerQueryValueW
+
+// DriverDirectory returns the directory where the spooler keeps the
+// printer drivers for the environment env, such as "Windows x64". An
+// empty env means the environment of the local machine.
+func DriverDirectory(env string) (string, error) {
+	var e *uint16
+	if env != "" {
+		var err error
+		if e, err = windows.UTF16PtrFromString(env); err != nil {
+			return "", err
+		}
+	}
+	b := make([]uint16, syscall.MAX_PATH)
+	var needed uint32
+	for {
+		err := GetPrinterDriverDirectory(nil, e, 1, &b[0], uint32(2*len(b)), &needed)
+		if err == nil {
+			break
+		}
+		if err != syscall.ERROR_INSUFFICIENT_BUFFER {
+			return "", err
+		}
+		if needed <= uint32(2*len(b)) {
+			return "", err
+		}
+		b = make([]uint16, needed/2+1)
+	}
+	return syscall.UTF16ToString(b), nil
+}
+
+// Files returns the driver, data, configuration, help and dependent files
+// of di. Dependent files are listed by name only; they are looked up next
+// to the driver file and then in the driver directory of the environment.
+// Files that cannot be found or read have Err set, which usually means a
+// corrupt driver installation.
+func (di *DriverInfo) Files() []DriverFile {
+	var dirs []string
+	if di.DriverPath != "" {
+		dirs = append(dirs, filepath.Dir(di.DriverPath))
+	}
+	if dir, err := DriverDirectory(di.Environment); err == nil {
+		dirs = append(dirs, filepath.Join(dir, fmt.Sprint(di.Version)), dir)
+	}
+	var files []DriverFile
+	for _, path := range []string{di.DriverPath, di.DataFile, di.ConfigFile, di.HelpFile} {
+		if path != "" {
+			files = append(files, inspectDriverFile(path))
+		}
+	}
+	for _, name := range di.DependentFiles {
+		path := resolveDriverFile(name, dirs)
+		files = append(files, inspectDriverFile(path))
+	}
+	return files
+}
+
+// resolveDriverFile returns the path of the file name in the first of dirs
+// containing it, or name joined to the first directory if none does.
+func resolveDriverFile(name string, dirs []string) string {
+	if filepath.IsAbs(name) || len(dirs) == 0 {
+		return name
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dirs[0], name)
+}
+
+func inspectDriverFile(path string) DriverFile {
+	f := DriverFile{Path: path}
+	fi, err := os.Stat(path)
+	if err != nil {
+		f.Err = err
+		return f
+	}
+	f.Size = fi.Size()
+	f.ModTime = fi.ModTime()
+	f.Version, _ = FileVersion(path)
+	return f
+}
+
+// FileVersion returns the file version, such as "10.0.19041.1", from the
+// version resource of the executable or DLL at path.
+func FileVersion(path string) (string, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return "", err
+	}
+	var handle uint32
+	n, err := GetFileVersionInfoSize(name, &handle)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	err = GetFileVersionInfo(name, 0, n, &b[0])
+	if err != nil {
+		return "", err
+	}
+	var fixed *byte
+	var fixedN uint32
+	root := []uint16{'\\', 0} // the root block, VS_FIXEDFILEINFO
+	if !VerQueryValue(&b[0], &root[0], &fixed, &fixedN) || fixedN < uint32(unsafe.Sizeof(VS_FIXEDFILEINFO{})) {
+		return "", fmt.Errorf("printer: %s has no fixed file version", path)
+	}
+	vi := (*VS_FIXEDFILEINFO)(unsafe.Pointer(fixed))
+	return fmt.Sprintf("%d.%d.%d.%d",
+		vi.FileVersionMS>>16, vi.FileVersionMS&0xffff,
+		vi.FileVersionLS>>16, vi.FileVersionLS&0xffff), nil
+}
+
+// multiSZ splits a list of strings separated and terminated by NULs.
+func multiSZ(p *uint16) []string {
+	if p == nil {
+		return nil
+	}
+	var list []string
+	for {
+		s := (*[1 << 20]uint16)(unsafe.Pointer(p))
+		n := 0
+		for s[n] != 0 {
+			n++
+		}
+		if n == 0 {
+			return list
+		}
+		list = append(list, syscall.UTF16ToString(s[:n]))
+		p = &s[n+1]
+	}
+}
+
+// filetimeToTime converts ft to a time, or the zero time if ft is unset.
+func filetimeToTime(ft syscall.Filetime) time.Time {
+	if ft.HighDateTime == 0 && ft.LowDateTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ft.Nanoseconds())
+}
@@ -0,0 +1,54 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "strings"
+
+// EnumOptions selects the printers returned by Enumerate.
+type EnumOptions struct {
+	// Flags is a combination of the PRINTER_ENUM_ constants. Zero means
+	// PRINTER_ENUM_LOCAL|PRINTER_ENUM_CONNECTIONS.
+	Flags uint32
+	// Server is the name of the print server to enumerate, such as
+	// `\\server`, used with PRINTER_ENUM_NAME. Empty means the local
+	// machine.
+	Server string
+	// Level is the PRINTER_INFO level to query: 1, 2, 4 or 5. Zero means 5.
+	// Lower levels fill fewer fields of Info but level 4 is the fastest
+	// and level 2 the most complete.
+	Level uint32
+	// Pattern, if set, keeps only printers whose name matches it, ignoring
+	// case. '*' matches any sequence of characters, including backslashes,
+	// and '?' any single character.
+	Pattern string
+}
+
+// matchName reports whether name matches the wildcard pattern, ignoring
+// case.
+func matchName(pattern, name string) bool {
+	p := []rune(strings.ToLower(pattern))
+	n := []rune(strings.ToLower(name))
+	// backtrack to the last '*' on mismatch
+	pi, ni, star, mark := 0, 0, -1, 0
+	for ni < len(n) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == n[ni]):
+			pi++
+			ni++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, ni
+			pi++
+		case star >= 0:
+			mark++
+			pi, ni = star+1, mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
@@ -0,0 +1,28 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*", "anything", true},
+		{"EPSON*", "epson TM-T20III Receipt", true},
+		{"*tm-t20*", `\\server\EPSON TM-T20`, true},
+		{"POS-?0", "POS-80", true},
+		{"POS-?0", "POS-800", false},
+		{"*Receipt", "Receipt printer", false},
+		{"", "", true},
+		{"", "x", false},
+	}
+	for _, test := range tests {
+		if got := matchName(test.pattern, test.name); got != test.want {
+			t.Errorf("matchName(%q, %q) = %v, want %v", test.pattern, test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Enumerate returns the printers selected by opts. The fields of Info not
+// provided by the selected level are left empty.
+func Enumerate(opts EnumOptions) ([]Info, error) {
+	flags := opts.Flags
+	if flags == 0 {
+		flags = PRINTER_ENUM_LOCAL | PRINTER_ENUM_CONNECTIONS
+	}
+	level := opts.Level
+	if level == 0 {
+		level = 5
+	}
+	if level != 1 && level != 2 && level != 4 && level != 5 {
+		return nil, fmt.Errorf("printer: invalid enumeration level: %d", level)
+	}
+	var server *uint16
+	if opts.Server != "" {
+		var err error
+		if server, err = windows.UTF16PtrFromString(opts.Server); err != nil {
+			return nil, err
+		}
+	}
+	var returned uint32
+	buf, err := spoolCall(func(b []byte, needed *uint32) error {
+		return EnumPrinters(flags, server, level, &b[0], uint32(len(b)), needed, &returned)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer buf.free()
+	printers := make([]Info, 0, returned)
+	for i := 0; i < int(returned); i++ {
+		var info Info
+		switch level {
+		case 1:
+			pi := (*PRINTER_INFO_1)(buf.at(i, unsafe.Sizeof(PRINTER_INFO_1{})))
+			info = Info{
+				Name:    windows.UTF16PtrToString(pi.Name),
+				Comment: windows.UTF16PtrToString(pi.Comment),
+			}
+		case 2:
+			info = *newInfo((*PRINTER_INFO_2)(buf.at(i, unsafe.Sizeof(PRINTER_INFO_2{}))))
+		case 4:
+			pi := (*PRINTER_INFO_4)(buf.at(i, unsafe.Sizeof(PRINTER_INFO_4{})))
+			info = Info{
+				Name:       windows.UTF16PtrToString(pi.PrinterName),
+				ServerName: windows.UTF16PtrToString(pi.ServerName),
+				Attributes: Attributes(pi.Attributes),
+			}
+		case 5:
+			pi := (*PRINTER_INFO_5)(buf.at(i, unsafe.Sizeof(PRINTER_INFO_5{})))
+			info = Info{
+				Name:       windows.UTF16PtrToString(pi.PrinterName),
+				PortName:   windows.UTF16PtrToString(pi.PortName),
+				Attributes: Attributes(pi.Attributes),
+			}
+		}
+		if opts.Pattern != "" && !matchName(opts.Pattern, info.Name) {
+			continue
+		}
+		printers = append(printers, info)
+	}
+	return printers, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strings"
+)
+
+// EscapeMode tells how control bytes in text are handled. Text coming from
+// users, such as customer names or item notes, could otherwise embed
+// commands cutting the paper, opening the drawer or changing the
+// formatting.
+type EscapeMode int
+
+const (
+	// EscapeNone sends text unchanged.
+	EscapeNone EscapeMode = iota
+	// EscapeStrip removes control bytes.
+	EscapeStrip
+	// EscapeReplace replaces control bytes with '?'.
+	EscapeReplace
+)
+
+// isControl reports whether b is a control byte other than tab, line feed
+// and carriage return.
+func isControl(b byte) bool {
+	return (b < 0x20 && b != '\t' && b != '\n' && b != '\r') || b == 0x7F
+}
+
+// escapeText handles the control bytes of s as set by mode.
+func escapeText(s string, mode EscapeMode) string {
+	if mode == EscapeNone {
+		return s
+	}
+	i := 0
+	for i < len(s) && !isControl(s[i]) {
+		i++
+	}
+	if i == len(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(s[:i])
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case !isControl(c):
+			b.WriteByte(c)
+		case mode == EscapeReplace:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,27 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestEscapeText(t *testing.T) {
+	const s = "Mr \x1Bp\x00\x19Smith\x1DVA0\tnote\n"
+	tests := []struct {
+		mode EscapeMode
+		want string
+	}{
+		{EscapeNone, s},
+		{EscapeStrip, "Mr pSmithVA0\tnote\n"},
+		{EscapeReplace, "Mr ?p??Smith?VA0\tnote\n"},
+	}
+	for _, test := range tests {
+		if got := escapeText(s, test.mode); got != test.want {
+			t.Errorf("escapeText(%q, %d) = %q, want %q", s, test.mode, got, test.want)
+		}
+	}
+	if got := escapeText("plain", EscapeStrip); got != "plain" {
+		t.Errorf("escapeText(plain) = %q", got)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"time"
+)
+
+// fileTransport writes the data of a printer to a file, for instance to
+// capture it or to write to a device file. Reads return io.EOF.
+type fileTransport struct {
+	f *os.File
+}
+
+// openFile opens file:path, truncating the file unless the parameter
+// "append" is set.
+func openFile(path string, params url.Values) (Transport, error) {
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if _, ok := params["append"]; ok {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileTransport{f: f}, nil
+}
+
+func (t *fileTransport) Write(b []byte) (int, error)        { return t.f.Write(b) }
+func (t *fileTransport) Read(b []byte) (int, error)         { return 0, io.EOF }
+func (t *fileTransport) Close() error                       { return t.f.Close() }
+func (t *fileTransport) SetWriteDeadline(d time.Time) error { return nil }
+func (t *fileTransport) SetReadDeadline(d time.Time) error  { return nil }
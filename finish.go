@@ -0,0 +1,59 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+// CutMode tells how the paper is cut.
+type CutMode int
+
+const (
+	CutNone CutMode = iota
+	// CutFull cuts the paper completely.
+	CutFull
+	// CutPartial leaves a point of the paper uncut, so receipts do not
+	// fall from the printer.
+	CutPartial
+)
+
+// Finish is the sequence ending the documents sent to a printer, see
+// Printer.End.
+type Finish struct {
+	// Feed is the number of lines fed after the last line printed, to
+	// bring it past the cutter. Printers differ in the distance from the
+	// print head to the cutter.
+	Feed uint8
+	// Cut is how the paper is cut after the feed.
+	Cut CutMode
+	// Reset sends ESC @ last, clearing the print buffer and restoring
+	// the default settings for the next document.
+	Reset bool
+}
+
+// DefaultFinish is the finish of profiles with no Finish set: the paper
+// is fed past the cutter of most 80mm printers and partially cut.
+var DefaultFinish = &Finish{Feed: 4, Cut: CutPartial}
+
+// bytes returns the commands of f.
+func (f *Finish) bytes() []byte {
+	// ESC d prints the pending line even if nothing is fed
+	b := []byte{esc, 'd', f.Feed}
+	switch f.Cut {
+	case CutFull:
+		b = append(b, gs, 'V', 0)
+	case CutPartial:
+		b = append(b, gs, 'V', 1)
+	}
+	if f.Reset {
+		b = append(b, esc, '@')
+	}
+	return b
+}
+
+// finish returns the finish of pr.
+func (pr *Profile) finish() *Finish {
+	if pr.Finish != nil {
+		return pr.Finish
+	}
+	return DefaultFinish
+}
@@ -0,0 +1,30 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFinish(t *testing.T) {
+	tests := []struct {
+		f    *Finish
+		want []byte
+	}{
+		{DefaultFinish, []byte("\x1bd\x04\x1dV\x01")},
+		{&Finish{}, []byte("\x1bd\x00")},
+		{&Finish{Feed: 6, Cut: CutFull, Reset: true}, []byte("\x1bd\x06\x1dV\x00\x1b@")},
+	}
+	for _, tt := range tests {
+		if got := tt.f.bytes(); !bytes.Equal(got, tt.want) {
+			t.Errorf("%+v: got %q, want %q", *tt.f, got, tt.want)
+		}
+	}
+	pr := &Profile{}
+	if pr.finish() != DefaultFinish {
+		t.Error("profile without Finish does not use DefaultFinish")
+	}
+}
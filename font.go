@@ -0,0 +1,97 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+)
+
+// Font is a character font of an ESC/POS printer, selected with ESC M.
+type Font uint8
+
+const (
+	FontA        Font = 0
+	FontB        Font = 1
+	FontC        Font = 2
+	FontD        Font = 3
+	FontE        Font = 4
+	FontSpecialA Font = 97
+	FontSpecialB Font = 98
+)
+
+var fontNames = map[string]Font{
+	"A":         FontA,
+	"B":         FontB,
+	"C":         FontC,
+	"D":         FontD,
+	"E":         FontE,
+	"SPECIAL A": FontSpecialA,
+	"SPECIAL B": FontSpecialB,
+}
+
+func (f Font) String() string {
+	switch f {
+	case FontA, FontB, FontC, FontD, FontE:
+		return string(rune('A' + f))
+	case FontSpecialA:
+		return "special A"
+	case FontSpecialB:
+		return "special B"
+	}
+	return fmt.Sprintf("Font(%d)", uint8(f))
+}
+
+// select font -- ESC M
+func (p *Printer) SelectFont(f Font) error {
+	if p.profile().Columns(f) == 0 {
+		return fmt.Errorf("printer: font %v not supported by profile %s", f, p.profile().Name)
+	}
+	if _, err := p.Write([]byte{esc, 'M', byte(f)}); err != nil {
+		return err
+	}
+	p.font = f
+	return nil
+}
+
+// send font -- ESC M n
+func (p *Printer) SendFont() {
+	p.Write([]byte{esc, 'M', byte(p.font)})
+}
+
+// Font returns the font currently selected.
+func (p *Printer) Font() Font {
+	return p.font
+}
+
+// Columns returns the number of characters that fit on a line with the
+// current font and character width.
+func (p *Printer) Columns() int {
+	n := p.profile().Columns(p.font)
+	if p.width > 1 {
+		n /= int(p.width)
+	}
+	return n
+}
+
+// set character size -- GS !, width and height are multipliers from 1 up
+// to the profile's MaxCharSize
+func (p *Printer) SetCharSize(width, height uint8) error {
+	max := p.profile().maxCharSize()
+	if width < 1 || height < 1 || width > max || height > max {
+		return fmt.Errorf("printer: invalid character size %dx%d, must be 1 to %d", width, height, max)
+	}
+	p.width = width
+	p.height = height
+	p.SendFontSize()
+	return nil
+}
+
+// CharSize returns the current character width and height multipliers.
+func (p *Printer) CharSize() (width, height uint8) {
+	if p.width == 0 || p.height == 0 {
+		return 1, 1
+	}
+	return p.width, p.height
+}
@@ -0,0 +1,29 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestFontColumns(t *testing.T) {
+	if got := FontC.String(); got != "C" {
+		t.Errorf("FontC.String() = %q", got)
+	}
+	if got := FontSpecialB.String(); got != "special B" {
+		t.Errorf("FontSpecialB.String() = %q", got)
+	}
+	var p Printer
+	p.reset()
+	if got := p.Columns(); got != 48 {
+		t.Errorf("Columns() = %d, want 48", got)
+	}
+	p.font = FontB
+	p.width = 2
+	if got := p.Columns(); got != 32 {
+		t.Errorf("Columns() in font B at double width = %d, want 32", got)
+	}
+	if err := p.SelectFont(FontE); err == nil {
+		t.Error("SelectFont(FontE) succeeded with the default profile")
+	}
+}
@@ -0,0 +1,556 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"image"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type DOCINFO struct {
+	Size     int32
+	DocName  *uint16
+	Output   *uint16
+	Datatype *uint16
+	Type     uint32
+}
+
+type XFORM struct {
+	M11, M12, M21, M22, Dx, Dy float32
+}
+
+type SIZE struct {
+	CX, CY int32
+}
+
+type POINT struct {
+	X, Y int32
+}
+
+type LOGFONT struct {
+	Height         int32
+	Width          int32
+	Escapement     int32
+	Orientation    int32
+	Weight         int32
+	Italic         byte
+	Underline      byte
+	StrikeOut      byte
+	CharSet        byte
+	OutPrecision   byte
+	ClipPrecision  byte
+	Quality        byte
+	PitchAndFamily byte
+	FaceName       [32]uint16
+}
+
+type BITMAPINFOHEADER struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   uint32
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+const (
+	HORZRES         = 8
+	VERTRES         = 10
+	LOGPIXELSX      = 88
+	LOGPIXELSY      = 90
+	PHYSICALWIDTH   = 110
+	PHYSICALHEIGHT  = 111
+	PHYSICALOFFSETX = 112
+	PHYSICALOFFSETY = 113
+
+	GM_ADVANCED    = 2
+	MM_ANISOTROPIC = 8
+
+	DC_ORIENTATION = 17
+
+	BI_RGB         = 0
+	DIB_RGB_COLORS = 0
+	SRCCOPY        = 0x00CC0020
+
+	TRANSPARENT     = 1
+	DEFAULT_CHARSET = 1
+	OUT_TT_PRECIS   = 4
+)
+
+//sys	CreateDC(driver *uint16, device *uint16, output *uint16, devmode *DEVMODE) (dc syscall.Handle, err error) = gdi32.CreateDCW
+//sys	DeleteDC(dc syscall.Handle) (err error) = gdi32.DeleteDC
+//sys	StartDoc(dc syscall.Handle, di *DOCINFO) (id int32, err error) [failretval<=0] = gdi32.StartDocW
+//sys	EndDoc(dc syscall.Handle) (n int32, err error) [failretval<=0] = gdi32.EndDoc
+//sys	AbortDoc(dc syscall.Handle) (n int32, err error) [failretval<=0] = gdi32.AbortDoc
+//sys	StartPage(dc syscall.Handle) (n int32, err error) [failretval<=0] = gdi32.StartPage
+//sys	EndPage(dc syscall.Handle) (n int32, err error) [failretval<=0] = gdi32.EndPage
+//sys	GetDeviceCaps(dc syscall.Handle, index int32) (n int32) = gdi32.GetDeviceCaps
+//sys	SetGraphicsMode(dc syscall.Handle, mode int32) (old int32, err error) = gdi32.SetGraphicsMode
+//sys	SetWorldTransform(dc syscall.Handle, xf *XFORM) (err error) = gdi32.SetWorldTransform
+//sys	SetMapMode(dc syscall.Handle, mode int32) (old int32, err error) = gdi32.SetMapMode
+//sys	SetWindowExtEx(dc syscall.Handle, x int32, y int32, old *SIZE) (err error) = gdi32.SetWindowExtEx
+//sys	SetViewportExtEx(dc syscall.Handle, x int32, y int32, old *SIZE) (err error) = gdi32.SetViewportExtEx
+//sys	TextOut(dc syscall.Handle, x int32, y int32, s *uint16, n int32) (err error) = gdi32.TextOutW
+//sys	SetBkMode(dc syscall.Handle, mode int32) (old int32, err error) = gdi32.SetBkMode
+//sys	CreateFontIndirect(lf *LOGFONT) (font syscall.Handle, err error) = gdi32.CreateFontIndirectW
+//sys	SelectObject(dc syscall.Handle, obj syscall.Handle) (old syscall.Handle, err error) = gdi32.SelectObject
+//sys	DeleteObject(obj syscall.Handle) (err error) = gdi32.DeleteObject
+//sys	GetTextExtentPoint32(dc syscall.Handle, s *uint16, n int32, size *SIZE) (err error) = gdi32.GetTextExtentPoint32W
+//sys	MoveToEx(dc syscall.Handle, x int32, y int32, old *POINT) (err error) = gdi32.MoveToEx
+//sys	LineTo(dc syscall.Handle, x int32, y int32) (err error) = gdi32.LineTo
+//sys	StretchDIBits(dc syscall.Handle, x int32, y int32, w int32, h int32, srcX int32, srcY int32, srcW int32, srcH int32, bits *byte, bmi *BITMAPINFOHEADER, usage uint32, rop uint32) (n int32, err error) = gdi32.StretchDIBits
+
+// DC is a GDI device context used to render text and graphics on a
+// printer, as opposed to sending raw data with Write. Coordinates are in
+// points (1/72 inch) from the top-left corner of the paper, with y growing
+// downwards, regardless of page orientation.
+type DC struct {
+	h    syscall.Handle
+	opts PageOptions
+
+	// rotate is set when landscape pages are rotated in software
+	rotate bool
+
+	// slot is the position of the current page on the sheet, see
+	// PageOptions.NUp
+	slot int
+
+	// font is the font selected with SetFont
+	font syscall.Handle
+
+	// job is the ID of the print job started by StartDoc
+	job uint32
+}
+
+// SetOrientation selects portrait or landscape printing for every
+// document subsequently started on p. It returns ErrUnsupported if the
+// driver cannot print landscape.
+func (p *Printer) SetOrientation(o Orientation) error {
+	switch o {
+	case Portrait:
+	case Landscape:
+		c, err := p.capability(DC_ORIENTATION)
+		if err != nil {
+			return err
+		}
+		if c == 0 {
+			return ErrUnsupported
+		}
+	default:
+		return fmt.Errorf("printer: invalid orientation: %d", o)
+	}
+	dm, err := p.devMode()
+	if err != nil {
+		return err
+	}
+	dm.Fields |= DM_ORIENTATION
+	dm.Orientation = int16(o)
+	return p.applyDevMode()
+}
+
+// NewDC creates a device context for rendering on p. The context starts
+// from the DEVMODE settings of p (copies, duplex, orientation) with opts
+// applied on top.
+func (p *Printer) NewDC(opts PageOptions) (*DC, error) {
+	dm, err := p.devMode()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, len(p.dm))
+	copy(b, p.dm)
+	dm = (*DEVMODE)(unsafe.Pointer(&b[0]))
+
+	switch opts.NUp {
+	case 0:
+		opts.NUp = 1
+	case 1, 2, 4:
+	default:
+		return nil, fmt.Errorf("printer: invalid number of pages per sheet: %d", opts.NUp)
+	}
+	dc := &DC{opts: opts}
+	switch opts.Orientation {
+	case 0:
+	case Portrait:
+		dm.Fields |= DM_ORIENTATION
+		dm.Orientation = DMORIENT_PORTRAIT
+	case Landscape:
+		c, err := p.capability(DC_ORIENTATION)
+		if err != nil {
+			return nil, err
+		}
+		dm.Fields |= DM_ORIENTATION
+		dm.Orientation = DMORIENT_PORTRAIT
+		if c == 0 {
+			dc.rotate = true
+		} else {
+			dm.Orientation = DMORIENT_LANDSCAPE
+		}
+	default:
+		return nil, fmt.Errorf("printer: invalid orientation: %d", opts.Orientation)
+	}
+
+	name, err := windows.UTF16PtrFromString(p.name)
+	if err != nil {
+		return nil, err
+	}
+	dc.h, err = CreateDC(nil, name, nil, dm)
+	if err != nil {
+		return nil, err
+	}
+	return dc, nil
+}
+
+// StartDoc starts a print job named name on dc. If output is not empty,
+// the job is printed to that file instead of the device.
+func (dc *DC) StartDoc(name, output string) error {
+	docName, err := newUTF16(name)
+	if err != nil {
+		return err
+	}
+	defer docName.free()
+	di := DOCINFO{
+		DocName: docName.ptr(),
+	}
+	di.Size = int32(unsafe.Sizeof(di))
+	if output != "" {
+		s, err := windows.UTF16PtrFromString(output)
+		if err != nil {
+			return err
+		}
+		di.Output = s
+	}
+	dc.job = 0
+	id, err := StartDoc(dc.h, &di)
+	if err != nil {
+		return err
+	}
+	dc.job = uint32(id)
+	return nil
+}
+
+// JobID returns the ID of the print job started by StartDoc.
+func (dc *DC) JobID() uint32 {
+	return dc.job
+}
+
+// EndDoc ends the print job started by StartDoc.
+func (dc *DC) EndDoc() error {
+	if dc.slot > 0 {
+		// flush a partially filled n-up sheet
+		dc.slot = 0
+		if _, err := EndPage(dc.h); err != nil {
+			EndDoc(dc.h)
+			return err
+		}
+	}
+	_, err := EndDoc(dc.h)
+	return err
+}
+
+// AbortDoc cancels the print job started by StartDoc.
+func (dc *DC) AbortDoc() error {
+	dc.slot = 0
+	_, err := AbortDoc(dc.h)
+	return err
+}
+
+// StartPage starts a new page and sets up the coordinate space. With
+// n-up printing a new sheet is only started for every NUp pages.
+func (dc *DC) StartPage() error {
+	if dc.slot == 0 {
+		_, err := StartPage(dc.h)
+		if err != nil {
+			return err
+		}
+	}
+	return dc.setup()
+}
+
+// EndPage ends the current page.
+func (dc *DC) EndPage() error {
+	dc.slot++
+	if dc.slot < dc.opts.NUp {
+		return nil
+	}
+	dc.slot = 0
+	_, err := EndPage(dc.h)
+	return err
+}
+
+// Close deletes the device context.
+func (dc *DC) Close() error {
+	err := DeleteDC(dc.h)
+	if dc.font != 0 {
+		DeleteObject(dc.font)
+	}
+	return err
+}
+
+// Size returns the width and height of the paper in points, as seen by
+// the caller after orientation is applied.
+func (dc *DC) Size() (width, height int) {
+	w := dc.caps(PHYSICALWIDTH, LOGPIXELSX)
+	h := dc.caps(PHYSICALHEIGHT, LOGPIXELSY)
+	if dc.rotate {
+		return h, w
+	}
+	return w, h
+}
+
+// PrintableArea returns the part of the paper the printer can print on.
+func (dc *DC) PrintableArea() image.Rectangle {
+	x := dc.caps(PHYSICALOFFSETX, LOGPIXELSX)
+	y := dc.caps(PHYSICALOFFSETY, LOGPIXELSY)
+	r := image.Rect(x, y, x+dc.caps(HORZRES, LOGPIXELSX), y+dc.caps(VERTRES, LOGPIXELSY))
+	if dc.rotate {
+		// inverse of the rotation in transform
+		w, _ := dc.Size()
+		r = image.Rect(w-r.Max.Y, r.Min.X, w-r.Min.Y, r.Max.X)
+	}
+	return r
+}
+
+// Body returns the area inside the margins between the header and footer
+// bands.
+func (dc *DC) Body() image.Rectangle {
+	_, body, _ := dc.areas()
+	return body
+}
+
+// Header returns the header band at the top of the page.
+func (dc *DC) Header() image.Rectangle {
+	header, _, _ := dc.areas()
+	return header
+}
+
+// Footer returns the footer band at the bottom of the page.
+func (dc *DC) Footer() image.Rectangle {
+	_, _, footer := dc.areas()
+	return footer
+}
+
+func (dc *DC) areas() (header, body, footer image.Rectangle) {
+	w, h := dc.Size()
+	return pageAreas(image.Pt(w, h), dc.PrintableArea(), dc.opts.Layout)
+}
+
+// caps returns device capability index converted from pixels to points
+// using the resolution capability dpi.
+func (dc *DC) caps(index, dpi int32) int {
+	return int(GetDeviceCaps(dc.h, index)) * 72 / int(GetDeviceCaps(dc.h, dpi))
+}
+
+// setup maps points to device pixels and installs the world transform
+// rotating landscape pages when the driver does not.
+func (dc *DC) setup() error {
+	if _, err := SetGraphicsMode(dc.h, GM_ADVANCED); err != nil {
+		return err
+	}
+	if _, err := SetMapMode(dc.h, MM_ANISOTROPIC); err != nil {
+		return err
+	}
+	if _, err := SetBkMode(dc.h, TRANSPARENT); err != nil {
+		return err
+	}
+	if err := SetWindowExtEx(dc.h, 72, 72, nil); err != nil {
+		return err
+	}
+	err := SetViewportExtEx(dc.h, GetDeviceCaps(dc.h, LOGPIXELSX), GetDeviceCaps(dc.h, LOGPIXELSY), nil)
+	if err != nil {
+		return err
+	}
+	return SetWorldTransform(dc.h, dc.transform())
+}
+
+// transform returns the world transform for the current page: its
+// position on an n-up sheet, the landscape rotation and finally the
+// offset of the printable area, which is where the device origin is.
+func (dc *DC) transform() *XFORM {
+	w, h := dc.Size()
+	xf := nupTransform(dc.opts.NUp, dc.slot, float32(w), float32(h))
+	if dc.rotate {
+		// The top of the landscape page runs along the left edge
+		// of the paper: (x, y) maps to (y, width-x).
+		xf = xf.mul(&XFORM{M12: -1, M21: 1, Dy: float32(w)})
+	}
+	xf = xf.mul(&XFORM{
+		M11: 1,
+		M22: 1,
+		Dx:  -float32(dc.caps(PHYSICALOFFSETX, LOGPIXELSX)),
+		Dy:  -float32(dc.caps(PHYSICALOFFSETY, LOGPIXELSY)),
+	})
+	return &xf
+}
+
+// nupTransform returns the transform that places page slot of an n-up
+// sheet on a w by h points page.
+func nupTransform(n, slot int, w, h float32) XFORM {
+	switch n {
+	case 2:
+		// rotate the page, scale it to half the sheet and center it
+		// in its half
+		rot := XFORM{M12: -1, M21: 1, Dy: w}
+		var s, x, y float32
+		if w < h {
+			s = minf(w/h, h/2/w)
+			x = (w - h*s) / 2
+			y = float32(slot)*h/2 + (h/2-w*s)/2
+		} else {
+			s = minf(w/2/h, h/w)
+			x = float32(slot)*w/2 + (w/2-h*s)/2
+			y = (h - w*s) / 2
+		}
+		return rot.mul(&XFORM{M11: s, M22: s, Dx: x, Dy: y})
+	case 4:
+		return XFORM{M11: 0.5, M22: 0.5, Dx: float32(slot%2) * w / 2, Dy: float32(slot/2) * h / 2}
+	}
+	return XFORM{M11: 1, M22: 1}
+}
+
+// mul returns the transform that applies xf and then b.
+func (xf *XFORM) mul(b *XFORM) XFORM {
+	return XFORM{
+		M11: xf.M11*b.M11 + xf.M12*b.M21,
+		M12: xf.M11*b.M12 + xf.M12*b.M22,
+		M21: xf.M21*b.M11 + xf.M22*b.M21,
+		M22: xf.M21*b.M12 + xf.M22*b.M22,
+		Dx:  xf.Dx*b.M11 + xf.Dy*b.M21 + b.Dx,
+		Dy:  xf.Dx*b.M12 + xf.Dy*b.M22 + b.Dy,
+	}
+}
+
+func minf(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// TextOut draws s with its top-left corner at x, y.
+func (dc *DC) TextOut(x, y int, s string) error {
+	u, err := newUTF16(s)
+	if err != nil {
+		return err
+	}
+	defer u.free()
+	return TextOut(dc.h, int32(x), int32(y), u.ptr(), int32(len(u.chars())))
+}
+
+// DrawImage draws img scaled to fill r.
+func (dc *DC) DrawImage(r image.Rectangle, img image.Image) error {
+	b := img.Bounds()
+	// 32 bit top-down DIB, pixels in BGRA order composed over white
+	bits := make([]byte, 0, 4*b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			r, g, b = r+0xffff-a, g+0xffff-a, b+0xffff-a
+			bits = append(bits, byte(b>>8), byte(g>>8), byte(r>>8), 0)
+		}
+	}
+	if len(bits) == 0 {
+		return nil
+	}
+	bmi := BITMAPINFOHEADER{
+		Width:       int32(b.Dx()),
+		Height:      -int32(b.Dy()),
+		Planes:      1,
+		BitCount:    32,
+		Compression: BI_RGB,
+	}
+	bmi.Size = uint32(unsafe.Sizeof(bmi))
+	_, err := StretchDIBits(dc.h, int32(r.Min.X), int32(r.Min.Y), int32(r.Dx()), int32(r.Dy()),
+		0, 0, int32(b.Dx()), int32(b.Dy()), &bits[0], &bmi, DIB_RGB_COLORS, SRCCOPY)
+	return err
+}
+
+// SetFont selects the font used by the text drawing methods.
+func (dc *DC) SetFont(f FontSpec) error {
+	if f.Size <= 0 {
+		return fmt.Errorf("printer: invalid font size: %d", f.Size)
+	}
+	lf := LOGFONT{
+		Height:       -int32(f.Size),
+		Weight:       int32(f.Weight),
+		CharSet:      DEFAULT_CHARSET,
+		OutPrecision: OUT_TT_PRECIS,
+	}
+	if lf.Weight == 0 {
+		lf.Weight = FW_NORMAL
+	}
+	if f.Italic {
+		lf.Italic = 1
+	}
+	family, err := windows.UTF16FromString(f.Family)
+	if err != nil {
+		return err
+	}
+	copy(lf.FaceName[:len(lf.FaceName)-1], family)
+	font, err := CreateFontIndirect(&lf)
+	if err != nil {
+		return err
+	}
+	if _, err := SelectObject(dc.h, font); err != nil {
+		DeleteObject(font)
+		return err
+	}
+	if dc.font != 0 {
+		DeleteObject(dc.font)
+	}
+	dc.font = font
+	return nil
+}
+
+// MeasureText returns the width and height of s drawn in the current
+// font.
+func (dc *DC) MeasureText(s string) (width, height int, err error) {
+	u, err := newUTF16(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer u.free()
+	var sz SIZE
+	err = GetTextExtentPoint32(dc.h, u.ptr(), int32(len(u.chars())), &sz)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(sz.CX), int(sz.CY), nil
+}
+
+// Line draws a line from x1, y1 to x2, y2.
+func (dc *DC) Line(x1, y1, x2, y2 int) error {
+	if err := MoveToEx(dc.h, int32(x1), int32(y1), nil); err != nil {
+		return err
+	}
+	return LineTo(dc.h, int32(x2), int32(y2))
+}
+
+// WrapText breaks s into lines no wider than width in the current font.
+// Lines are broken at spaces where possible and at newlines in s.
+func (dc *DC) WrapText(s string, width int) ([]string, error) {
+	return wrapText(dc, s, width)
+}
+
+// TextBox draws s wrapped to the width of r and aligned within it,
+// starting at the top of r. Lines that do not fit in r are not drawn. It
+// returns the height of the drawn text.
+func (dc *DC) TextBox(r image.Rectangle, s string, align Align) (int, error) {
+	return textBox(dc, r, s, align)
+}
+
+// TableRow draws one row of a table at x, y, wrapping every cell to the
+// width of its column. It returns the height of the row.
+func (dc *DC) TableRow(x, y int, cols []Column, cells []string) (int, error) {
+	return tableRow(dc, x, y, cols, cells)
+}
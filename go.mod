@@ -3,6 +3,9 @@ module github.com/icobani/printer
 go 1.15
 
 require (
+	golang.org/x/image v0.0.0-20210628002857-a66eb6448b8d
 	golang.org/x/sys v0.0.0-20210525143221-35b2ab0089ea
 	golang.org/x/text v0.3.6
+	google.golang.org/grpc v1.38.0
+	google.golang.org/protobuf v1.26.0
 )
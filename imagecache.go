@@ -0,0 +1,198 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	_ "golang.org/x/image/bmp"
+)
+
+// monochrome is the palette of dithered images: the dots a receipt
+// printer prints and the paper.
+var monochrome = color.Palette{color.Black, color.White}
+
+// Dither returns img converted to black and white with Floyd-Steinberg
+// error diffusion, so gray areas print as patterns of dots instead of
+// being thresholded. Transparent areas become white.
+func Dither(img image.Image) *image.Paletted {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewPaletted(image.Rect(0, 0, w, h), monochrome)
+	if w == 0 || h == 0 {
+		return dst
+	}
+	luma := make([]uint8, w*h)
+	row := lumaRows(img)
+	parallelRows(w, h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			row(y, luma[y*w:(y+1)*w])
+		}
+	})
+	// errors diffused to the current and next rows, with a guard
+	// column on both sides
+	cur, next := make([]int32, w+2), make([]int32, w+2)
+	for y := 0; y < h; y++ {
+		l, pix := luma[y*w:(y+1)*w], dst.Pix[y*dst.Stride:]
+		for x := 0; x < w; x++ {
+			v := int32(l[x]) + cur[x+1]/16
+			e := v
+			if v >= 128 {
+				pix[x] = 1
+				e = v - 255
+			}
+			cur[x+2] += 7 * e
+			next[x] += 3 * e
+			next[x+1] += 5 * e
+			next[x+2] += e
+		}
+		cur, next = next, cur
+		for i := range next {
+			next[i] = 0
+		}
+	}
+	return dst
+}
+
+// ImageCache caches decoded and dithered images by the SHA-256 hash of
+// their encoded content, so logos printed on every receipt are processed
+// once. The least recently used images are dropped beyond MaxImages. It
+// is safe for concurrent use.
+type ImageCache struct {
+	// MaxImages is the number of images kept. Zero means
+	// DefaultMaxImages.
+	MaxImages int
+
+	mu  sync.Mutex
+	m   map[[sha256.Size]byte]*list.Element
+	lru list.List // of *cachedImage, most recently used first
+}
+
+// DefaultMaxImages is the default number of images kept by an ImageCache.
+const DefaultMaxImages = 64
+
+type cachedImage struct {
+	sum [sha256.Size]byte
+	img *image.Paletted
+}
+
+// NewImageCache returns an empty cache.
+func NewImageCache() *ImageCache {
+	return &ImageCache{m: make(map[[sha256.Size]byte]*list.Element)}
+}
+
+// DefaultImageCache is the cache used by ImageFromFile and ImageFromURL.
+var DefaultImageCache = NewImageCache()
+
+// Image returns the dithered image encoded in data, in any format
+// registered with the image package; PNG, JPEG, GIF and BMP are.
+func (c *ImageCache) Image(data []byte) (*image.Paletted, error) {
+	sum := sha256.Sum256(data)
+	c.mu.Lock()
+	e, ok := c.m[sum]
+	if ok {
+		c.lru.MoveToFront(e)
+	}
+	c.mu.Unlock()
+	if ok {
+		return e.Value.(*cachedImage).img, nil
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	img := Dither(src)
+	c.add(sum, img)
+	return img, nil
+}
+
+// add caches img under sum, dropping the least recently used images
+// beyond MaxImages.
+func (c *ImageCache) add(sum [sha256.Size]byte, img *image.Paletted) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.m[sum]; ok {
+		// decoded concurrently
+		c.lru.MoveToFront(e)
+		return
+	}
+	if c.m == nil {
+		c.m = make(map[[sha256.Size]byte]*list.Element)
+	}
+	c.m[sum] = c.lru.PushFront(&cachedImage{sum, img})
+	max := c.MaxImages
+	if max <= 0 {
+		max = DefaultMaxImages
+	}
+	for c.lru.Len() > max {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.m, e.Value.(*cachedImage).sum)
+	}
+}
+
+// Len returns the number of cached images.
+func (c *ImageCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.m)
+}
+
+// Clear removes all images from the cache.
+func (c *ImageCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m = make(map[[sha256.Size]byte]*list.Element)
+	c.lru.Init()
+}
+
+// ImageFromFile returns the image in the file at path, dithered and
+// cached in DefaultImageCache. The returned image is shared and must not
+// be modified.
+func ImageFromFile(path string) (*image.Paletted, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	img, err := DefaultImageCache.Image(data)
+	if err != nil {
+		return nil, fmt.Errorf("printer: %s: %v", path, err)
+	}
+	return img, nil
+}
+
+// ImageFromURL is like ImageFromFile but downloads the image from url.
+// The image is downloaded every time, but only decoded and dithered when
+// its content changed.
+func ImageFromURL(url string) (*image.Paletted, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("printer: %s: %s", url, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	img, err := DefaultImageCache.Image(data)
+	if err != nil {
+		return nil, fmt.Errorf("printer: %s: %v", url, err)
+	}
+	return img, nil
+}
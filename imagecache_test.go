@@ -0,0 +1,114 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testPNG(t *testing.T) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 4))
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 4; y++ {
+			img.Set(x, y, color.Gray{uint8(x * 16)})
+		}
+	}
+	img.Set(15, 3, color.NRGBA{}) // transparent
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDither(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(testPNG(t))
+	src, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := Dither(src)
+	if img.ColorIndexAt(0, 0) != 0 {
+		t.Error("black is not printed")
+	}
+	if img.ColorIndexAt(15, 3) != 1 {
+		t.Error("transparent is printed")
+	}
+	black := 0
+	for _, c := range img.Pix {
+		if c == 0 {
+			black++
+		}
+	}
+	if black < 16 || black > 48 {
+		t.Errorf("%d of 64 dots printed for a gray ramp", black)
+	}
+}
+
+func TestImageCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "printer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	data := testPNG(t)
+	path := filepath.Join(dir, "logo.png")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	DefaultImageCache.Clear()
+	a, err := ImageFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ImageFromURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b || DefaultImageCache.Len() != 1 {
+		t.Errorf("same content decoded twice")
+	}
+	if _, err := DefaultImageCache.Image([]byte("not an image")); err == nil {
+		t.Error("invalid image decoded")
+	}
+}
+
+func TestImageCacheEviction(t *testing.T) {
+	images := make([][]byte, 3)
+	for i := range images {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, i+1, 1))); err != nil {
+			t.Fatal(err)
+		}
+		images[i] = buf.Bytes()
+	}
+	c := NewImageCache()
+	c.MaxImages = 2
+	first, _ := c.Image(images[0])
+	c.Image(images[1])
+	c.Image(images[0]) // most recently used, images[1] is dropped next
+	c.Image(images[2])
+	if n := c.Len(); n != 2 {
+		t.Errorf("%d images cached, want 2", n)
+	}
+	if img, _ := c.Image(images[0]); img != first {
+		t.Error("recently used image dropped")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// JobEventKind tells what happened to a job, see WatchJobs.
+type JobEventKind int
+
+const (
+	JobAdded JobEventKind = iota
+	JobChanged
+	JobRemoved
+)
+
+func (k JobEventKind) String() string {
+	switch k {
+	case JobAdded:
+		return "added"
+	case JobChanged:
+		return "changed"
+	case JobRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// JobEvent is a change of the queue of a printer.
+type JobEvent struct {
+	Kind JobEventKind
+	Job  JobInfo // last known state for JobRemoved
+}
+
+// WatchJobs polls the queue of p every interval and sends the jobs added,
+// changed and removed since the previous poll on the returned channel,
+// which is closed when ctx is done or the queue cannot be read anymore.
+// Jobs already queued are sent as added first.
+func (p *Printer) WatchJobs(ctx context.Context, interval time.Duration) (<-chan JobEvent, error) {
+	jobs, err := p.Jobs()
+	if err != nil {
+		return nil, err
+	}
+	c := make(chan JobEvent)
+	go func() {
+		defer close(c)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var old []JobInfo
+		for {
+			for _, e := range diffJobs(old, jobs) {
+				select {
+				case c <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+			old = jobs
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			if jobs, err = p.Jobs(); err != nil {
+				return
+			}
+		}
+	}()
+	return c, nil
+}
+
+// diffJobs returns the events turning the jobs old into new.
+func diffJobs(old, new []JobInfo) []JobEvent {
+	var events []JobEvent
+	byID := make(map[uint32]JobInfo, len(old))
+	for _, j := range old {
+		byID[j.JobID] = j
+	}
+	for _, j := range new {
+		o, ok := byID[j.JobID]
+		switch {
+		case !ok:
+			events = append(events, JobEvent{JobAdded, j})
+		case o.StatusCode != j.StatusCode || o.Status != j.Status || o.PagesPrinted != j.PagesPrinted ||
+			o.TotalPages != j.TotalPages || o.Position != j.Position ||
+			o.BytesPrinted != j.BytesPrinted || o.TotalBytes != j.TotalBytes:
+			events = append(events, JobEvent{JobChanged, j})
+		}
+		delete(byID, j.JobID)
+	}
+	for _, j := range old {
+		if _, ok := byID[j.JobID]; ok {
+			events = append(events, JobEvent{JobRemoved, j})
+		}
+	}
+	return events
+}
+
+// Progress returns how much of job j has printed, in percent. It uses
+// the bytes sent to the printer when the spooler reports them, and the
+// pages printed otherwise. A job that has finished is at 100.
+func (j JobInfo) Progress() float64 {
+	switch {
+	case j.StatusCode.IsFinished():
+		return 100
+	case j.TotalBytes > 0 && j.BytesPrinted > 0:
+		return percent(j.BytesPrinted, j.TotalBytes)
+	case j.TotalPages > 0:
+		return percent(j.PagesPrinted, j.TotalPages)
+	}
+	return 0
+}
+
+func percent(n, total uint32) float64 {
+	if n >= total {
+		return 100
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// JobProgress returns the progress of the job id in the queue of p, see
+// JobInfo.Progress.
+func (p *Printer) JobProgress(id uint32) (float64, error) {
+	jobs, err := p.Jobs()
+	if err != nil {
+		return 0, err
+	}
+	for i := range jobs {
+		if jobs[i].JobID == id {
+			return jobs[i].Progress(), nil
+		}
+	}
+	return 0, fmt.Errorf("printer: no job %d in queue %q", id, p.name)
+}
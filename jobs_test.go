@@ -0,0 +1,69 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffJobs(t *testing.T) {
+	old := []JobInfo{
+		{JobID: 1, Status: "Printing", PagesPrinted: 1},
+		{JobID: 2, Status: "Spooling"},
+		{JobID: 3},
+	}
+	new := []JobInfo{
+		{JobID: 2, Status: "Spooling"},
+		{JobID: 3, Position: 1},
+		{JobID: 4},
+	}
+	want := []JobEvent{
+		{JobChanged, JobInfo{JobID: 3, Position: 1}},
+		{JobAdded, JobInfo{JobID: 4}},
+		{JobRemoved, JobInfo{JobID: 1, Status: "Printing", PagesPrinted: 1}},
+	}
+	if got := diffJobs(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffJobs = %+v, want %+v", got, want)
+	}
+	if got := diffJobs(nil, old); len(got) != 3 || got[0].Kind != JobAdded {
+		t.Errorf("diffJobs(nil, old) = %+v", got)
+	}
+}
+
+func TestSystemTime(t *testing.T) {
+	st := SystemTime{Year: 2021, Month: 6, Day: 1, Hour: 12, Minute: 30, Second: 15, Milliseconds: 250}
+	want := time.Date(2021, 6, 1, 12, 30, 15, 250*int(time.Millisecond), time.UTC)
+	if got := st.Time(time.UTC); !got.Equal(want) {
+		t.Errorf("Time(UTC) = %v, want %v", got, want)
+	}
+	east := time.FixedZone("east", 3*60*60)
+	if got := st.Time(east); !got.Equal(want.Add(-3 * time.Hour)) {
+		t.Errorf("Time(east) = %v, want %v", got, want.Add(-3*time.Hour))
+	}
+	p := NewPrinter("fake", nil)
+	if p.spoolerTimeZone() != time.UTC {
+		t.Errorf("default spooler time zone = %v, want UTC", p.spoolerTimeZone())
+	}
+}
+
+func TestJobProgress(t *testing.T) {
+	for _, tt := range []struct {
+		job  JobInfo
+		want float64
+	}{
+		{JobInfo{}, 0},
+		{JobInfo{TotalPages: 4, PagesPrinted: 1}, 25},
+		{JobInfo{TotalPages: 4, PagesPrinted: 1, TotalBytes: 1000, BytesPrinted: 500}, 50},
+		{JobInfo{TotalPages: 4, PagesPrinted: 1, TotalBytes: 1000}, 25},
+		{JobInfo{TotalBytes: 1000, BytesPrinted: 1200}, 100},
+		{JobInfo{StatusCode: JOB_STATUS_PRINTED, TotalBytes: 1000}, 100},
+	} {
+		if got := tt.job.Progress(); got != tt.want {
+			t.Errorf("%+v.Progress() = %v, want %v", tt.job, got, tt.want)
+		}
+	}
+}
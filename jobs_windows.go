@@ -0,0 +1,138 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "unsafe"
+
+//sys	SetJob(h syscall.Handle, jobID uint32, level uint32, buf *byte, command uint32) (err error) = winspool.SetJobW
+//sys	FindFirstPrinterChangeNotification(h syscall.Handle, filter uint32, options uint32, notifyOptions *PRINTER_NOTIFY_OPTIONS) (change syscall.Handle, err error) [failretval==syscall.InvalidHandle] = winspool.FindFirstPrinterChangeNotification
+//sys	FindNextPrinterChangeNotification(change syscall.Handle, cause *uint32, notifyOptions *PRINTER_NOTIFY_OPTIONS, info **PRINTER_NOTIFY_INFO) (err error) = winspool.FindNextPrinterChangeNotification
+//sys	FindClosePrinterChangeNotification(change syscall.Handle) (err error) = winspool.FindClosePrinterChangeNotification
+//sys	FreePrinterNotifyInfo(info *PRINTER_NOTIFY_INFO) (err error) = winspool.FreePrinterNotifyInfo
+
+const (
+	JOB_CONTROL_PAUSE   = 1
+	JOB_CONTROL_RESUME  = 2
+	JOB_CONTROL_CANCEL  = 3
+	JOB_CONTROL_RESTART = 4
+	JOB_CONTROL_DELETE  = 5
+)
+
+// CancelJob deletes the print job id from the queue of p.
+func (p *Printer) CancelJob(id uint32) error {
+	return p.jobAccessDenied("CancelJob", id, SetJob(p.h, id, 0, nil, JOB_CONTROL_DELETE))
+}
+
+// PauseJob pauses the print job id.
+func (p *Printer) PauseJob(id uint32) error {
+	return p.jobAccessDenied("PauseJob", id, SetJob(p.h, id, 0, nil, JOB_CONTROL_PAUSE))
+}
+
+// ResumeJob resumes the print job id paused with PauseJob.
+func (p *Printer) ResumeJob(id uint32) error {
+	return p.jobAccessDenied("ResumeJob", id, SetJob(p.h, id, 0, nil, JOB_CONTROL_RESUME))
+}
+
+// RestartJob prints the job id again from its start. Jobs that have
+// printed can only be restarted if the queue keeps them, see
+// SetKeepPrintedJobs.
+func (p *Printer) RestartJob(id uint32) error {
+	return p.jobAccessDenied("RestartJob", id, SetJob(p.h, id, 0, nil, JOB_CONTROL_RESTART))
+}
+
+const (
+	JOB_NOTIFY_TYPE = 1
+
+	JOB_NOTIFY_FIELD_TOTAL_BYTES   = 0x16
+	JOB_NOTIFY_FIELD_BYTES_PRINTED = 0x17
+
+	PRINTER_NOTIFY_OPTIONS_REFRESH = 1
+)
+
+type PRINTER_NOTIFY_OPTIONS_TYPE struct {
+	Type      uint16
+	Reserved0 uint16
+	Reserved1 uint32
+	Reserved2 uint32
+	Count     uint32
+	Fields    *uint16
+}
+
+type PRINTER_NOTIFY_OPTIONS struct {
+	Version uint32
+	Flags   uint32
+	Count   uint32
+	Types   *PRINTER_NOTIFY_OPTIONS_TYPE
+}
+
+type PRINTER_NOTIFY_INFO_DATA struct {
+	Type     uint16
+	Field    uint16
+	Reserved uint32
+	Id       uint32
+	// NotifyData union: two DWORDs, or a size and a pointer
+	Data [2]uintptr
+}
+
+type PRINTER_NOTIFY_INFO struct {
+	Version uint32
+	Flags   uint32
+	Count   uint32
+	Data    [1]PRINTER_NOTIFY_INFO_DATA
+}
+
+// jobBytesPrinted returns the bytes printed of every job of p, which
+// JOB_INFO_2 lacks, from a snapshot of the job change notifications. The
+// notification is opened on first use and kept until p is closed.
+func (p *Printer) jobBytesPrinted() (map[uint32]uint32, error) {
+	fields := []uint16{JOB_NOTIFY_FIELD_BYTES_PRINTED}
+	typ := PRINTER_NOTIFY_OPTIONS_TYPE{
+		Type:   JOB_NOTIFY_TYPE,
+		Count:  uint32(len(fields)),
+		Fields: &fields[0],
+	}
+	opts := PRINTER_NOTIFY_OPTIONS{Version: 2, Count: 1, Types: &typ}
+	p.notifyMu.Lock()
+	defer p.notifyMu.Unlock()
+	if p.notify == 0 {
+		change, err := FindFirstPrinterChangeNotification(p.h, 0, 0, &opts)
+		if err != nil {
+			return nil, err
+		}
+		p.notify = change
+	}
+	opts.Flags = PRINTER_NOTIFY_OPTIONS_REFRESH
+	var cause uint32
+	var info *PRINTER_NOTIFY_INFO
+	if err := FindNextPrinterChangeNotification(p.notify, &cause, &opts, &info); err != nil {
+		// open a new notification next time
+		FindClosePrinterChangeNotification(p.notify)
+		p.notify = 0
+		return nil, err
+	}
+	if info == nil {
+		return nil, nil
+	}
+	defer FreePrinterNotifyInfo(info)
+	printed := make(map[uint32]uint32)
+	size := unsafe.Sizeof(info.Data[0])
+	for i := 0; i < int(info.Count); i++ {
+		d := (*PRINTER_NOTIFY_INFO_DATA)(unsafe.Pointer(uintptr(unsafe.Pointer(&info.Data[0])) + uintptr(i)*size))
+		if d.Type == JOB_NOTIFY_TYPE && d.Field == JOB_NOTIFY_FIELD_BYTES_PRINTED {
+			printed[d.Id] = uint32(d.Data[0])
+		}
+	}
+	return printed, nil
+}
+
+// closeJobNotify closes the notification opened by jobBytesPrinted.
+func (p *Printer) closeJobNotify() {
+	p.notifyMu.Lock()
+	defer p.notifyMu.Unlock()
+	if p.notify != 0 {
+		FindClosePrinterChangeNotification(p.notify)
+		p.notify = 0
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "strings"
+
+// JobStatus is the status of a print job, a combination of the
+// JOB_STATUS_ flags.
+type JobStatus uint32
+
+var jobStatusNames = []struct {
+	flag JobStatus
+	name string
+}{
+	{JOB_STATUS_PRINTING, "Printing"},
+	{JOB_STATUS_PAUSED, "Paused"},
+	{JOB_STATUS_ERROR, "Error"},
+	{JOB_STATUS_DELETING, "Deleting"},
+	{JOB_STATUS_SPOOLING, "Spooling"},
+	{JOB_STATUS_OFFLINE, "Printer Offline"},
+	{JOB_STATUS_PAPEROUT, "Out of Paper"},
+	{JOB_STATUS_PRINTED, "Printed"},
+	{JOB_STATUS_DELETED, "Deleted"},
+	{JOB_STATUS_BLOCKED_DEVQ, "Driver Error"},
+	{JOB_STATUS_USER_INTERVENTION, "User Action Required"},
+	{JOB_STATUS_RESTART, "Restarted"},
+	{JOB_STATUS_COMPLETE, "Sent to Printer"},
+	{JOB_STATUS_RETAINED, "Retained"},
+	{JOB_STATUS_RENDERING_LOCALLY, "Rendering on Client"},
+}
+
+// Has reports whether all the flags of f are set in s.
+func (s JobStatus) Has(f JobStatus) bool {
+	return s&f == f
+}
+
+// IsPrinting reports whether the job is being sent to the printer.
+func (s JobStatus) IsPrinting() bool {
+	return s&JOB_STATUS_PRINTING != 0
+}
+
+// IsError reports whether the job cannot print until the error, such as
+// the printer being offline or out of paper, is resolved.
+func (s JobStatus) IsError() bool {
+	return s&jobErrorStatus != 0
+}
+
+// IsFinished reports whether the job has printed or was deleted.
+func (s JobStatus) IsFinished() bool {
+	return s&(JOB_STATUS_PRINTED|JOB_STATUS_COMPLETE|JOB_STATUS_DELETED) != 0
+}
+
+// String describes the flags of s, such as "Printing, Out of Paper". A
+// job with no flag set is waiting in the queue and described as "Queued".
+func (s JobStatus) String() string {
+	if s == 0 {
+		return "Queued"
+	}
+	var names []string
+	for _, n := range jobStatusNames {
+		if s&n.flag != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,33 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestJobStatus(t *testing.T) {
+	tests := []struct {
+		status                     JobStatus
+		printing, failed, finished bool
+		text                       string
+	}{
+		{0, false, false, false, "Queued"},
+		{JOB_STATUS_SPOOLING, false, false, false, "Spooling"},
+		{JOB_STATUS_PRINTING | JOB_STATUS_PAPEROUT, true, true, false, "Printing, Out of Paper"},
+		{JOB_STATUS_PRINTED, false, false, true, "Printed"},
+		{JOB_STATUS_DELETING | JOB_STATUS_DELETED, false, false, true, "Deleting, Deleted"},
+	}
+	for _, tt := range tests {
+		s := tt.status
+		if s.IsPrinting() != tt.printing || s.IsError() != tt.failed || s.IsFinished() != tt.finished {
+			t.Errorf("%#x: IsPrinting, IsError, IsFinished = %v, %v, %v", uint32(s), s.IsPrinting(), s.IsError(), s.IsFinished())
+		}
+		if got := s.String(); got != tt.text {
+			t.Errorf("%#x: String() = %q, want %q", uint32(s), got, tt.text)
+		}
+	}
+	if s := JobStatus(JOB_STATUS_PAUSED | JOB_STATUS_ERROR); !s.Has(JOB_STATUS_PAUSED) || s.Has(JOB_STATUS_PAUSED|JOB_STATUS_PRINTING) {
+		t.Error("Has is wrong")
+	}
+}
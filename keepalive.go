@@ -0,0 +1,76 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"time"
+)
+
+// Health is the result of a keepalive probe of a printer.
+type Health struct {
+	Healthy bool
+	Status  Status    // valid if Err is nil
+	Err     error     // why the probe failed
+	Time    time.Time // of the probe
+}
+
+// Keepalive probes the status of p every interval the connection stays
+// idle, so a printer that was switched off or went offline is noticed
+// before the next document is printed rather than by it. onChange, if
+// not nil, is called with the result of the first probe and whenever the
+// printer becomes healthy or unhealthy. A probe waits at most interval
+// for the printer to answer; it clears the read deadline of p when done.
+// Probing stops when stop is called.
+func (p *Printer) Keepalive(interval time.Duration, onChange func(Health)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		first := true
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			h, ok := p.probe(interval)
+			if !ok {
+				continue
+			}
+			p.mu.Lock()
+			changed := first || h.Healthy != p.health.Healthy
+			p.health = h
+			p.mu.Unlock()
+			if changed && onChange != nil {
+				onChange(h)
+			}
+			first = false
+		}
+	}()
+	return func() { close(done) }
+}
+
+// probe queries the status of p if it was idle for interval. It reports
+// whether it did.
+func (p *Printer) probe(interval time.Duration) (Health, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Since(p.lastIO) < interval {
+		return Health{}, false
+	}
+	p.t.SetReadDeadline(time.Now().Add(interval))
+	defer p.t.SetReadDeadline(time.Time{})
+	b, err := p.realtimeStatus(1)
+	h := Health{Status: Status(b), Err: err, Time: time.Now()}
+	h.Healthy = err == nil && h.Status&StatusOffline == 0
+	return h, true
+}
+
+// Health returns the result of the last keepalive probe, see Keepalive.
+func (p *Printer) Health() Health {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.health
+}
@@ -0,0 +1,31 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeepalive(t *testing.T) {
+	ft := &fakeTransport{status: []byte{0x12, 0x12, 0x1A}} // online, online, offline
+	p := NewPrinter("fake", ft)
+	changes := make(chan Health, 10)
+	stop := p.Keepalive(5*time.Millisecond, func(h Health) { changes <- h })
+	defer stop()
+	for _, healthy := range []bool{true, false} {
+		select {
+		case h := <-changes:
+			if h.Healthy != healthy {
+				t.Errorf("got %+v, want healthy %v", h, healthy)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no health change reported")
+		}
+	}
+	if p.Health().Healthy {
+		t.Error("Health reports an offline printer healthy")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Labels printed on receipts, translated by DefaultCatalog. Use them as
+// keys of Receipt.Label.
+const (
+	LabelTotal    = "Total"
+	LabelSubtotal = "Subtotal"
+	LabelChange   = "Change"
+	LabelVAT      = "VAT"
+	LabelCash     = "Cash"
+	LabelCard     = "Card"
+	LabelDiscount = "Discount"
+	LabelThanks   = "Thank you!"
+)
+
+// DefaultCatalog translates the Label constants to German, French,
+// Spanish, Italian, Dutch and Turkish. Applications add their own
+// strings or languages with SetString.
+var DefaultCatalog = newDefaultCatalog()
+
+func newDefaultCatalog() *catalog.Builder {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	keys := []string{LabelTotal, LabelSubtotal, LabelChange, LabelVAT, LabelCash, LabelCard, LabelDiscount, LabelThanks}
+	for lang, msgs := range map[string][]string{
+		"de": {"Summe", "Zwischensumme", "Rückgeld", "MwSt.", "Bar", "Karte", "Rabatt", "Vielen Dank!"},
+		"fr": {"Total", "Sous-total", "Monnaie", "TVA", "Espèces", "Carte", "Remise", "Merci !"},
+		"es": {"Total", "Subtotal", "Cambio", "IVA", "Efectivo", "Tarjeta", "Descuento", "¡Gracias!"},
+		"it": {"Totale", "Subtotale", "Resto", "IVA", "Contanti", "Carta", "Sconto", "Grazie!"},
+		"nl": {"Totaal", "Subtotaal", "Wisselgeld", "BTW", "Contant", "Pin", "Korting", "Bedankt!"},
+		"tr": {"Toplam", "Ara toplam", "Para üstü", "KDV", "Nakit", "Kart", "İndirim", "Teşekkürler!"},
+	} {
+		tag := language.MustParse(lang)
+		for i, key := range keys {
+			b.SetString(tag, key, msgs[i])
+		}
+	}
+	return b
+}
+
+// localeCodePages maps languages to the code page selected with ESC t
+// for their receipts, see LocaleCodePage.
+var localeCodePages = map[string]uint8{
+	"de": CodePagePC858, "fr": CodePagePC858, "es": CodePagePC858, "it": CodePagePC858,
+	"nl": CodePagePC858, "pt": CodePagePC858, "da": CodePagePC858, "sv": CodePagePC858,
+	"no": CodePagePC858, "fi": CodePagePC858,
+	"pl": CodePagePC852, "cs": CodePagePC852, "sk": CodePagePC852, "hu": CodePagePC852,
+	"hr": CodePagePC852, "sl": CodePagePC852,
+	"ru": CodePagePC866, "uk": CodePagePC866, "be": CodePagePC866,
+	"el": CodePageWPC1253,
+	"he": CodePagePC862,
+	"tr": CodePageWPC1254,
+}
+
+// LocaleCodePage returns the code page, as numbered by ESC t, that
+// prints the language of the BCP 47 locale, such as 19 (PC858) for
+// "de-DE". Unknown languages get 0 (PC437).
+func LocaleCodePage(locale string) uint8 {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return 0
+	}
+	base, _ := tag.Base()
+	return localeCodePages[base.String()]
+}
+
+// Label returns the translation of key, such as LabelTotal, in the
+// receipt's Locale, from its Catalog or else DefaultCatalog. Keys with
+// no translation are returned unchanged.
+func (r *Receipt) Label(key string) string {
+	if r.Locale == "" {
+		return key
+	}
+	var cat catalog.Catalog = DefaultCatalog
+	if r.Catalog != nil {
+		cat = r.Catalog
+	}
+	tag, err := language.Parse(r.Locale)
+	if err != nil {
+		return key
+	}
+	return message.NewPrinter(tag, message.Catalog(cat)).Sprintf(key)
+}
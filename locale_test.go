@@ -0,0 +1,69 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		locale, key, want string
+	}{
+		{"", LabelTotal, "Total"},
+		{"de-DE", LabelChange, "Rückgeld"},
+		{"de-CH", LabelVAT, "MwSt."},
+		{"tr-TR", LabelTotal, "Toplam"},
+		{"en-GB", LabelChange, "Change"},
+		{"ja-JP", LabelTotal, "Total"},
+		{"de-DE", "Table", "Table"},
+	}
+	for _, tt := range tests {
+		r := NewReceipt("")
+		r.Locale = tt.locale
+		if got := r.Label(tt.key); got != tt.want {
+			t.Errorf("%s: Label(%q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+
+	cat := catalog.NewBuilder()
+	cat.SetString(language.German, "Table", "Tisch")
+	r := NewReceipt("")
+	r.Locale, r.Catalog = "de", cat
+	if got := r.Label("Table"); got != "Tisch" {
+		t.Errorf("custom catalog: got %q", got)
+	}
+}
+
+func TestLocaleCodePage(t *testing.T) {
+	for locale, want := range map[string]uint8{"de-DE": 19, "tr": 48, "ru-RU": 17, "en-US": 0, "xx": 0, "": 0} {
+		if got := LocaleCodePage(locale); got != want {
+			t.Errorf("LocaleCodePage(%q) = %d, want %d", locale, got, want)
+		}
+	}
+}
+
+func TestReceiptLocale(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	r := NewReceipt("")
+	r.Locale = "de-DE"
+	r.TwoColumns(r.Label(LabelChange), "5,00 €")
+	if err := r.Render(p); err != nil {
+		t.Fatal(err)
+	}
+	out := ft.String()
+	if !strings.Contains(out, "\x1Bt\x13") {
+		t.Errorf("code page PC858 not selected in %q", out)
+	}
+	// ü and € in PC858
+	if !strings.Contains(out, "R\x81ckgeld") || !strings.Contains(out, "5,00 \xD5") {
+		t.Errorf("text not encoded in PC858: %q", out)
+	}
+}
@@ -73,6 +73,14 @@ func trim(s string) string {
 
 var packageName string
 
+// dllext returns file name extension of the dll.
+func dllext(dll string) string {
+	if dll == "winspool" {
+		return ".drv"
+	}
+	return ".dll"
+}
+
 func packagename() string {
 	return packageName
 }
@@ -679,6 +687,7 @@ func (src *Source) Generate(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"packagename": packagename,
 		"syscalldot":  syscalldot,
+		"dllext":      dllext,
 	}
 	t := template.Must(template.New("main").Funcs(funcMap).Parse(srcTemplate))
 	err := t.Execute(w, src)
@@ -746,7 +755,7 @@ var (
 
 {{/* help functions */}}
 
-{{define "dlls"}}{{range .DLLs}}	mod{{.}} = {{syscalldot}}NewLazyDLL("{{.}}.drv")
+{{define "dlls"}}{{range .DLLs}}	mod{{.}} = {{syscalldot}}NewLazyDLL("{{.}}{{dllext .}}")
 {{end}}{{end}}
 
 {{define "funcnames"}}{{range .Funcs}}	proc{{.DLLFuncName}} = mod{{.DLLName}}.NewProc("{{.DLLFuncName}}")
@@ -0,0 +1,88 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"net"
+)
+
+// NetConfig is the configuration of the Ethernet interface of a printer,
+// see Printer.ConfigureNetwork.
+type NetConfig struct {
+	// DHCP gets the address from a DHCP server; IP, Mask and Gateway
+	// are then ignored.
+	DHCP    bool
+	IP      net.IP
+	Mask    net.IPMask
+	Gateway net.IP // nil for none
+	// Serial, if set, is the serial number the printer must report for
+	// the configuration to be sent, so a stale address does not
+	// reconfigure another printer.
+	Serial string
+}
+
+// Validate reports whether c is a usable IPv4 configuration.
+func (c *NetConfig) Validate() error {
+	if c.DHCP {
+		return nil
+	}
+	ip := c.IP.To4()
+	if ip == nil || ip.IsUnspecified() || ip.IsMulticast() || ip.Equal(net.IPv4bcast) {
+		return fmt.Errorf("printer: invalid IP address %v", c.IP)
+	}
+	ones, bits := c.Mask.Size()
+	if bits != 32 || ones == 0 || ones > 30 {
+		return fmt.Errorf("printer: invalid network mask %v", c.Mask)
+	}
+	network := &net.IPNet{IP: ip.Mask(c.Mask), Mask: c.Mask}
+	if ip.Equal(network.IP) {
+		return fmt.Errorf("printer: %v is the network address of %v", ip, network)
+	}
+	if c.Gateway != nil && (c.Gateway.To4() == nil || !network.Contains(c.Gateway) || c.Gateway.Equal(ip)) {
+		return fmt.Errorf("printer: gateway %v not in %v", c.Gateway, network)
+	}
+	return nil
+}
+
+// NetConfigEncoder returns the utility commands of a printer model
+// setting its interface to c, sent in user setting mode, see
+// Profile.NetConfig.
+type NetConfigEncoder func(c *NetConfig) ([]byte, error)
+
+// ConfigureNetwork sets the Ethernet interface of the printer to c, so
+// receipt printers can be provisioned without their configuration
+// utility. The commands come from the profile's NetConfig; it returns
+// ErrUnsupported if the profile has none. c is validated and its Serial
+// checked first. The printer restarts when it leaves user setting mode,
+// which drops network connections: p should be closed afterwards and
+// the printer reopened at its new address.
+func (p *Printer) ConfigureNetwork(c *NetConfig) error {
+	encode := p.profile().NetConfig
+	if encode == nil {
+		return ErrUnsupported
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.Serial != "" {
+		s, err := p.SerialNumber()
+		if err != nil {
+			return fmt.Errorf("printer: cannot check serial number: %w", err)
+		}
+		if s != c.Serial {
+			return fmt.Errorf("printer: serial number is %s, not %s", s, c.Serial)
+		}
+	}
+	settings, err := encode(c)
+	if err != nil {
+		return err
+	}
+	b := []byte{gs, '(', 'E', 3, 0, 1, 'I', 'N'} // start user setting mode
+	b = append(b, settings...)
+	b = append(b, gs, '(', 'E', 4, 0, 2, 'O', 'U', 'T') // end it, restarting
+	_, err = p.Write(b)
+	return err
+}
@@ -0,0 +1,61 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestNetConfigValidate(t *testing.T) {
+	mask := net.CIDRMask(24, 32)
+	tests := []struct {
+		c  NetConfig
+		ok bool
+	}{
+		{NetConfig{DHCP: true}, true},
+		{NetConfig{IP: net.IPv4(10, 0, 0, 5), Mask: mask, Gateway: net.IPv4(10, 0, 0, 1)}, true},
+		{NetConfig{IP: net.IPv4(10, 0, 0, 5), Mask: mask}, true},
+		{NetConfig{}, false},
+		{NetConfig{IP: net.IPv4(10, 0, 0, 0), Mask: mask}, false},
+		{NetConfig{IP: net.IPv4(10, 0, 0, 5), Mask: net.CIDRMask(32, 32)}, false},
+		{NetConfig{IP: net.IPv4(10, 0, 0, 5), Mask: mask, Gateway: net.IPv4(10, 0, 1, 1)}, false},
+		{NetConfig{IP: net.ParseIP("fe80::1"), Mask: mask}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.c.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%+v: got %v", tt.c, err)
+		}
+	}
+}
+
+func TestConfigureNetwork(t *testing.T) {
+	ft := &fakeTransport{status: []byte("_X5E7012345\x00")}
+	p := NewPrinter("fake", ft)
+	c := &NetConfig{DHCP: true, Serial: "X5E7012345"}
+	if err := p.ConfigureNetwork(c); err != ErrUnsupported {
+		t.Errorf("got %v, want ErrUnsupported", err)
+	}
+	p.Profile = &Profile{NetConfig: func(c *NetConfig) ([]byte, error) {
+		return []byte("[dhcp]"), nil
+	}}
+	if err := p.ConfigureNetwork(c); err != nil {
+		t.Fatal(err)
+	}
+	want := "\x1DID\x1D(E\x03\x00\x01IN[dhcp]\x1D(E\x04\x00\x02OUT"
+	if got := ft.String(); got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+
+	ft = &fakeTransport{status: []byte("_OTHER\x00")}
+	p.t = ft
+	if err := p.ConfigureNetwork(c); err == nil || !strings.Contains(err.Error(), "serial") {
+		t.Errorf("got %v, want a serial number mismatch", err)
+	}
+	if ft.Len() != 3 {
+		t.Errorf("wrote %q after the mismatch", ft.String())
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"sync"
+)
+
+// NodeHandler prints a node of a document, given its parameters and
+// content, see WriteNode.
+type NodeHandler func(p *Printer, params map[string]string, data string) error
+
+var (
+	nodeHandlersMu sync.RWMutex
+	nodeHandlers   = map[string]NodeHandler{
+		"text": func(p *Printer, params map[string]string, data string) error {
+			return p.TextParams(params, data)
+		},
+		"feed": func(p *Printer, params map[string]string, data string) error {
+			o, err := feedOptions(params)
+			if err != nil {
+				return err
+			}
+			return p.Feed(o)
+		},
+		"cut": func(p *Printer, params map[string]string, data string) error {
+			p.FeedAndCut(params)
+			return nil
+		},
+		"pulse": func(p *Printer, params map[string]string, data string) error {
+			p.Pulse()
+			return nil
+		},
+		"beep": func(p *Printer, params map[string]string, data string) error {
+			if e, ok := params["event"]; ok {
+				return p.Alert(e)
+			}
+			pat, err := ParseBeepPattern(data)
+			if err != nil {
+				return err
+			}
+			return p.PlayBeeps(pat)
+		},
+		"image": func(p *Printer, params map[string]string, data string) error {
+			p.Image(params, data)
+			return nil
+		},
+	}
+)
+
+// RegisterNode makes WriteNode print the nodes called name with h, so
+// applications can add their own nodes, such as "logo" or "table", to the
+// documents they print. Registering a name again replaces its handler,
+// including the built-in text, feed, cut, pulse, beep and image
+// handlers.
+func RegisterNode(name string, h NodeHandler) {
+	if name == "" || h == nil {
+		panic("printer: invalid RegisterNode of " + name)
+	}
+	nodeHandlersMu.Lock()
+	defer nodeHandlersMu.Unlock()
+	nodeHandlers[name] = h
+}
+
+// nodeHandler returns the handler of the nodes called name.
+func nodeHandler(name string) (NodeHandler, bool) {
+	nodeHandlersMu.RLock()
+	defer nodeHandlersMu.RUnlock()
+	h, ok := nodeHandlers[name]
+	return h, ok
+}
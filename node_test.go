@@ -0,0 +1,39 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"testing"
+)
+
+func TestRegisterNode(t *testing.T) {
+	RegisterNode("logo", func(p *Printer, params map[string]string, data string) error {
+		_, err := p.WriteString("[" + params["name"] + "]")
+		return err
+	})
+	defer func() {
+		nodeHandlersMu.Lock()
+		delete(nodeHandlers, "logo")
+		nodeHandlersMu.Unlock()
+	}()
+
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	if err := p.WriteNode("logo", map[string]string{"name": "shop"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.WriteNode("pulse", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ft.String(), "[shop]\x1Bp\x00\x19\xfa"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+	if err := p.WriteNode("table", nil, ""); err == nil {
+		t.Error("no error for unknown node")
+	}
+	if err := p.WriteNode("feed", map[string]string{"line": "x"}, ""); err == nil {
+		t.Error("no error for invalid feed node")
+	}
+}
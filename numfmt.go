@@ -0,0 +1,148 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// NumberFormat describes how numbers are written in a locale.
+type NumberFormat struct {
+	Decimal   string // decimal separator
+	Thousands string // thousands separator, may be empty
+	Decimals  int    // digits after the decimal separator
+}
+
+// CurrencyFormat describes how amounts of money are written in a locale.
+type CurrencyFormat struct {
+	Number NumberFormat
+	Symbol string
+	// SymbolAfter places the symbol after the amount.
+	SymbolAfter bool
+	// Space separates the symbol from the amount.
+	Space bool
+}
+
+// Currency formats of a few locales, by BCP 47 tag.
+var Currencies = map[string]CurrencyFormat{
+	"en-US": {Number: NumberFormat{".", ",", 2}, Symbol: "$"},
+	"en-GB": {Number: NumberFormat{".", ",", 2}, Symbol: "£"},
+	"de-DE": {Number: NumberFormat{",", ".", 2}, Symbol: "€", SymbolAfter: true, Space: true},
+	"fr-FR": {Number: NumberFormat{",", " ", 2}, Symbol: "€", SymbolAfter: true, Space: true},
+	"nl-NL": {Number: NumberFormat{",", ".", 2}, Symbol: "€", Space: true},
+	"es-ES": {Number: NumberFormat{",", ".", 2}, Symbol: "€", SymbolAfter: true, Space: true},
+	"it-IT": {Number: NumberFormat{",", ".", 2}, Symbol: "€", SymbolAfter: true, Space: true},
+	"tr-TR": {Number: NumberFormat{",", ".", 2}, Symbol: "₺"},
+	"ja-JP": {Number: NumberFormat{".", ",", 0}, Symbol: "¥"},
+	"de-CH": {Number: NumberFormat{".", "'", 2}, Symbol: "CHF", Space: true},
+}
+
+// Format formats v, rounded to f.Decimals digits.
+func (f NumberFormat) Format(v float64) string {
+	neg := v < 0
+	s := strconv.FormatFloat(math.Abs(v), 'f', f.Decimals, 64)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+	var b strings.Builder
+	if neg && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(f.Thousands)
+		}
+		b.WriteRune(c)
+	}
+	if frac != "" {
+		b.WriteString(f.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// Format formats the amount v with the currency symbol.
+func (f CurrencyFormat) Format(v float64) string {
+	n := f.Number.Format(v)
+	sep := ""
+	if f.Space {
+		sep = " "
+	}
+	if f.SymbolAfter {
+		return n + sep + f.Symbol
+	}
+	if strings.HasPrefix(n, "-") {
+		return "-" + f.Symbol + sep + n[1:]
+	}
+	return f.Symbol + sep + n
+}
+
+// PadLeft right-aligns s in a column width characters wide, so numbers
+// line up. s is returned unchanged if it is wider.
+func PadLeft(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// PadRight left-aligns s in a column width characters wide.
+func PadRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// formatRow lays out cells in the columns cols on a line total characters
+// wide. Column widths are in characters; columns with no width share the
+// remaining space. Cells are cut to their column width.
+func formatRow(cols []Column, cells []string, total int) string {
+	widths := make([]int, len(cols))
+	rest, flex := total, 0
+	for i, col := range cols {
+		widths[i] = col.Width
+		if col.Width <= 0 {
+			flex++
+		}
+		rest -= col.Width
+	}
+	for i, col := range cols {
+		if col.Width <= 0 && flex > 0 {
+			widths[i] = rest / flex
+			rest -= widths[i]
+			flex--
+		}
+	}
+	var b strings.Builder
+	for i, col := range cols {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		w := widths[i]
+		if w <= 0 {
+			continue
+		}
+		if r := []rune(cell); len(r) > w {
+			cell = string(r[:w])
+		}
+		switch col.Align {
+		case AlignRight:
+			cell = PadLeft(cell, w)
+		case AlignCenter:
+			pad := w - utf8.RuneCountInString(cell)
+			cell = PadRight(strings.Repeat(" ", pad/2)+cell, w)
+		default:
+			cell = PadRight(cell, w)
+		}
+		b.WriteString(cell)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
@@ -0,0 +1,46 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestCurrencyFormat(t *testing.T) {
+	tests := []struct {
+		locale string
+		v      float64
+		want   string
+	}{
+		{"en-US", 1234567.891, "$1,234,567.89"},
+		{"en-US", -12.5, "-$12.50"},
+		{"de-DE", 1234.5, "1.234,50 €"},
+		{"fr-FR", 999.999, "1 000,00 €"},
+		{"ja-JP", 1500, "¥1,500"},
+		{"en-US", -0.001, "$0.00"},
+	}
+	for _, test := range tests {
+		if got := Currencies[test.locale].Format(test.v); got != test.want {
+			t.Errorf("%s: Format(%v) = %q, want %q", test.locale, test.v, got, test.want)
+		}
+	}
+}
+
+func TestFormatRow(t *testing.T) {
+	tests := []struct {
+		cols  []Column
+		cells []string
+		total int
+		want  string
+	}{
+		{[]Column{{}, {Width: 8, Align: AlignRight}}, []string{"Coffee", "3.50"}, 20, "Coffee          3.50"},
+		{[]Column{{}, {Width: 8, Align: AlignRight}}, []string{"A very long item name", "3.50"}, 20, "A very long     3.50"},
+		{[]Column{{Width: 3}, {}, {Width: 6, Align: AlignRight}}, []string{"2x", "Tea", "4.00"}, 16, "2x Tea      4.00"},
+		{[]Column{{Width: 10, Align: AlignCenter}}, []string{"mid"}, 10, "   mid"},
+	}
+	for _, test := range tests {
+		if got := formatRow(test.cols, test.cells, test.total); got != test.want {
+			t.Errorf("formatRow(%v, %q, %d) = %q, want %q", test.cols, test.cells, test.total, got, test.want)
+		}
+	}
+}
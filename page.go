@@ -0,0 +1,62 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+const (
+	DMORIENT_PORTRAIT  = 1
+	DMORIENT_LANDSCAPE = 2
+
+	DMDUP_SIMPLEX    = 1
+	DMDUP_VERTICAL   = 2
+	DMDUP_HORIZONTAL = 3
+)
+
+// Duplex selects single or double-sided printing.
+type Duplex int16
+
+const (
+	Simplex   Duplex = DMDUP_SIMPLEX    // print on one side only
+	LongEdge  Duplex = DMDUP_VERTICAL   // flip pages on the long edge
+	ShortEdge Duplex = DMDUP_HORIZONTAL // flip pages on the short edge
+)
+
+// Orientation selects the direction in which a page is printed.
+type Orientation int16
+
+const (
+	Portrait  Orientation = DMORIENT_PORTRAIT
+	Landscape Orientation = DMORIENT_LANDSCAPE
+)
+
+// PageOptions controls how pages are rendered through a DC.
+type PageOptions struct {
+	// Orientation of the page. Zero keeps the printer default. Landscape
+	// is rotated in software when the driver does not support it.
+	Orientation Orientation
+
+	// NUp is the number of pages printed on each sheet of paper: 1, 2 or
+	// 4. Zero means 1. With 2-up, pages are rotated to fit side by side.
+	NUp int
+
+	// Layout defines the page margins and the header and footer bands.
+	Layout PageLayout
+}
+
+// PageLayout describes the areas of a page, in points. Margins are
+// measured from the edges of the paper and are never smaller than the
+// area the printer hardware cannot print on.
+type PageLayout struct {
+	Margins Margins
+
+	// HeaderHeight and FooterHeight reserve bands inside the margins at
+	// the top and bottom of every page, see DC.Header and DC.Footer.
+	HeaderHeight int
+	FooterHeight int
+}
+
+// Margins holds the width of the four page margins.
+type Margins struct {
+	Left, Top, Right, Bottom int
+}
@@ -0,0 +1,106 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+)
+
+// pdfWriter builds a minimal PDF document in memory. Object 1 is the
+// catalog and object 2 the page tree; both are written by WriteTo.
+type pdfWriter struct {
+	objects [][]byte // objects[i] is object i+1
+	pages   []int
+}
+
+func newPDFWriter() *pdfWriter {
+	return &pdfWriter{objects: make([][]byte, 2)}
+}
+
+// object adds an object and returns its number.
+func (pw *pdfWriter) object(s string) int {
+	pw.objects = append(pw.objects, []byte(s))
+	return len(pw.objects)
+}
+
+// stream adds a stream object with the given extra dictionary entries,
+// compressing data, and returns its number.
+func (pw *pdfWriter) stream(dict string, data []byte) int {
+	var b bytes.Buffer
+	zw := zlib.NewWriter(&b)
+	zw.Write(data)
+	zw.Close()
+	var obj bytes.Buffer
+	fmt.Fprintf(&obj, "<< %s /Filter /FlateDecode /Length %d >>\nstream\n", dict, b.Len())
+	obj.Write(b.Bytes())
+	obj.WriteString("\nendstream")
+	pw.objects = append(pw.objects, obj.Bytes())
+	return len(pw.objects)
+}
+
+// page adds a width by height points page drawn by content, with the
+// given resource dictionary.
+func (pw *pdfWriter) page(width, height float64, resources string, content []byte) {
+	c := pw.stream("", content)
+	pw.pages = append(pw.pages, pw.object(fmt.Sprintf(
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>",
+		width, height, resources, c)))
+}
+
+// imagePage adds a page showing img, sized at dpi dots per inch.
+func (pw *pdfWriter) imagePage(img image.Image, dpi int) {
+	b := img.Bounds()
+	im := pw.image(img)
+	w := float64(b.Dx()) * 72 / float64(dpi)
+	h := float64(b.Dy()) * 72 / float64(dpi)
+	pw.page(w, h, fmt.Sprintf("<< /XObject << /Im0 %d 0 R >> >>", im),
+		[]byte(fmt.Sprintf("q %.2f 0 0 %.2f 0 0 cm /Im0 Do Q", w, h)))
+}
+
+// image adds img as an image XObject and returns its number.
+func (pw *pdfWriter) image(img image.Image) int {
+	b := img.Bounds()
+	rgb := make([]byte, 0, 3*b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			rgb = append(rgb, byte(r>>8), byte(g>>8), byte(b>>8))
+		}
+	}
+	return pw.stream(fmt.Sprintf(
+		"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8",
+		b.Dx(), b.Dy()), rgb)
+}
+
+// WriteTo writes the document to w.
+func (pw *pdfWriter) WriteTo(w io.Writer) (int64, error) {
+	var kids bytes.Buffer
+	for _, p := range pw.pages {
+		fmt.Fprintf(&kids, "%d 0 R ", p)
+	}
+	pw.objects[0] = []byte("<< /Type /Catalog /Pages 2 0 R >>")
+	pw.objects[1] = []byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids.String(), len(pw.pages)))
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(pw.objects))
+	for i, obj := range pw.objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n", i+1)
+		b.Write(obj)
+		b.WriteString("\nendobj\n")
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(pw.objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.objects)+1, xref)
+	return b.WriteTo(w)
+}
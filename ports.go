@@ -0,0 +1,22 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+// Monitor describes an installed port monitor, such as "Standard TCP/IP
+// Port" or "WSD Port".
+type Monitor struct {
+	Name        string
+	Environment string
+	DLLName     string
+}
+
+// Port describes a printer port.
+type Port struct {
+	Name        string
+	Monitor     string
+	Description string
+	// Type is a combination of the PORT_TYPE_ constants.
+	Type uint32
+}
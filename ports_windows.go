@@ -0,0 +1,167 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type MONITOR_INFO_2 struct {
+	Name        *uint16
+	Environment *uint16
+	DLLName     *uint16
+}
+
+type PORT_INFO_2 struct {
+	PortName    *uint16
+	MonitorName *uint16
+	Description *uint16
+	PortType    uint32
+	Reserved    uint32
+}
+
+const (
+	PORT_TYPE_WRITE        = 0x0001
+	PORT_TYPE_READ         = 0x0002
+	PORT_TYPE_REDIRECTED   = 0x0004
+	PORT_TYPE_NET_ATTACHED = 0x0008
+)
+
+//sys	enumMonitors(server *uint16, level uint32, buf *byte, bufN uint32, needed *uint32, returned *uint32) (err error) = winspool.EnumMonitorsW
+//sys	EnumPorts(server *uint16, level uint32, buf *byte, bufN uint32, needed *uint32, returned *uint32) (err error) = winspool.EnumPortsW
+
+// EnumMonitors returns the port monitors installed on the local machine.
+func EnumMonitors() ([]Monitor, error) {
+	var returned uint32
+	buf, err := spoolCall(func(b []byte, needed *uint32) error {
+		return enumMonitors(nil, 2, &b[0], uint32(len(b)), needed, &returned)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer buf.free()
+	if returned == 0 {
+		return nil, nil
+	}
+	monitors := make([]Monitor, 0, returned)
+	for i := 0; i < int(returned); i++ {
+		m := (*MONITOR_INFO_2)(buf.at(i, unsafe.Sizeof(MONITOR_INFO_2{})))
+		monitors = append(monitors, Monitor{
+			Name:        windows.UTF16PtrToString(m.Name),
+			Environment: windows.UTF16PtrToString(m.Environment),
+			DLLName:     windows.UTF16PtrToString(m.DLLName),
+		})
+	}
+	return monitors, nil
+}
+
+// PrintersOnPort returns the names of the printers bound to port, such as
+// "USB001", "COM3:" or "LPT1:". The trailing colon of port names is
+// ignored.
+func PrintersOnPort(port string) ([]string, error) {
+	printers, err := Enumerate(EnumOptions{Flags: PRINTER_ENUM_LOCAL})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, p := range printers {
+		for _, pp := range splitPorts(p.PortName) {
+			if samePort(pp, port) {
+				names = append(names, p.Name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// OpenByPort opens the printer bound to port. It returns an
+// *AmbiguousNameError if several printers are bound to it.
+func OpenByPort(port string) (*Printer, error) {
+	names, err := PrintersOnPort(port)
+	if err != nil {
+		return nil, err
+	}
+	switch len(names) {
+	case 0:
+		return nil, fmt.Errorf("printer: no printer is bound to port %q", port)
+	case 1:
+		return Open(names[0])
+	}
+	return nil, &AmbiguousNameError{Name: port, Candidates: names}
+}
+
+// splitPorts splits the port list of a printer, which has several ports
+// when printer pooling is enabled.
+func splitPorts(list string) []string {
+	var ports []string
+	for _, port := range strings.Split(list, ",") {
+		if port = strings.TrimSpace(port); port != "" {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+func samePort(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, ":"), strings.TrimSuffix(b, ":"))
+}
+
+// ReadPorts returns the printer ports installed on the local machine.
+func ReadPorts() ([]Port, error) {
+	var returned uint32
+	buf, err := spoolCall(func(b []byte, needed *uint32) error {
+		return EnumPorts(nil, 2, &b[0], uint32(len(b)), needed, &returned)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer buf.free()
+	if returned == 0 {
+		return nil, nil
+	}
+	ports := make([]Port, 0, returned)
+	for i := 0; i < int(returned); i++ {
+		p := (*PORT_INFO_2)(buf.at(i, unsafe.Sizeof(PORT_INFO_2{})))
+		ports = append(ports, Port{
+			Name:        windows.UTF16PtrToString(p.PortName),
+			Monitor:     windows.UTF16PtrToString(p.MonitorName),
+			Description: windows.UTF16PtrToString(p.Description),
+			Type:        p.PortType,
+		})
+	}
+	return ports, nil
+}
+
+// PrinterPorts maps the name of each local printer to the ports it prints
+// to; a printer has several ports when printer pooling is enabled.
+func PrinterPorts() (map[string][]Port, error) {
+	printers, err := Enumerate(EnumOptions{Flags: PRINTER_ENUM_LOCAL})
+	if err != nil {
+		return nil, err
+	}
+	ports, err := ReadPorts()
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string][]Port, len(printers))
+	for _, p := range printers {
+		for _, name := range splitPorts(p.PortName) {
+			port := Port{Name: name}
+			for _, pp := range ports {
+				if samePort(pp.Name, name) {
+					port = pp
+					break
+				}
+			}
+			m[p.Name] = append(m[p.Name], port)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"strings"
+	"sync"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Paper sizes in points, for previews.
+var (
+	PaperA4     = image.Pt(595, 842)
+	PaperLetter = image.Pt(612, 792)
+)
+
+// Preview renders d into one image per page at dpi dots per inch on paper
+// of the given size in points, without printing it. The hardware margins
+// of the printer are unknown, so the whole paper is considered printable;
+// PageOptions.NUp is not applied. Text is drawn with the Go fonts, so line
+// breaks may differ slightly from the printed output.
+func (d *Document) Preview(paper image.Point, dpi int) ([]image.Image, error) {
+	if dpi <= 0 {
+		return nil, fmt.Errorf("printer: invalid preview resolution: %d", dpi)
+	}
+	if d.Options.Orientation == Landscape && paper.X < paper.Y {
+		paper.X, paper.Y = paper.Y, paper.X
+	}
+	c := &previewCanvas{
+		paper:  paper,
+		dpi:    dpi,
+		layout: d.Options.Layout,
+		faces:  make(map[FontSpec]font.Face),
+	}
+	if err := d.render(c); err != nil {
+		return nil, err
+	}
+	return c.pages, nil
+}
+
+// PreviewPDF is like Preview but writes the pages to w as a PDF document.
+func (d *Document) PreviewPDF(w io.Writer, paper image.Point, dpi int) error {
+	pages, err := d.Preview(paper, dpi)
+	if err != nil {
+		return err
+	}
+	pw := newPDFWriter()
+	for _, img := range pages {
+		pw.imagePage(img, dpi)
+	}
+	_, err = pw.WriteTo(w)
+	return err
+}
+
+// previewCanvas draws pages on in-memory images.
+type previewCanvas struct {
+	paper  image.Point // in points
+	dpi    int
+	layout PageLayout
+
+	pages []image.Image
+	page  *image.RGBA
+	faces map[FontSpec]font.Face
+	face  font.Face
+}
+
+// px converts points to pixels.
+func (c *previewCanvas) px(pt int) int {
+	return pt * c.dpi / 72
+}
+
+// pt converts pixels to points, rounding up.
+func (c *previewCanvas) pt(px int) int {
+	return (px*72 + c.dpi - 1) / c.dpi
+}
+
+func (c *previewCanvas) StartPage() error {
+	c.page = image.NewRGBA(image.Rect(0, 0, c.px(c.paper.X), c.px(c.paper.Y)))
+	draw.Draw(c.page, c.page.Bounds(), image.White, image.Point{}, draw.Src)
+	return nil
+}
+
+func (c *previewCanvas) EndPage() error {
+	c.pages = append(c.pages, c.page)
+	return nil
+}
+
+func (c *previewCanvas) Body() image.Rectangle {
+	_, body, _ := pageAreas(c.paper, image.Rectangle{Max: c.paper}, c.layout)
+	return body
+}
+
+func (c *previewCanvas) Header() image.Rectangle {
+	header, _, _ := pageAreas(c.paper, image.Rectangle{Max: c.paper}, c.layout)
+	return header
+}
+
+func (c *previewCanvas) Footer() image.Rectangle {
+	_, _, footer := pageAreas(c.paper, image.Rectangle{Max: c.paper}, c.layout)
+	return footer
+}
+
+func (c *previewCanvas) SetFont(f FontSpec) error {
+	if f.Size <= 0 {
+		return fmt.Errorf("printer: invalid font size: %d", f.Size)
+	}
+	if face, ok := c.faces[f]; ok {
+		c.face = face
+		return nil
+	}
+	ttf, err := previewFont(f)
+	if err != nil {
+		return err
+	}
+	face, err := opentype.NewFace(ttf, &opentype.FaceOptions{
+		Size:    float64(f.Size),
+		DPI:     float64(c.dpi),
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return err
+	}
+	c.faces[f] = face
+	c.face = face
+	return nil
+}
+
+func (c *previewCanvas) MeasureText(s string) (width, height int, err error) {
+	if c.face == nil {
+		return 0, 0, fmt.Errorf("printer: no font selected")
+	}
+	w := font.MeasureString(c.face, s)
+	return c.pt(w.Ceil()), c.pt(c.face.Metrics().Height.Ceil()), nil
+}
+
+func (c *previewCanvas) TextOut(x, y int, s string) error {
+	if c.face == nil {
+		return fmt.Errorf("printer: no font selected")
+	}
+	d := font.Drawer{
+		Dst:  c.page,
+		Src:  image.Black,
+		Face: c.face,
+		Dot:  fixed.P(c.px(x), c.px(y)+c.face.Metrics().Ascent.Ceil()),
+	}
+	d.DrawString(s)
+	return nil
+}
+
+func (c *previewCanvas) DrawImage(r image.Rectangle, img image.Image) error {
+	dst := image.Rect(c.px(r.Min.X), c.px(r.Min.Y), c.px(r.Max.X), c.px(r.Max.Y))
+	xdraw.ApproxBiLinear.Scale(c.page, dst, img, img.Bounds(), draw.Over, nil)
+	return nil
+}
+
+func (c *previewCanvas) Line(x1, y1, x2, y2 int) error {
+	// Bresenham's algorithm
+	x1, y1, x2, y2 = c.px(x1), c.px(y1), c.px(x2), c.px(y2)
+	dx, sx := abs(x2-x1), 1
+	if x1 > x2 {
+		sx = -1
+	}
+	dy, sy := -abs(y2-y1), 1
+	if y1 > y2 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		c.page.Set(x1, y1, color.Black)
+		if x1 == x2 && y1 == y2 {
+			return nil
+		}
+		if 2*e >= dy {
+			e += dy
+			x1 += sx
+		}
+		if 2*e <= dx {
+			e += dx
+			y1 += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+var previewFonts struct {
+	sync.Once
+	fonts map[string]*opentype.Font
+	err   error
+}
+
+// previewFont returns the Go font that best matches f.
+func previewFont(f FontSpec) (*opentype.Font, error) {
+	previewFonts.Do(func() {
+		ttfs := map[string][]byte{
+			"regular":    goregular.TTF,
+			"bold":       gobold.TTF,
+			"italic":     goitalic.TTF,
+			"bolditalic": gobolditalic.TTF,
+			"mono":       gomono.TTF,
+			"monobold":   gomonobold.TTF,
+		}
+		previewFonts.fonts = make(map[string]*opentype.Font)
+		for name, ttf := range ttfs {
+			fnt, err := opentype.Parse(ttf)
+			if err != nil {
+				previewFonts.err = err
+				return
+			}
+			previewFonts.fonts[name] = fnt
+		}
+	})
+	if previewFonts.err != nil {
+		return nil, previewFonts.err
+	}
+	bold := f.Weight >= 600
+	family := strings.ToLower(f.Family)
+	if strings.Contains(family, "mono") || strings.Contains(family, "courier") || strings.Contains(family, "consol") {
+		if bold {
+			return previewFonts.fonts["monobold"], nil
+		}
+		return previewFonts.fonts["mono"], nil
+	}
+	switch {
+	case bold && f.Italic:
+		return previewFonts.fonts["bolditalic"], nil
+	case bold:
+		return previewFonts.fonts["bold"], nil
+	case f.Italic:
+		return previewFonts.fonts["italic"], nil
+	}
+	return previewFonts.fonts["regular"], nil
+}
@@ -2,86 +2,30 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Windows printing.
+// Package printer prints to Windows printer queues and, on any system,
+// to ESC/POS printers reached through a Transport.
 package printer
 
 import (
 	"encoding/base64"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"strconv"
 	"strings"
-	"syscall"
+	"sync"
 	"time"
-	"unsafe"
-
-	"golang.org/x/sys/windows"
 )
 
-//go:generate go run mksyscall_windows.go -output zapi.go printer.go
-
-type DOC_INFO_1 struct {
-	DocName    *uint16
-	OutputFile *uint16
-	Datatype   *uint16
-}
-
-type PRINTER_INFO_5 struct {
-	PrinterName              *uint16
-	PortName                 *uint16
-	Attributes               uint32
-	DeviceNotSelectedTimeout uint32
-	TransmissionRetryTimeout uint32
-}
-
-type DRIVER_INFO_8 struct {
-	Version                  uint32
-	Name                     *uint16
-	Environment              *uint16
-	DriverPath               *uint16
-	DataFile                 *uint16
-	ConfigFile               *uint16
-	HelpFile                 *uint16
-	DependentFiles           *uint16
-	MonitorName              *uint16
-	DefaultDataType          *uint16
-	PreviousNames            *uint16
-	DriverDate               syscall.Filetime
-	DriverVersion            uint64
-	MfgName                  *uint16
-	OEMUrl                   *uint16
-	HardwareID               *uint16
-	Provider                 *uint16
-	PrintProcessor           *uint16
-	VendorSetup              *uint16
-	ColorProfiles            *uint16
-	InfPath                  *uint16
-	PrinterDriverAttributes  uint32
-	CoreDriverDependencies   *uint16
-	MinInboxDriverVerDate    syscall.Filetime
-	MinInboxDriverVerVersion uint32
-}
-
-type JOB_INFO_1 struct {
-	JobID        uint32
-	PrinterName  *uint16
-	MachineName  *uint16
-	UserName     *uint16
-	Document     *uint16
-	DataType     *uint16
-	Status       *uint16
-	StatusCode   uint32
-	Priority     uint32
-	Position     uint32
-	TotalPages   uint32
-	PagesPrinted uint32
-	Submitted    syscall.Systemtime
-}
+//go:generate go run mksyscall_windows.go -output zapi_windows.go printer_windows.go devmode_windows.go gdi_windows.go driver_windows.go driverfiles_windows.go ports_windows.go printerdata_windows.go admin_windows.go spool_windows.go deviceid_windows.go transport_windows.go serial_windows.go jobs_windows.go
 
 const (
-	PRINTER_ENUM_LOCAL       = 2
-	PRINTER_ENUM_CONNECTIONS = 4
+	PRINTER_ENUM_DEFAULT     = 0x00000001
+	PRINTER_ENUM_LOCAL       = 0x00000002
+	PRINTER_ENUM_CONNECTIONS = 0x00000004
+	PRINTER_ENUM_NAME        = 0x00000008
+	PRINTER_ENUM_REMOTE      = 0x00000010
+	PRINTER_ENUM_SHARED      = 0x00000020
+	PRINTER_ENUM_NETWORK     = 0x00000040
 
 	PRINTER_DRIVER_XPS = 0x00000002
 )
@@ -111,75 +55,32 @@ const (
 	QRCodeErrorCorrectionLevelH  uint8 = 51
 )
 
-//sys	GetDefaultPrinter(buf *uint16, bufN *uint32) (err error) = winspool.GetDefaultPrinterW
-//sys	ClosePrinter(h syscall.Handle) (err error) = winspool.ClosePrinter
-//sys	OpenPrinter(name *uint16, h *syscall.Handle, defaults uintptr) (err error) = winspool.OpenPrinterW
-//sys	StartDocPrinter(h syscall.Handle, level uint32, docinfo *DOC_INFO_1) (err error) = winspool.StartDocPrinterW
-//sys	EndDocPrinter(h syscall.Handle) (err error) = winspool.EndDocPrinter
-//sys	WritePrinter(h syscall.Handle, buf *byte, bufN uint32, written *uint32) (err error) = winspool.WritePrinter
-//sys	StartPagePrinter(h syscall.Handle) (err error) = winspool.StartPagePrinter
-//sys	EndPagePrinter(h syscall.Handle) (err error) = winspool.EndPagePrinter
-//sys	EnumPrinters(flags uint32, name *uint16, level uint32, buf *byte, bufN uint32, needed *uint32, returned *uint32) (err error) = winspool.EnumPrintersW
-//sys	GetPrinterDriver(h syscall.Handle, env *uint16, level uint32, di *byte, n uint32, needed *uint32) (err error) = winspool.GetPrinterDriverW
-//sys	EnumJobs(h syscall.Handle, firstJob uint32, noJobs uint32, level uint32, buf *byte, bufN uint32, bytesNeeded *uint32, jobsReturned *uint32) (err error) = winspool.EnumJobsW
-
-func Default() (string, error) {
-	b := make([]uint16, 3)
-	n := uint32(len(b))
-	err := GetDefaultPrinter(&b[0], &n)
-	if err != nil {
-		if err != syscall.ERROR_INSUFFICIENT_BUFFER {
-			return "", err
-		}
-		b = make([]uint16, n)
-		err = GetDefaultPrinter(&b[0], &n)
-		if err != nil {
-			return "", err
-		}
-	}
-	return syscall.UTF16ToString(b), nil
-}
-
 // ReadNames return printer names on the system
 func ReadNames() ([]string, error) {
-	const flags = PRINTER_ENUM_LOCAL | PRINTER_ENUM_CONNECTIONS
-	var needed, returned uint32
-	buf := make([]byte, 1)
-	err := EnumPrinters(flags, nil, 5, &buf[0], uint32(len(buf)), &needed, &returned)
+	printers, err := Enumerate(EnumOptions{})
 	if err != nil {
-		if err != syscall.ERROR_INSUFFICIENT_BUFFER {
-			return nil, err
-		}
-		buf = make([]byte, needed)
-		err = EnumPrinters(flags, nil, 5, &buf[0], uint32(len(buf)), &needed, &returned)
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
-	ps := (*[1024]PRINTER_INFO_5)(unsafe.Pointer(&buf[0]))[:returned:returned]
-	names := make([]string, 0, returned)
-	for _, p := range ps {
-		names = append(names, windows.UTF16PtrToString(p.PrinterName))
+	names := make([]string, 0, len(printers))
+	for _, p := range printers {
+		names = append(names, p.Name)
 	}
 	return names, nil
 }
 
-func Open(name string) (*Printer, error) {
-	var p Printer
-	// TODO: implement pDefault parameter
-	err := OpenPrinter(&(syscall.StringToUTF16(name))[0], &p.h, 0)
-	if err != nil {
-		return nil, err
-	}
-	return &p, nil
-}
-
 // DriverInfo stores information about printer driver.
 type DriverInfo struct {
-	Name        string
-	Environment string
-	DriverPath  string
-	Attributes  uint32
+	Name           string
+	Environment    string
+	DriverPath     string
+	DataFile       string
+	ConfigFile     string
+	HelpFile       string
+	DependentFiles []string
+	Attributes     uint32
+	Version        uint32
+	DriverDate     time.Time
+	DriverVersion  uint64
 }
 
 // JobInfo stores information about a print job.
@@ -190,214 +91,326 @@ type JobInfo struct {
 	DocumentName    string
 	DataType        string
 	Status          string
-	StatusCode      uint32
+	StatusCode      JobStatus
 	Priority        uint32
 	Position        uint32
 	TotalPages      uint32
 	PagesPrinted    uint32
-	Submitted       time.Time
+	// TotalBytes is the size of the spooled job, which grows while the
+	// job is spooling. BytesPrinted is how much of it was sent to the
+	// printer, zero if the spooler does not report it.
+	TotalBytes   uint32
+	BytesPrinted uint32
+	// Submitted is when the job was queued, interpreting SubmittedRaw in
+	// Printer.SpoolerTimeZone.
+	Submitted    time.Time
+	SubmittedRaw SystemTime
+	// StartTime and UntilTime are the minutes after midnight UTC between
+	// which the job may print; they are equal if it always may.
+	StartTime uint32
+	UntilTime uint32
+	// Elapsed is the time since the job started printing.
+	Elapsed time.Duration
 }
 
-// Jobs returns information about all print jobs on this printer
-func (p *Printer) Jobs() ([]JobInfo, error) {
-	var bytesNeeded, jobsReturned uint32
-	buf := make([]byte, 1)
-	for {
-		err := EnumJobs(p.h, 0, 255, 1, &buf[0], uint32(len(buf)), &bytesNeeded, &jobsReturned)
-		if err == nil {
-			break
-		}
-		if err != syscall.ERROR_INSUFFICIENT_BUFFER {
-			return nil, err
-		}
-		if bytesNeeded <= uint32(len(buf)) {
-			return nil, err
-		}
-		buf = make([]byte, bytesNeeded)
-	}
-	if jobsReturned <= 0 {
-		return nil, nil
-	}
-	pjs := make([]JobInfo, 0, jobsReturned)
-	ji := (*[2048]JOB_INFO_1)(unsafe.Pointer(&buf[0]))[:jobsReturned:jobsReturned]
-	for _, j := range ji {
-		pji := JobInfo{
-			JobID:        j.JobID,
-			StatusCode:   j.StatusCode,
-			Priority:     j.Priority,
-			Position:     j.Position,
-			TotalPages:   j.TotalPages,
-			PagesPrinted: j.PagesPrinted,
-		}
-		if j.MachineName != nil {
-			pji.UserMachineName = windows.UTF16PtrToString(j.MachineName)
-		}
-		if j.UserName != nil {
-			pji.UserName = windows.UTF16PtrToString(j.UserName)
-		}
-		if j.Document != nil {
-			pji.DocumentName = windows.UTF16PtrToString(j.Document)
-		}
-		if j.DataType != nil {
-			pji.DataType = windows.UTF16PtrToString(j.DataType)
-		}
-		if j.Status != nil {
-			pji.Status = windows.UTF16PtrToString(j.Status)
-		}
-		if strings.TrimSpace(pji.Status) == "" {
-			if pji.StatusCode == 0 {
-				pji.Status += "Queue Paused, "
-			}
-			if pji.StatusCode&JOB_STATUS_PRINTING != 0 {
-				pji.Status += "Printing, "
-			}
-			if pji.StatusCode&JOB_STATUS_PAUSED != 0 {
-				pji.Status += "Paused, "
-			}
-			if pji.StatusCode&JOB_STATUS_ERROR != 0 {
-				pji.Status += "Error, "
-			}
-			if pji.StatusCode&JOB_STATUS_DELETING != 0 {
-				pji.Status += "Deleting, "
-			}
-			if pji.StatusCode&JOB_STATUS_SPOOLING != 0 {
-				pji.Status += "Spooling, "
-			}
-			if pji.StatusCode&JOB_STATUS_OFFLINE != 0 {
-				pji.Status += "Printer Offline, "
-			}
-			if pji.StatusCode&JOB_STATUS_PAPEROUT != 0 {
-				pji.Status += "Out of Paper, "
-			}
-			if pji.StatusCode&JOB_STATUS_PRINTED != 0 {
-				pji.Status += "Printed, "
-			}
-			if pji.StatusCode&JOB_STATUS_DELETED != 0 {
-				pji.Status += "Deleted, "
-			}
-			if pji.StatusCode&JOB_STATUS_BLOCKED_DEVQ != 0 {
-				pji.Status += "Driver Error, "
-			}
-			if pji.StatusCode&JOB_STATUS_USER_INTERVENTION != 0 {
-				pji.Status += "User Action Required, "
-			}
-			if pji.StatusCode&JOB_STATUS_RESTART != 0 {
-				pji.Status += "Restarted, "
-			}
-			if pji.StatusCode&JOB_STATUS_COMPLETE != 0 {
-				pji.Status += "Sent to Printer, "
-			}
-			if pji.StatusCode&JOB_STATUS_RETAINED != 0 {
-				pji.Status += "Retained, "
-			}
-			if pji.StatusCode&JOB_STATUS_RENDERING_LOCALLY != 0 {
-				pji.Status += "Rendering on Client, "
-			}
-			pji.Status = strings.TrimRight(pji.Status, ", ")
-		}
-		pji.Submitted = time.Date(
-			int(j.Submitted.Year),
-			time.Month(int(j.Submitted.Month)),
-			int(j.Submitted.Day),
-			int(j.Submitted.Hour),
-			int(j.Submitted.Minute),
-			int(j.Submitted.Second),
-			int(1000*j.Submitted.Milliseconds),
-			time.Local,
-		).UTC()
-		pjs = append(pjs, pji)
-	}
-	return pjs, nil
-}
-
-// DriverInfo returns information about printer p driver.
-func (p *Printer) DriverInfo() (*DriverInfo, error) {
-	var needed uint32
-	b := make([]byte, 1024*10)
-	for {
-		err := GetPrinterDriver(p.h, nil, 8, &b[0], uint32(len(b)), &needed)
-		if err == nil {
-			break
-		}
-		if err != syscall.ERROR_INSUFFICIENT_BUFFER {
-			return nil, err
-		}
-		if needed <= uint32(len(b)) {
-			return nil, err
-		}
-		b = make([]byte, needed)
-	}
-	di := (*DRIVER_INFO_8)(unsafe.Pointer(&b[0]))
-	return &DriverInfo{
-		Attributes:  di.PrinterDriverAttributes,
-		Name:        windows.UTF16PtrToString(di.Name),
-		DriverPath:  windows.UTF16PtrToString(di.DriverPath),
-		Environment: windows.UTF16PtrToString(di.Environment),
-	}, nil
+// SystemTime is a date and time as the spooler reports it, with the
+// fields of a Windows SYSTEMTIME.
+type SystemTime struct {
+	Year         uint16
+	Month        uint16
+	DayOfWeek    uint16
+	Day          uint16
+	Hour         uint16
+	Minute       uint16
+	Second       uint16
+	Milliseconds uint16
+}
+
+// Time returns t as a time in loc.
+func (t SystemTime) Time(loc *time.Location) time.Time {
+	return time.Date(int(t.Year), time.Month(t.Month), int(t.Day),
+		int(t.Hour), int(t.Minute), int(t.Second), int(t.Milliseconds)*int(time.Millisecond), loc)
 }
 
 func (p *Printer) StartDocument(name, datatype string) error {
-	d := DOC_INFO_1{
-		DocName:    &(syscall.StringToUTF16(name))[0],
-		OutputFile: nil,
-		Datatype:   &(syscall.StringToUTF16(datatype))[0],
+	p.startAudit(name)
+	err := p.startDocument(name, datatype)
+	if err != nil {
+		p.endAudit(err)
+	}
+	return err
+}
+
+func (p *Printer) startDocument(name, datatype string) error {
+	p.doc = p.doc[:0]
+	p.job = 0
+	p.acked = 0
+	p.data = p.data[:0]
+	p.docName = name
+	// left over by a failed document
+	p.mu.Lock()
+	p.wbuf = p.wbuf[:0]
+	p.mu.Unlock()
+	if p.h == 0 {
+		if dt, ok := p.t.(DocumentTransport); ok {
+			return dt.StartDocument(name, datatype)
+		}
+		return nil
 	}
-	return StartDocPrinter(p.h, 1, &d)
+	return p.retry("StartDocument", func() error {
+		return p.startSpoolDocument(name, datatype)
+	})
+}
+
+// JobID returns the spooler ID of the print job of the current or last
+// document, or 0 if the printer does not print through the spooler.
+func (p *Printer) JobID() uint32 {
+	return p.job
 }
 
-// StartRawDocument calls StartDocument and passes either "RAW" or "XPS_PASS"
-// as a document type, depending if printer driver is XPS-based or not.
+// StartRawDocument calls StartDocument with a datatype that passes the
+// data unmodified to the printer. Unless p.Datatype is set, the datatype
+// is chosen among "XPS_PASS" for v4 and XPS drivers, and "RAW",
+// "RAW [FF auto]" and "RAW [FF appended]", depending on what the print
+// processor of the queue supports.
 func (p *Printer) StartRawDocument(name string) error {
-	di, err := p.DriverInfo()
+	datatype, err := p.rawDatatype()
 	if err != nil {
 		return err
 	}
+	return p.StartDocument(name, datatype)
+}
+
+// rawDatatype returns the datatype used by StartRawDocument.
+func (p *Printer) rawDatatype() (string, error) {
+	if p.Datatype != "" {
+		return p.Datatype, nil
+	}
+	if p.h == 0 {
+		return "RAW", nil
+	}
+	di, err := p.DriverInfo()
+	if err != nil {
+		return "", err
+	}
 	// See https://support.microsoft.com/en-us/help/2779300/v4-print-drivers-using-raw-mode-to-send-pcl-postscript-directly-to-the
 	// for details.
-	datatype := "RAW"
-	if di.Attributes&PRINTER_DRIVER_XPS != 0 {
-		datatype = "XPS_PASS"
+	xps := di.Version >= 4 || di.Attributes&PRINTER_DRIVER_XPS != 0
+	supported, err := p.Datatypes()
+	if err != nil {
+		// cannot tell, fall back to the driver type alone
+		if xps {
+			return "XPS_PASS", nil
+		}
+		return "RAW", nil
 	}
-	return p.StartDocument(name, datatype)
+	has := func(datatype string) bool {
+		for _, s := range supported {
+			if strings.EqualFold(s, datatype) {
+				return true
+			}
+		}
+		return false
+	}
+	if xps && has("XPS_PASS") {
+		return "XPS_PASS", nil
+	}
+	for _, datatype := range []string{"RAW", "RAW [FF auto]", "RAW [FF appended]"} {
+		if has(datatype) {
+			return datatype, nil
+		}
+	}
+	return "RAW", nil
 }
 
-func (p *Printer) Write(b []byte) (int, error) {
-	var written uint32
-	err := WritePrinter(p.h, &b[0], uint32(len(b)), &written)
+// Write writes b to the printer. If it fails, n is the number of bytes
+// of b written before the error.
+func (p *Printer) Write(b []byte) (n int, err error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if p.tx != nil {
+		p.tx = append(p.tx, b...)
+		return len(b), nil
+	}
+	n, err = p.write(b)
 	if err != nil {
-		return 0, err
+		// a document replayed on a new handle gets all of b
+		if err = p.recoverHandle("Write", err); err == nil {
+			n, err = p.write(b)
+		}
+	}
+	// only what reached the printer is recorded and copied
+	if p.recorder() != nil || p.Replay {
+		p.data = append(p.data, b[:n]...)
+	}
+	if p.copies > 1 {
+		p.doc = append(p.doc, b[:n]...)
+	}
+	return n, err
+}
+
+// write writes b to the printer, with flow control if p.Flow is set.
+func (p *Printer) write(b []byte) (int, error) {
+	if p.Flow != nil {
+		return p.flowWrite(b)
+	}
+	return p.send(b)
+}
+
+// send writes b to the printer, coalesced with other small writes if
+// p.WriteBuffer is set.
+func (p *Printer) send(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.WriteBuffer > 0 {
+		if len(p.wbuf)+len(b) > p.WriteBuffer {
+			if err := p.flushLocked(); err != nil {
+				return 0, err
+			}
+		}
+		if len(b) < p.WriteBuffer {
+			p.wbuf = append(p.wbuf, b...)
+			return len(b), nil
+		}
 	}
-	if p.Debug {
-		p.data = append(p.data, b...)
+	return p.transmit(b)
+}
+
+// transmit writes b to the transport. p.mu must be held.
+func (p *Printer) transmit(b []byte) (int, error) {
+	p.lastIO = time.Now()
+	n, err := p.t.Write(b)
+	p.acked += n
+	if p.audit != nil {
+		p.audit.Bytes += int64(n)
 	}
-	return int(written), nil
+	return n, err
 }
 
+// Flush sends the data buffered because of p.WriteBuffer.
+func (p *Printer) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.flushLocked()
+}
+
+// flushLocked is Flush with p.mu held. The data the transport did not
+// accept stays buffered.
+func (p *Printer) flushLocked() error {
+	if len(p.wbuf) == 0 {
+		return nil
+	}
+	n, err := p.transmit(p.wbuf)
+	p.wbuf = p.wbuf[:copy(p.wbuf, p.wbuf[n:])]
+	return err
+}
+
+// EndDocument ends the document started with StartDocument. If the
+// document cannot be saved by p.Recorder or recorded by p.Audit, the error
+// is returned even though the document printed.
 func (p *Printer) EndDocument() error {
-	if p.Debug {
-		err := ioutil.WriteFile("file.pj", p.data, 0644)
-		if err != nil {
-			// handle error
+	err := p.endDocument()
+	if rec := p.recorder(); rec != nil {
+		_, rerr := rec.Save(p.name, p.docName, p.data)
+		p.data = p.data[:0]
+		if err == nil && rerr != nil {
+			err = fmt.Errorf("printer: recorder: %w", rerr)
 		}
 	}
-	return EndDocPrinter(p.h)
+	if aerr := p.endAudit(err); err == nil {
+		err = aerr
+	}
+	return err
+}
+
+var debugRecorder = NewRecorder(".")
+
+// recorder returns the Recorder saving the documents of p, if any.
+func (p *Printer) recorder() *Recorder {
+	if p.Recorder == nil && p.Debug {
+		return debugRecorder
+	}
+	return p.Recorder
+}
+
+func (p *Printer) endDocument() error {
+	// send the copies the driver could not produce, see SetCopies
+	for i := 1; i < p.copies && len(p.doc) > 0; i++ {
+		if _, err := p.write(p.doc); err != nil {
+			p.endSpoolDocument()
+			return err
+		}
+	}
+	// a document replayed from here gets a single copy
+	if err := p.retry("EndDocument", p.Flush); err != nil {
+		p.endSpoolDocument()
+		return err
+	}
+	p.doc = p.doc[:0]
+	if p.h == 0 {
+		if dt, ok := p.t.(DocumentTransport); ok {
+			return dt.EndDocument()
+		}
+		return nil
+	}
+	return p.retry("EndDocument", p.endSpoolDocument)
 }
 
 func (p *Printer) StartPage() error {
-	return StartPagePrinter(p.h)
+	if p.h == 0 {
+		return nil
+	}
+	return p.retry("StartPage", p.startSpoolPage)
 }
 
 func (p *Printer) EndPage() error {
-	return EndPagePrinter(p.h)
+	if err := p.retry("EndPage", p.Flush); err != nil {
+		return err
+	}
+	if p.h == 0 {
+		return nil
+	}
+	return p.retry("EndPage", p.endSpoolPage)
 }
 
 func (p *Printer) Close() error {
-	return ClosePrinter(p.h)
+	p.closeJobNotify()
+	if p.t == nil {
+		return p.closeSpooler()
+	}
+	ferr := p.Flush()
+	if err := p.t.Close(); err != nil {
+		return err
+	}
+	return ferr
 }
 
 type Printer struct {
-	h syscall.Handle
+	h    handle // spooler handle, zero for printers created with NewPrinter
+	t    Transport
+	name string
+
+	// mu serializes the use of t by the user and Keepalive
+	mu     sync.Mutex
+	lastIO time.Time
+	health Health
+	dm     []byte // DEVMODE buffer, see devMode
+
+	job   uint32 // spooler job ID, see JobID
+	acked int    // bytes of the document accepted by t, see Acked
+
+	// software copies, see SetCopies
+	copies int
+	doc    []byte
+
+	// data buffered by Transact, and the ID of its last process ID
+	// response
+	tx   []byte
+	txID int
+
 	// font metrics
+	font          Font
 	width, height uint8
 
 	// state toggles ESC[char]
@@ -408,8 +421,95 @@ type Printer struct {
 
 	// state toggles GS[char]
 	reverse, smooth uint8
-	Debug           bool
-	data            []byte
+
+	align    Align
+	codePage uint8   // see SetCodePage
+	styles   []Style // see PushStyle
+	// Debug saves the data of every document in the current directory,
+	// as a Recorder would.
+	Debug bool
+	// Recorder, if set, saves the data of every document.
+	Recorder *Recorder
+	data     []byte // of the current document, for Recorder
+	docName  string
+
+	// Datatype, if set, is used by StartRawDocument instead of the
+	// datatype it would choose.
+	Datatype string
+
+	// Escape tells how WriteString and Text handle control bytes, such
+	// as ESC and GS, in the text they are given.
+	Escape EscapeMode
+
+	// Replacements are applied to the text given to Text. Nil means
+	// DefaultReplacements.
+	Replacements *Replacements
+
+	// Profile describes the ESC/POS capabilities of the printer. Nil
+	// means DefaultProfile.
+	Profile *Profile
+
+	// MinSpoolSpace, if set, makes StartDocument fail with a
+	// *LowDiskSpaceError when fewer bytes are free in the spool directory.
+	MinSpoolSpace uint64
+
+	// BandWait, if set, is called before every band of a raster image
+	// but the first, see Profile.BandHeight, for instance to check the
+	// status of the printer. An error aborts the image.
+	BandWait func() error
+
+	// Audit, if set, records the documents printed, see AuditRecord.
+	Audit AuditStore
+	// AuditUser is the user recorded by Audit. Empty means the user
+	// running the program.
+	AuditUser string
+	audit     *AuditRecord // of the current document
+
+	// Dedup, if set, keeps PrintOnce from printing a document twice.
+	Dedup *Dedup
+
+	// Flow, if set, enables flow control for the data written to the
+	// printer.
+	Flow *FlowControl
+
+	// WriteBuffer, if set, coalesces writes smaller than WriteBuffer
+	// bytes into single writes to the transport, each a WritePrinter
+	// call for the spooler. The buffered data is sent by Flush, EndPage,
+	// EndDocument and Close, and before the printer is queried, such as
+	// by Status or Read; errors sending it are returned by these calls.
+	WriteBuffer int
+	wbuf        []byte // guarded by mu
+
+	// Replay, if set, keeps the data of the current document in memory
+	// so that the document can be started again and the data replayed if
+	// the spooler handle is lost in the middle of it, see
+	// StaleHandleError. The data is also kept when Recorder is set or
+	// copies are made in software.
+	Replay bool
+
+	// state of the spooler document, to restart it on a new handle
+	access        uint32 // desired access the handle was opened with
+	inDoc, inPage bool
+	datatype      string
+
+	// job change notification reporting the bytes printed, see
+	// jobBytesPrinted
+	notifyMu sync.Mutex
+	notify   handle
+
+	// SpoolerTimeZone is the time zone Jobs interprets the submission
+	// times reported by the spooler in. Nil means UTC, as documented;
+	// time.Local gives the times of earlier versions of the package.
+	SpoolerTimeZone *time.Location
+}
+
+// spoolerTimeZone returns the time zone of the spooler times, see
+// SpoolerTimeZone.
+func (p *Printer) spoolerTimeZone() *time.Location {
+	if p.SpoolerTimeZone != nil {
+		return p.SpoolerTimeZone
+	}
+	return time.UTC
 }
 
 const (
@@ -423,36 +523,9 @@ const (
 	GS byte = 0x1D
 )
 
-// text replacement map
-var textReplaceMap = map[string]string{
-	// horizontal tab
-	"&#9;":  "\x09",
-	"&#x9;": "\x09",
-
-	// linefeed
-	"&#10;": "\n",
-	"&#xA;": "\n",
-
-	// xml stuff
-	"&apos;": "'",
-	"&quot;": `"`,
-	"&gt;":   ">",
-	"&lt;":   "<",
-
-	// ampersand must be last to avoid double decoding
-	"&amp;": "&",
-}
-
-// replace text from the above map
-func textReplace(data string) string {
-	for k, v := range textReplaceMap {
-		data = strings.Replace(data, k, v, -1)
-	}
-	return data
-}
-
 // reset toggles
 func (p *Printer) reset() {
+	p.font = FontA
 	p.width = 1
 	p.height = 1
 
@@ -463,27 +536,39 @@ func (p *Printer) reset() {
 
 	p.reverse = 0
 	p.smooth = 0
+
+	p.align = AlignLeft
 }
 
-// write a string to the printer
+// write a string to the printer, control bytes are handled as set by
+// p.Escape and UTF-8 text is encoded in the code page, see SetCodePage
 func (p *Printer) WriteString(data string) (int, error) {
-	return p.Write([]byte(data))
+	return p.Write([]byte(p.encode(escapeText(data, p.Escape))))
 }
 
-// init/reset printer settings
+// write a command to the printer
+func (p *Printer) command(cmd string) {
+	p.Write([]byte(cmd))
+}
+
+// init/reset printer settings: leave page mode, clear the print buffer,
+// select the profile's code page, character set and line spacing, and
+// forget the styles saved with PushStyle
 func (p *Printer) Init() {
 	p.reset()
-	p.WriteString("\x1B@")
+	p.styles = p.styles[:0]
+	p.codePage = p.profile().CodePage
+	p.Write(p.profile().initBytes())
 }
 
-// end output
+// end output: feed, cut and reset as set by the profile's Finish
 func (p *Printer) End() {
-	p.WriteString("\xFA")
+	p.Write(p.profile().finish().bytes())
 }
 
 // send cut
 func (p *Printer) Cut() {
-	p.WriteString("\x1DVA0")
+	p.command("\x1DVA0")
 }
 
 // send cut minus one point (partial cut)
@@ -493,17 +578,17 @@ func (p *Printer) CutPartial() {
 
 // send cash
 func (p *Printer) Cash() {
-	p.WriteString("\x1B\x70\x00\x0A\xFF")
+	p.command("\x1B\x70\x00\x0A\xFF")
 }
 
 // send linefeed
 func (p *Printer) Linefeed() {
-	p.WriteString("\n")
+	p.command("\n")
 }
 
 // send N formfeeds
 func (p *Printer) FormfeedN(n int) {
-	p.WriteString(fmt.Sprintf("\x1Bd%c", n))
+	p.command(fmt.Sprintf("\x1Bd%c", n))
 }
 
 // send formfeed
@@ -511,78 +596,64 @@ func (p *Printer) Formfeed() {
 	p.FormfeedN(1)
 }
 
-// set font
-func (p *Printer) SetFont(font string) {
-	f := 0
-
-	switch font {
-	case "A":
-		f = 0
-	case "B":
-		f = 1
-	case "C":
-		f = 2
-	default:
-		log.Fatalf("Invalid font: '%s', defaulting to 'A'", font)
-		f = 0
+// set font by name: "A" to "E", "special A" or "special B", see SelectFont
+func (p *Printer) SetFont(font string) error {
+	f, ok := fontNames[strings.ToUpper(font)]
+	if !ok {
+		return fmt.Errorf("printer: invalid font: %q", font)
 	}
-
-	p.WriteString(fmt.Sprintf("\x1BM%c", f))
+	return p.SelectFont(f)
 }
 
 func (p *Printer) SendFontSize() {
-	p.WriteString(fmt.Sprintf("\x1D!%c", ((p.width-1)<<4)|(p.height-1)))
+	p.command(fmt.Sprintf("\x1D!%c", ((p.width-1)<<4)|(p.height-1)))
 }
 
 // set font size
-func (p *Printer) SetFontSize(width, height uint8) {
-	if width > 0 && height > 0 && width <= 8 && height <= 8 {
-		p.width = width
-		p.height = height
-		p.SendFontSize()
-	} else {
-		log.Fatalf("Invalid font size passed: %d x %d", width, height)
-	}
+//
+// Deprecated: use SetCharSize.
+func (p *Printer) SetFontSize(width, height uint8) error {
+	return p.SetCharSize(width, height)
 }
 
 // send underline
 func (p *Printer) SendUnderline() {
-	p.WriteString(fmt.Sprintf("\x1B-%c", p.underline))
+	p.command(fmt.Sprintf("\x1B-%c", p.underline))
 }
 
 // send emphasize / doublestrike
 func (p *Printer) SendEmphasize() {
-	p.WriteString(fmt.Sprintf("\x1BG%c", p.emphasize))
+	p.command(fmt.Sprintf("\x1BG%c", p.emphasize))
 }
 
 // send upsidedown
 func (p *Printer) SendUpsidedown() {
-	p.WriteString(fmt.Sprintf("\x1B{%c", p.upsidedown))
+	p.command(fmt.Sprintf("\x1B{%c", p.upsidedown))
 }
 
-// send rotate
+// send rotate -- ESC V
 func (p *Printer) SendRotate() {
-	p.WriteString(fmt.Sprintf("\x1BR%c", p.rotate))
+	p.command(fmt.Sprintf("\x1BV%c", p.rotate))
 }
 
 // send reverse
 func (p *Printer) SendReverse() {
-	p.WriteString(fmt.Sprintf("\x1DB%c", p.reverse))
+	p.command(fmt.Sprintf("\x1DB%c", p.reverse))
 }
 
 // send smooth
 func (p *Printer) SendSmooth() {
-	p.WriteString(fmt.Sprintf("\x1Db%c", p.smooth))
+	p.command(fmt.Sprintf("\x1Db%c", p.smooth))
 }
 
 // send move x
 func (p *Printer) SendMoveX(x uint16) {
-	p.WriteString(string([]byte{0x1b, 0x24, byte(x % 256), byte(x / 256)}))
+	p.command(string([]byte{0x1b, 0x24, byte(x % 256), byte(x / 256)}))
 }
 
 // send move y
 func (p *Printer) SendMoveY(y uint16) {
-	p.WriteString(string([]byte{0x1d, 0x24, byte(y % 256), byte(y / 256)}))
+	p.command(string([]byte{0x1d, 0x24, byte(y % 256), byte(y / 256)}))
 }
 
 // set underline
@@ -621,177 +692,241 @@ func (p *Printer) SetSmooth(v uint8) {
 	p.SendSmooth()
 }
 
-// pulse (open the drawer)
+// pulse (open the drawer) -- see OpenDrawer
 func (p *Printer) Pulse() {
-	// with t=2 -- meaning 2*2msec
-	p.WriteString("\x1Bp\x02")
+	p.OpenDrawer(DefaultDrawerKick)
 }
 
 // set alignment
 func (p *Printer) SetAlign(align string) {
-	a := 0
+	a := AlignLeft
 	switch align {
 	case "left":
-		a = 0
+		a = AlignLeft
 	case "center":
-		a = 1
+		a = AlignCenter
 	case "right":
-		a = 2
+		a = AlignRight
 	default:
 		log.Fatalf("Invalid alignment: %s", align)
 	}
-	p.WriteString(fmt.Sprintf("\x1Ba%c", a))
-}
-
-// set language -- ESC R
-func (p *Printer) SetLang(lang string) {
-	l := 0
-
-	switch lang {
-	case "en":
-		l = 0
-	case "fr":
-		l = 1
-	case "de":
-		l = 2
-	case "uk":
-		l = 3
-	case "da":
-		l = 4
-	case "sv":
-		l = 5
-	case "it":
-		l = 6
-	case "es":
-		l = 7
-	case "ja":
-		l = 8
-	case "no":
-		l = 9
+	p.align = a
+	p.SendAlign()
+}
+
+// select alignment -- ESC a n
+func (p *Printer) SelectAlign(a Align) error {
+	switch a {
+	case AlignLeft, AlignCenter, AlignRight:
 	default:
-		log.Fatalf("Invalid language: %s", lang)
+		return fmt.Errorf("printer: invalid alignment %d", a)
 	}
-	p.WriteString(fmt.Sprintf("\x1BR%c", l))
+	p.align = a
+	p.SendAlign()
+	return nil
 }
 
-// do a block of text
-func (p *Printer) Text(params map[string]string, data string) {
-
-	// send alignment to printer
-	if align, ok := params["align"]; ok {
-		p.SetAlign(align)
-	}
+// send justification -- ESC a n
+func (p *Printer) SendAlign() {
+	p.command(fmt.Sprintf("\x1Ba%c", p.align))
+}
 
-	// set lang
-	if lang, ok := params["lang"]; ok {
-		p.SetLang(lang)
+// set language -- ESC R, see SetCharSet
+func (p *Printer) SetLang(lang string) error {
+	cs, ok := langCharSets[lang]
+	if !ok {
+		return fmt.Errorf("printer: invalid language: %s", lang)
 	}
+	return p.SetCharSet(cs)
+}
 
-	// set smooth
-	if smooth, ok := params["smooth"]; ok && (smooth == "true" || smooth == "1") {
-		p.SetSmooth(1)
-	}
+// TextOptions are the formatting options of Text.
+type TextOptions struct {
+	Align Align
+	Font  Font
+	// Width and Height are the character size multipliers. Zero means 1.
+	Width, Height uint8
+	Bold          bool
+	// Underline is the underline thickness in dots: 0, 1 or 2.
+	Underline uint8
+	Reverse   bool
+	Rotate    bool
+	Smooth    bool
+	// X, if set, is the absolute horizontal position of the text in
+	// dots. Y, if set, is its vertical position in page mode.
+	X, Y uint16
+	// Lang, if set, selects the character set of a language, see
+	// SetLang.
+	Lang string
+}
 
-	// set emphasize
-	if em, ok := params["em"]; ok && (em == "true" || em == "1") {
-		p.SetEmphasize(1)
+// do a block of text: apply o, then write data after applying the
+// replacements
+func (p *Printer) Text(o TextOptions, data string) error {
+	err := p.SetStyle(Style{
+		Font:      o.Font,
+		Width:     o.Width,
+		Height:    o.Height,
+		Underline: o.Underline,
+		Emphasize: o.Bold,
+		Reverse:   o.Reverse,
+		Rotate:    o.Rotate,
+		Align:     o.Align,
+	})
+	if err != nil {
+		return err
 	}
-
-	// set underline
-	if ul, ok := params["ul"]; ok && (ul == "true" || ul == "1") {
-		p.SetUnderline(1)
+	p.SetSmooth(boolByte(o.Smooth))
+	if o.Lang != "" {
+		if err := p.SetLang(o.Lang); err != nil {
+			return err
+		}
 	}
-
-	// set reverse
-	if reverse, ok := params["reverse"]; ok && (reverse == "true" || reverse == "1") {
-		p.SetReverse(1)
+	if o.X > 0 {
+		p.SendMoveX(o.X)
 	}
-
-	// set rotate
-	if rotate, ok := params["rotate"]; ok && (rotate == "true" || rotate == "1") {
-		p.SetRotate(1)
+	if o.Y > 0 {
+		p.SendMoveY(o.Y)
 	}
 
-	// set font
-	if font, ok := params["font"]; ok {
-		p.SetFont(strings.ToUpper(font[5:6]))
+	// do text replace, then write data
+	data = p.replacements().Replace(data)
+	if len(data) > 0 {
+		_, err = p.WriteString(data)
 	}
+	return err
+}
 
-	// do dw (double font width)
-	if dw, ok := params["dw"]; ok && (dw == "true" || dw == "1") {
-		p.SetFontSize(2, p.height)
+// TextParams calls Text with the options given by the parameters of a
+// text node, applied over the current formatting: align (left, center or
+// right), lang, smooth, em, ul, reverse, rotate, font (such as font_b),
+// dw and dh (double width and height), width, height, x and y. Boolean
+// parameters are set by "true" or "1".
+func (p *Printer) TextParams(params map[string]string, data string) error {
+	o, err := p.textOptions(params)
+	if err != nil {
+		return err
 	}
+	return p.Text(o, data)
+}
 
-	// do dh (double font height)
-	if dh, ok := params["dh"]; ok && (dh == "true" || dh == "1") {
-		p.SetFontSize(p.width, 2)
+// textOptions returns the TextOptions of the parameters of a text node.
+func (p *Printer) textOptions(params map[string]string) (TextOptions, error) {
+	s := p.Style()
+	o := TextOptions{
+		Align:     s.Align,
+		Font:      s.Font,
+		Width:     s.Width,
+		Height:    s.Height,
+		Bold:      s.Emphasize,
+		Underline: s.Underline,
+		Reverse:   s.Reverse,
+		Rotate:    s.Rotate,
+		Smooth:    p.smooth != 0,
 	}
-
-	// do font width
-	if width, ok := params["width"]; ok {
-		if i, err := strconv.Atoi(width); err == nil {
-			p.SetFontSize(uint8(i), p.height)
-		} else {
-			log.Fatalf("Invalid font width: %s", width)
+	if align, ok := params["align"]; ok {
+		switch align {
+		case "left":
+			o.Align = AlignLeft
+		case "center":
+			o.Align = AlignCenter
+		case "right":
+			o.Align = AlignRight
+		default:
+			return o, fmt.Errorf("printer: invalid alignment: %s", align)
 		}
 	}
-
-	// do font height
-	if height, ok := params["height"]; ok {
-		if i, err := strconv.Atoi(height); err == nil {
-			p.SetFontSize(p.width, uint8(i))
-		} else {
-			log.Fatalf("Invalid font height: %s", height)
+	o.Lang = params["lang"]
+	set := func(name string) bool {
+		v, ok := params[name]
+		return ok && (v == "true" || v == "1")
+	}
+	if set("smooth") {
+		o.Smooth = true
+	}
+	if set("em") {
+		o.Bold = true
+	}
+	if set("ul") {
+		o.Underline = 1
+	}
+	if set("reverse") {
+		o.Reverse = true
+	}
+	if set("rotate") {
+		o.Rotate = true
+	}
+	if font, ok := params["font"]; ok {
+		// font_a, font_b...
+		name := font
+		if len(font) == 6 {
+			name = font[5:6]
+		}
+		f, ok := fontNames[strings.ToUpper(name)]
+		if !ok {
+			return o, fmt.Errorf("printer: invalid font: %q", font)
 		}
+		o.Font = f
 	}
-
-	// do y positioning
-	if x, ok := params["x"]; ok {
-		if i, err := strconv.Atoi(x); err == nil {
-			p.SendMoveX(uint16(i))
-		} else {
-			log.Fatalf("Invalid x param %s", x)
+	if set("dw") {
+		o.Width = 2
+	}
+	if set("dh") {
+		o.Height = 2
+	}
+	for _, n := range []struct {
+		name string
+		v    *uint8
+	}{{"width", &o.Width}, {"height", &o.Height}} {
+		if v, ok := params[n.name]; ok {
+			i, err := strconv.ParseUint(v, 10, 8)
+			if err != nil {
+				return o, fmt.Errorf("printer: invalid font %s: %s", n.name, v)
+			}
+			*n.v = uint8(i)
 		}
 	}
-
-	// do y positioning
-	if y, ok := params["y"]; ok {
-		if i, err := strconv.Atoi(y); err == nil {
-			p.SendMoveY(uint16(i))
-		} else {
-			log.Fatalf("Invalid y param %s", y)
+	for _, n := range []struct {
+		name string
+		v    *uint16
+	}{{"x", &o.X}, {"y", &o.Y}} {
+		if v, ok := params[n.name]; ok {
+			i, err := strconv.ParseUint(v, 10, 16)
+			if err != nil {
+				return o, fmt.Errorf("printer: invalid %s param %s", n.name, v)
+			}
+			*n.v = uint16(i)
 		}
 	}
+	return o, nil
+}
 
-	// do text replace, then write data
-	data = textReplace(data)
-	if len(data) > 0 {
-		p.WriteString(data)
-	}
+// FeedOptions are the options of Feed.
+type FeedOptions struct {
+	// Lines is the number of lines fed, 0 to 255.
+	Lines int
+	// Dots is fed after the lines, in dots.
+	Dots uint16
+	// ResetStyles restores the default text formatting after the feed.
+	ResetStyles bool
 }
 
-// feed the printer
-func (p *Printer) Feed(params map[string]string) {
-	// handle lines (form feed X lines)
-	if l, ok := params["line"]; ok {
-		if i, err := strconv.Atoi(l); err == nil {
-			p.FormfeedN(i)
-		} else {
-			log.Fatalf("Invalid line number %s", l)
-		}
+// feed the printer: print the pending line, then feed as set by o
+func (p *Printer) Feed(o FeedOptions) error {
+	if o.Lines < 0 || o.Lines > 255 {
+		return fmt.Errorf("printer: invalid number of lines to feed: %d", o.Lines)
 	}
-
-	// handle units (dots)
-	if u, ok := params["unit"]; ok {
-		if i, err := strconv.Atoi(u); err == nil {
-			p.SendMoveY(uint16(i))
-		} else {
-			log.Fatalf("Invalid unit number %s", u)
-		}
+	b := []byte{esc, 'd', byte(o.Lines)}
+	// ESC J feeds at most 255 dots
+	for d := o.Dots; d > 0; d -= uint16(min(int(d), 255)) {
+		b = append(b, esc, 'J', byte(min(int(d), 255)))
+	}
+	if _, err := p.Write(b); err != nil {
+		return err
+	}
+	if !o.ResetStyles {
+		return nil
 	}
-
-	// send linefeed
-	p.Linefeed()
 
 	// reset variables
 	p.reset()
@@ -804,7 +939,31 @@ func (p *Printer) Feed(params map[string]string) {
 	p.SendUnderline()
 	p.SendUpsidedown()
 	p.SendFontSize()
-	p.SendUnderline()
+	p.SendFont()
+	p.SendAlign()
+	return nil
+}
+
+// feedOptions returns the FeedOptions of the parameters of a feed node:
+// line, the number of lines, and unit, the number of dots. Nodes feed one
+// more line and reset the styles.
+func feedOptions(params map[string]string) (FeedOptions, error) {
+	o := FeedOptions{Lines: 1, ResetStyles: true}
+	if l, ok := params["line"]; ok {
+		i, err := strconv.Atoi(l)
+		if err != nil {
+			return o, fmt.Errorf("printer: invalid line number %s", l)
+		}
+		o.Lines += i
+	}
+	if u, ok := params["unit"]; ok {
+		i, err := strconv.ParseUint(u, 10, 16)
+		if err != nil {
+			return o, fmt.Errorf("printer: invalid unit number %s", u)
+		}
+		o.Dots = uint16(i)
+	}
+	return o, nil
 }
 
 // feed and cut based on parameters
@@ -816,44 +975,37 @@ func (p *Printer) FeedAndCut(params map[string]string) {
 	p.Cut()
 }
 
-// Barcode sends a barcode to the printer.
-func (p *Printer) Barcode(barcode string, format int) {
-	code := ""
-	switch format {
-	case 0:
-		code = "\x00"
-	case 1:
-		code = "\x01"
-	case 2:
-		code = "\x02"
-	case 3:
-		code = "\x03"
-	case 4:
-		code = "\x04"
-	case 73:
-		code = "\x49"
-	}
-
-	// reset settings
-	p.reset()
-
-	// set align
-	p.SetAlign("center")
-
-	// write barcode
-	if format > 69 {
-		p.WriteString(fmt.Sprintf("\x1dk"+code+"%v%v", len(barcode), barcode))
-	} else if format < 69 {
-		p.WriteString(fmt.Sprintf("\x1dk"+code+"%v\x00", barcode))
+// QRCode sends a QR code (model 2) holding data to the printer. size is
+// the module size in dots, 1 to 16, and ec is one of the
+// QRCodeErrorCorrectionLevel constants.
+func (p *Printer) QRCode(data string, size, ec uint8) error {
+	if len(data) == 0 || len(data) > 7089 {
+		return fmt.Errorf("printer: invalid QR code data length: %d", len(data))
+	}
+	if size < 1 || size > 16 {
+		return fmt.Errorf("printer: invalid QR code module size: %d", size)
 	}
-	p.WriteString(fmt.Sprintf("%v", barcode))
+	if ec < QRCodeErrorCorrectionLevelL || ec > QRCodeErrorCorrectionLevelH {
+		return fmt.Errorf("printer: invalid QR code error correction level: %d", ec)
+	}
+	n := len(data) + 3
+	b := []byte{
+		gs, '(', 'k', 4, 0, 49, 65, 50, 0, // select model 2
+		gs, '(', 'k', 3, 0, 49, 67, size, // module size
+		gs, '(', 'k', 3, 0, 49, 69, ec, // error correction level
+		gs, '(', 'k', byte(n), byte(n >> 8), 49, 80, 48, // store data
+	}
+	b = append(b, data...)
+	b = append(b, gs, '(', 'k', 3, 0, 49, 81, 48) // print
+	_, err := p.Write(b)
+	return err
 }
 
 // used to send graphics headers
 func (p *Printer) gSend(m byte, fn byte, data []byte) {
 	l := len(data) + 2
 
-	p.WriteString("\x1b(L")
+	p.command("\x1b(L")
 	p.Write([]byte{byte(l % 256), byte(l / 256), m, fn})
 	p.Write(data)
 }
@@ -918,8 +1070,9 @@ func (p *Printer) Image(params map[string]string, data string) {
 
 }
 
-// write a "node" to the printer
-func (p *Printer) WriteNode(name string, params map[string]string, data string) {
+// write a "node" to the printer, using the handler registered for name,
+// see RegisterNode
+func (p *Printer) WriteNode(name string, params map[string]string, data string) error {
 	cstr := ""
 	if data != "" {
 		str := data[:]
@@ -930,16 +1083,9 @@ func (p *Printer) WriteNode(name string, params map[string]string, data string)
 	}
 	log.Printf("WriteString: %s => %+v%s\n", name, params, cstr)
 
-	switch name {
-	case "text":
-		p.Text(params, data)
-	case "feed":
-		p.Feed(params)
-	case "cut":
-		p.FeedAndCut(params)
-	case "pulse":
-		p.Pulse()
-	case "image":
-		p.Image(params, data)
+	h, ok := nodeHandler(name)
+	if !ok {
+		return fmt.Errorf("printer: unknown node %q", name)
 	}
+	return h(p, params, data)
 }
@@ -0,0 +1,347 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package printer
+
+import (
+	"context"
+	"image"
+	"time"
+)
+
+// The Windows spooler and GDI are not available on this system: the
+// functions using them return a *PlatformError and only printers created
+// with NewPrinter or OpenURI print.
+
+// handle is the spooler handle of a printer queue, always zero here.
+type handle = uintptr
+
+func (p *Printer) startSpoolDocument(name, datatype string) error {
+	return &PlatformError{Op: "StartDocument"}
+}
+
+func (p *Printer) endSpoolDocument() error {
+	return &PlatformError{Op: "EndDocument"}
+}
+
+func (p *Printer) startSpoolPage() error {
+	return &PlatformError{Op: "StartPage"}
+}
+
+func (p *Printer) endSpoolPage() error {
+	return &PlatformError{Op: "EndPage"}
+}
+
+func (p *Printer) closeSpooler() error {
+	return nil
+}
+
+func (p *Printer) closeJobNotify() {}
+
+func isStaleHandle(err error) bool {
+	return false
+}
+
+func (p *Printer) reopen() error {
+	return &PlatformError{Op: "reopen"}
+}
+
+func Default() (string, error) {
+	return "", &PlatformError{Op: "Default"}
+}
+
+func Open(name string) (*Printer, error) {
+	return nil, &PlatformError{Op: "Open"}
+}
+
+func OpenAdmin(name string) (*Printer, error) {
+	return nil, &PlatformError{Op: "OpenAdmin"}
+}
+
+func OpenByPort(port string) (*Printer, error) {
+	return nil, &PlatformError{Op: "OpenByPort"}
+}
+
+func Enumerate(opts EnumOptions) ([]Info, error) {
+	return nil, &PlatformError{Op: "Enumerate"}
+}
+
+func WatchDefault(ctx context.Context) (<-chan DefaultChange, error) {
+	return nil, &PlatformError{Op: "WatchDefault"}
+}
+
+func watchPrinters(ctx context.Context, server string) (<-chan struct{}, error) {
+	return nil, &PlatformError{Op: "NewPrinterCache"}
+}
+
+func SpoolDirectory() (string, error) {
+	return "", &PlatformError{Op: "SpoolDirectory"}
+}
+
+func SpoolFreeSpace() (dir string, free uint64, err error) {
+	return "", 0, &PlatformError{Op: "SpoolFreeSpace"}
+}
+
+func StageDriverPackage(infPath string) (string, error) {
+	return "", &PlatformError{Op: "StageDriverPackage"}
+}
+
+func InstallDriver(infPath, driverName string) error {
+	return &PlatformError{Op: "InstallDriver"}
+}
+
+func CreateQueue(name, driver, port string) error {
+	return &PlatformError{Op: "CreateQueue"}
+}
+
+func DriverDirectory(env string) (string, error) {
+	return "", &PlatformError{Op: "DriverDirectory"}
+}
+
+func FileVersion(path string) (string, error) {
+	return "", &PlatformError{Op: "FileVersion"}
+}
+
+func (di *DriverInfo) Files() []DriverFile {
+	return nil
+}
+
+func EnumMonitors() ([]Monitor, error) {
+	return nil, &PlatformError{Op: "EnumMonitors"}
+}
+
+func PrintersOnPort(port string) ([]string, error) {
+	return nil, &PlatformError{Op: "PrintersOnPort"}
+}
+
+func ReadPorts() ([]Port, error) {
+	return nil, &PlatformError{Op: "ReadPorts"}
+}
+
+func PrinterPorts() (map[string][]Port, error) {
+	return nil, &PlatformError{Op: "PrinterPorts"}
+}
+
+func (p *Printer) Jobs() ([]JobInfo, error) {
+	return nil, &PlatformError{Op: "Jobs"}
+}
+
+func (p *Printer) CancelJob(id uint32) error {
+	return &PlatformError{Op: "CancelJob"}
+}
+
+func (p *Printer) PauseJob(id uint32) error {
+	return &PlatformError{Op: "PauseJob"}
+}
+
+func (p *Printer) ResumeJob(id uint32) error {
+	return &PlatformError{Op: "ResumeJob"}
+}
+
+func (p *Printer) RestartJob(id uint32) error {
+	return &PlatformError{Op: "RestartJob"}
+}
+
+func (p *Printer) DriverInfo() (*DriverInfo, error) {
+	return nil, &PlatformError{Op: "DriverInfo"}
+}
+
+func (p *Printer) Datatypes() ([]string, error) {
+	return nil, &PlatformError{Op: "Datatypes"}
+}
+
+func (p *Printer) DeviceID() (*DeviceID, error) {
+	return nil, &PlatformError{Op: "DeviceID"}
+}
+
+func (p *Printer) BidiStatus() (*BidiStatus, error) {
+	return nil, &PlatformError{Op: "BidiStatus"}
+}
+
+func (p *Printer) Info() (*Info, error) {
+	return nil, &PlatformError{Op: "Info"}
+}
+
+func (p *Printer) Attributes() (Attributes, error) {
+	return 0, &PlatformError{Op: "Attributes"}
+}
+
+func (p *Printer) Rename(newName string) error {
+	return &PlatformError{Op: "Rename"}
+}
+
+func (p *Printer) SetComment(comment string) error {
+	return &PlatformError{Op: "SetComment"}
+}
+
+func (p *Printer) SetLocation(location string) error {
+	return &PlatformError{Op: "SetLocation"}
+}
+
+func (p *Printer) SetShareName(shareName string) error {
+	return &PlatformError{Op: "SetShareName"}
+}
+
+func (p *Printer) SetShared(shared bool) error {
+	return &PlatformError{Op: "SetShared"}
+}
+
+func (p *Printer) Share(shareName string) error {
+	return &PlatformError{Op: "Share"}
+}
+
+func (p *Printer) Unshare() error {
+	return &PlatformError{Op: "Unshare"}
+}
+
+func (p *Printer) SetPriority(priority uint32) error {
+	return &PlatformError{Op: "SetPriority"}
+}
+
+func (p *Printer) SetDefaultPriority(priority uint32) error {
+	return &PlatformError{Op: "SetDefaultPriority"}
+}
+
+func (p *Printer) SetAvailability(start, until time.Duration) error {
+	return &PlatformError{Op: "SetAvailability"}
+}
+
+func (p *Printer) SeparatorPage() (string, error) {
+	return "", &PlatformError{Op: "SeparatorPage"}
+}
+
+func (p *Printer) SetSeparatorPage(path string) error {
+	return &PlatformError{Op: "SetSeparatorPage"}
+}
+
+func (p *Printer) SetSpoolMode(mode SpoolMode) error {
+	return &PlatformError{Op: "SetSpoolMode"}
+}
+
+func (p *Printer) SetKeepPrintedJobs(keep bool) error {
+	return &PlatformError{Op: "SetKeepPrintedJobs"}
+}
+
+func (p *Printer) SetEnableDevQ(enable bool) error {
+	return &PlatformError{Op: "SetEnableDevQ"}
+}
+
+func (p *Printer) SetDoCompleteFirst(enable bool) error {
+	return &PlatformError{Op: "SetDoCompleteFirst"}
+}
+
+func (p *Printer) SetCopies(copies int, collate bool) error {
+	return &PlatformError{Op: "SetCopies"}
+}
+
+func (p *Printer) SetDuplex(d Duplex) error {
+	return &PlatformError{Op: "SetDuplex"}
+}
+
+func (p *Printer) SetOrientation(o Orientation) error {
+	return &PlatformError{Op: "SetOrientation"}
+}
+
+func (p *Printer) DefaultDevMode(scope DefaultsScope) ([]byte, error) {
+	return nil, &PlatformError{Op: "DefaultDevMode"}
+}
+
+func (p *Printer) SetDefaultDevMode(scope DefaultsScope, dm []byte) error {
+	return &PlatformError{Op: "SetDefaultDevMode"}
+}
+
+func (p *Printer) SetQueueDefaults(scope DefaultsScope, d QueueDefaults) error {
+	return &PlatformError{Op: "SetQueueDefaults"}
+}
+
+// DC is a GDI device context. NewDC always fails on this system, so the
+// methods of DC are never called.
+type DC struct{}
+
+func (p *Printer) NewDC(opts PageOptions) (*DC, error) {
+	return nil, &PlatformError{Op: "NewDC"}
+}
+
+func (dc *DC) StartDoc(name, output string) error {
+	return &PlatformError{Op: "DC.StartDoc"}
+}
+
+func (dc *DC) JobID() uint32 {
+	return 0
+}
+
+func (dc *DC) EndDoc() error {
+	return &PlatformError{Op: "DC.EndDoc"}
+}
+
+func (dc *DC) AbortDoc() error {
+	return &PlatformError{Op: "DC.AbortDoc"}
+}
+
+func (dc *DC) StartPage() error {
+	return &PlatformError{Op: "DC.StartPage"}
+}
+
+func (dc *DC) EndPage() error {
+	return &PlatformError{Op: "DC.EndPage"}
+}
+
+func (dc *DC) Close() error {
+	return nil
+}
+
+func (dc *DC) Size() (width, height int) {
+	return 0, 0
+}
+
+func (dc *DC) PrintableArea() image.Rectangle {
+	return image.Rectangle{}
+}
+
+func (dc *DC) Body() image.Rectangle {
+	return image.Rectangle{}
+}
+
+func (dc *DC) Header() image.Rectangle {
+	return image.Rectangle{}
+}
+
+func (dc *DC) Footer() image.Rectangle {
+	return image.Rectangle{}
+}
+
+func (dc *DC) TextOut(x, y int, s string) error {
+	return &PlatformError{Op: "DC.TextOut"}
+}
+
+func (dc *DC) DrawImage(r image.Rectangle, img image.Image) error {
+	return &PlatformError{Op: "DC.DrawImage"}
+}
+
+func (dc *DC) SetFont(f FontSpec) error {
+	return &PlatformError{Op: "DC.SetFont"}
+}
+
+func (dc *DC) MeasureText(s string) (width, height int, err error) {
+	return 0, 0, &PlatformError{Op: "DC.MeasureText"}
+}
+
+func (dc *DC) Line(x1, y1, x2, y2 int) error {
+	return &PlatformError{Op: "DC.Line"}
+}
+
+func (dc *DC) WrapText(s string, width int) ([]string, error) {
+	return nil, &PlatformError{Op: "DC.WrapText"}
+}
+
+func (dc *DC) TextBox(r image.Rectangle, s string, align Align) (int, error) {
+	return 0, &PlatformError{Op: "DC.TextBox"}
+}
+
+func (dc *DC) TableRow(x, y int, cols []Column, cells []string) (int, error) {
+	return 0, &PlatformError{Op: "DC.TableRow"}
+}
@@ -4,390 +4,106 @@
 
 package printer
 
-import (
-	"bytes"
-	"encoding/json"
+import "testing"
 
-	"golang.org/x/text/encoding/charmap"
-	"log"
-	"os"
-	"testing"
-)
-
-func TestPrinttofile(t *testing.T) {
-	filerc, err := os.Open("file.pj")
-	if err != nil {
-		log.Fatal(err)
+func TestFeed(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	p.SetEmphasize(1)
+	ft.Reset()
+	if err := p.Feed(FeedOptions{Lines: 2, Dots: 300}); err != nil {
+		t.Fatal(err)
 	}
-	defer filerc.Close()
-	PrintToFile(filerc)
-}
-func PrintToFile(filerc *os.File) error {
-	name, err := Default()
-	if err != nil {
-		return err
+	if got, want := ft.String(), "\x1Bd\x02\x1BJ\xff\x1BJ\x2d"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
 	}
-
-	p, err := Open(name)
-	p.Debug = true
-	if err != nil {
-		return err
+	ft.Reset()
+	if err := p.Feed(FeedOptions{ResetStyles: true}); err != nil {
+		t.Fatal(err)
 	}
-	defer p.Close()
-
-	err = p.StartDocument(filerc.Name(), "RAW")
-	if err != nil {
-		return err
+	if got, want := ft.String(), "\x1Bd\x00\x1BG\x00\x1BV\x00\x1Db\x00\x1DB\x00\x1B-\x00\x1B{\x00\x1D!\x00\x1BM\x00\x1Ba\x00"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
 	}
-	defer p.EndDocument()
-	err = p.StartPage()
-	if err != nil {
-		return err
+	if p.Style().Emphasize {
+		t.Error("emphasize not reset")
 	}
-
-	p.Init()
-
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(filerc)
-	contents := buf.Bytes()
-
-	p.Write(contents)
-
-	err = p.EndPage()
-	if err != nil {
-		return err
+	if err := p.Feed(FeedOptions{Lines: 256}); err == nil {
+		t.Error("no error for 256 lines")
 	}
-	return nil
 }
 
-func TestPrinter(t *testing.T) {
-	name, err := Default()
-	if err != nil {
-		t.Fatalf("Default failed: %v", err)
-	}
-
-	p, err := Open(name)
-	p.Debug = true
-	if err != nil {
-		t.Fatalf("Open failed: %v", err)
-	}
-	defer p.Close()
-
-	err = p.StartDocument("my document", "RAW")
-	if err != nil {
-		t.Fatalf("StartDocument failed: %v", err)
+func TestFeedResetStyles(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	if err := p.SelectFont(FontB); err != nil {
+		t.Fatal(err)
 	}
-	defer p.EndDocument()
-	err = p.StartPage()
-	if err != nil {
-		t.Fatalf("StartPage failed: %v", err)
-	}
-
-	text := "£"
-	encoder := charmap.CodePage437.NewEncoder()
-	encoded, _ := encoder.String(text)
-
-	p.Init()
-	p.SetFontSize(2, 2)
-	p.SetFont("B")
-	p.SetAlign("center")
-	p.WriteString("** CARD PAYMENT **\n")
-	p.WriteString("------------------------\n")
-	p.WriteString("GETMENULINK Ref: 1544\n")
-	p.WriteString("ACEPTED (Auto)\n")
-	p.WriteString("------------------------\n")
-	p.FormfeedN(2)
-	p.SetEmphasize(1)
-	p.SetReverse(1)
-	p.WriteString("YUM YUM THAI\n")
-	p.SetReverse(0)
-	p.WriteString("Pickup\n")
-	p.SetEmphasize(0)
-	p.Formfeed()
-
-	p.SetFont("A")
-	p.SetAlign("left")
-	p.SetFontSize(1, 1)
-	p.WriteString("Date            : 25.05.2021 17:51\n")
-	p.WriteString("Server          : Pit\n")
-	p.WriteString("Order           : 21/34953\n")
-	p.WriteString("Dispatch Time   : 18:20\n")
-	p.Formfeed()
-
-	p.SetAlign("center")
-	p.SetFont("B")
-	p.SetFontSize(2, 2)
-	p.SetEmphasize(1)
-	p.WriteString("------------------------------\n")
-	p.SetUnderline(1)
-	p.WriteString("Pickup Details\n")
-	p.Formfeed()
-	p.SetUnderline(0)
-	p.WriteString("Ibrahim COBANI\n")
-	p.WriteString("(532 540 1194)\n")
-	p.Formfeed()
-	p.WriteString("------------------------------\n")
-	p.WriteString("ORDER DETAILS\n")
-	p.WriteString("------------------------------\n")
-	p.SetEmphasize(0)
-	p.SetFont("A")
-	p.SetFontSize(1, 2)
-	p.SetAlign("center")
-	p.WriteString("***STARTED***\n")
-	p.SetAlign("left")
-	p.WriteString("1x3. SA-TAY KING PRAWN\n")
-	p.Formfeed()
-	p.SetAlign("center")
-	p.WriteString("***MAIN***\n")
-	p.SetAlign("left")
-	p.WriteString("1x61. Jungle Curry with  Chicken\n")
-	p.WriteString("1x130. Sauted Aubergine with chilli, Onion & Peppers (V) \n")
-	p.WriteString("1x141. Steamed Rice\n")
-	p.Formfeed()
-
-	p.SetFont("B")
-	p.SetFontSize(2, 2)
-	p.SetEmphasize(1)
-	p.SetAlign("right")
-	p.WriteString("------------------------------\n")
-	p.WriteString("Total (4 Items)\n")
-	p.WriteString("Total : " + encoded + "29\n")
-	p.SetAlign("left")
-
-	p.Formfeed()
-	p.Cut()
-
-	p.SetFontSize(2, 2)
-	p.SetFont("B")
-	p.SetAlign("center")
-	p.WriteString("** CARD PAYMENT **\n")
-	p.WriteString("------------------------\n")
-	p.WriteString("GETMENULINK Ref: 1544\n")
-	p.WriteString("ACEPTED (Auto)\n")
-	p.WriteString("------------------------\n")
-	p.FormfeedN(2)
-	p.SetEmphasize(1)
-	p.SetReverse(1)
-	p.WriteString("YUM YUM THAI\n")
-	p.SetReverse(0)
-	p.SetEmphasize(0)
-	p.SetFont("A")
-	p.SetFontSize(1, 1)
-	p.WriteString("187 STOKE NEWINGTON HIGH STREET\n")
-	p.WriteString("LONDON\n")
-	p.WriteString("N16 OLH\n")
-	p.WriteString("0207 254 6751\n")
-	p.WriteString("www.yumyumthain16.co.uk\n")
-	p.WriteString("317318415\n")
-	p.WriteString("\n")
-
-	p.Formfeed()
-
-	p.SetAlign("left")
-	p.WriteString("Date            : 25.05.2021 17:51\n")
-	p.WriteString("Server          : Pit\n")
-	p.WriteString("Order           : 21/34953\n")
-
-	p.SetAlign("center")
-	p.SetFont("B")
-	p.SetFontSize(2, 2)
-	p.SetEmphasize(1)
-	p.WriteString("------------------------------\n")
-	p.WriteString("Dispatch Time   : 18:20\n")
-
-	p.WriteString("------------------------------\n")
-	p.SetUnderline(1)
-	p.WriteString("Pickup Details\n")
-	p.Formfeed()
-	p.SetUnderline(0)
-	p.WriteString("Ibrahim COBANI\n")
-	p.WriteString("(532 540 1194)\n")
-	p.Formfeed()
-	p.WriteString("------------------------------\n")
-	p.WriteString("ORDER DETAILS\n")
-	p.WriteString("------------------------------\n")
-	p.SetEmphasize(0)
-	p.SetFont("A")
-	p.SetFontSize(1, 2)
-	p.SetAlign("right")
-	p.WriteString("1x3. SA-TAY KING PRAWN              " + encoded + "10.95\n")
-	p.WriteString("1x61. Jungle Curry with Chicken     " + encoded + "8.95\n")
-	p.WriteString("1x141. Steamed Rice                 " + encoded + "2.75\n")
-	p.WriteString("1x130. Sauted Aubergine with..      " + encoded + "7.25\n")
-
-	p.SetFont("B")
-	p.SetFontSize(2, 2)
-	p.SetEmphasize(1)
-
-	p.WriteString("------------------------------\n")
-	p.SetFont("A")
-	p.SetFontSize(1, 1)
-	p.SetEmphasize(1)
-	p.WriteString("Sub Total (4 Items)     " + encoded + "29.90\n")
-	p.WriteString("Total                   " + encoded + "29.90\n")
-	p.WriteString("Paid : (Cards - dineNet)" + encoded + "29.90\n")
-	p.Formfeed()
 	p.SetAlign("center")
-	p.SetFontSize(1, 2)
-	p.SetEmphasize(0)
-	p.WriteString("Signature _________________________________\n")
-	p.Formfeed()
-	p.SetEmphasize(1)
-	p.WriteString("Thank you, Please call again\n")
-	p.WriteString("Yum Yum Thai Restaurants Ltd.\n")
-
-	p.Formfeed()
-	p.Cut()
-
-	err = p.EndPage()
-	if err != nil {
-		t.Fatalf("EndPage failed: %v", err)
+	ft.Reset()
+	if err := p.Feed(FeedOptions{Lines: 1, ResetStyles: true}); err != nil {
+		t.Fatal(err)
 	}
-
-}
-
-func TestReadNames(t *testing.T) {
-	names, err := ReadNames()
-	if err != nil {
-		t.Fatalf("ReadNames failed: %v", err)
-	}
-	name, err := Default()
-	if err != nil {
-		t.Fatalf("Default failed: %v", err)
+	want := "\x1Bd\x01" +
+		"\x1BG\x00\x1BV\x00\x1Db\x00\x1DB\x00\x1B-\x00\x1B{\x00\x1D!\x00" +
+		"\x1BM\x00\x1Ba\x00"
+	if got := ft.String(); got != want {
+		t.Errorf("wrote %q, want %q", got, want)
 	}
-	// make sure default printer is listed
-	for _, v := range names {
-		if v == name {
-			return
-		}
+	if p.Font() != FontA || p.align != AlignLeft {
+		t.Errorf("font %v, align %v after reset", p.Font(), p.align)
 	}
-	t.Fatalf("Default printed %q is not listed amongst printers returned by ReadNames %q", name, names)
 }
 
-func TestDriverInfo(t *testing.T) {
-	name, err := Default()
+func TestFeedOptions(t *testing.T) {
+	o, err := feedOptions(map[string]string{"line": "2", "unit": "30"})
 	if err != nil {
-		t.Fatalf("Default failed: %v", err)
+		t.Fatal(err)
 	}
-
-	p, err := Open(name)
-	if err != nil {
-		t.Fatalf("Open failed: %v", err)
+	if want := (FeedOptions{Lines: 3, Dots: 30, ResetStyles: true}); o != want {
+		t.Errorf("got %+v, want %+v", o, want)
 	}
-	defer p.Close()
-
-	di, err := p.DriverInfo()
-	if err != nil {
-		t.Fatalf("DriverInfo failed: %v", err)
+	if _, err := feedOptions(map[string]string{"line": "x"}); err == nil {
+		t.Error("no error for invalid line")
 	}
-	t.Logf("%+v", di)
 }
 
-func TestJobs(t *testing.T) {
-	names, err := ReadNames()
+func TestText(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	err := p.Text(TextOptions{Align: AlignCenter, Width: 2, Height: 2, Bold: true, X: 10}, "Total")
 	if err != nil {
-		t.Fatalf("ReadNames failed: %v", err)
+		t.Fatal(err)
 	}
-	for _, name := range names {
-		t.Log("Printer Name:", name)
-		p, err := Open(name)
-		if err != nil {
-			t.Fatalf("Open failed: %v", err)
-		}
-		defer p.Close()
-
-		pj, err := p.Jobs()
-		if err != nil {
-			t.Fatalf("Jobs failed: %v", err)
-		}
-		if len(pj) > 0 {
-			t.Log("Print Jobs:", len(pj))
-			for _, j := range pj {
-				b, err := json.MarshalIndent(j, "", "   ")
-				if err == nil && len(b) > 0 {
-					t.Log(string(b))
-				}
-			}
-		}
+	want := "\x1D!\x11\x1BG\x01\x1Ba\x01\x1Db\x00\x1B$\x0a\x00Total"
+	if got := ft.String(); got != want {
+		t.Errorf("wrote %q, want %q", got, want)
 	}
-}
-func TestPrinter_QRCode(t *testing.T) {
-	name, err := Default()
-	if err != nil {
-		t.Fatalf("Default failed: %v", err)
+	if err := p.Text(TextOptions{Width: 9}, "x"); err == nil {
+		t.Error("no error for width 9")
 	}
-
-	p, err := Open(name)
-	p.Debug = true
-	if err != nil {
-		t.Fatalf("Open failed: %v", err)
-	}
-	defer p.Close()
-
-	err = p.StartDocument("my document", "RAW")
-	if err != nil {
-		t.Fatalf("StartDocument failed: %v", err)
-	}
-	defer p.EndDocument()
-	err = p.StartPage()
-	if err != nil {
-		t.Fatalf("StartPage failed: %v", err)
-	}
-
-	p.Init()
-	p.SetFontSize(2, 2)
-	p.SetFont("B")
-	p.SetAlign("center")
-	p.WriteString("** CARD PAYMENT **\n")
-	p.WriteString("------------------------\n")
-	p.WriteString("GETMENULINK Ref: 1544\n")
-	p.WriteString("ACEPTED (Auto)\n")
-
-	p.Cut()
-
-	err = p.EndPage()
-	if err != nil {
-		t.Fatalf("EndPage failed: %v", err)
+	if err := p.Text(TextOptions{Align: Align(7)}, "x"); err == nil {
+		t.Error("no error for alignment 7")
 	}
 }
 
-func TestMercanPrinter(t *testing.T) {
-	name, err := Default()
-	if err != nil {
-		t.Fatalf("Default failed: %v", err)
-	}
-
-	p, err := Open(name)
-	p.Debug = true
-	if err != nil {
-		t.Fatalf("Open failed: %v", err)
-	}
-	defer p.Close()
-
-	err = p.StartDocument("my document", "RAW")
+func TestTextParams(t *testing.T) {
+	p := NewPrinter("fake", new(fakeTransport))
+	p.SetEmphasize(1)
+	o, err := p.textOptions(map[string]string{"align": "right", "font": "font_b", "dh": "1", "ul": "true", "y": "20"})
 	if err != nil {
-		t.Fatalf("StartDocument failed: %v", err)
+		t.Fatal(err)
 	}
-	defer p.EndDocument()
-	err = p.StartPage()
-	if err != nil {
-		t.Fatalf("StartPage failed: %v", err)
+	want := TextOptions{Align: AlignRight, Font: FontB, Width: 1, Height: 2, Bold: true, Underline: 1, Y: 20}
+	if o != want {
+		t.Errorf("got %+v, want %+v", o, want)
 	}
-
-	p.Init()
-	p.SetFontSize(2, 2)
-	p.SetFont("B")
-	p.SetAlign("center")
-	p.WriteString("Mercan'in Odasi\n")
-	p.WriteString("Kapiyi Calmadan\n")
-	p.WriteString("** G I R M E Y I N **\n")
-
-	p.Formfeed()
-	p.Cut()
-
-	err = p.EndPage()
-	if err != nil {
-		t.Fatalf("EndPage failed: %v", err)
+	for _, params := range []map[string]string{
+		{"align": "middle"},
+		{"font": "font_z"},
+		{"width": "wide"},
+	} {
+		if _, err := p.textOptions(params); err == nil {
+			t.Errorf("no error for %v", params)
+		}
 	}
-
 }
@@ -0,0 +1,357 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type DOC_INFO_1 struct {
+	DocName    *uint16
+	OutputFile *uint16
+	Datatype   *uint16
+}
+
+type PRINTER_INFO_1 struct {
+	Flags       uint32
+	Description *uint16
+	Name        *uint16
+	Comment     *uint16
+}
+
+type PRINTER_INFO_4 struct {
+	PrinterName *uint16
+	ServerName  *uint16
+	Attributes  uint32
+}
+
+type PRINTER_INFO_5 struct {
+	PrinterName              *uint16
+	PortName                 *uint16
+	Attributes               uint32
+	DeviceNotSelectedTimeout uint32
+	TransmissionRetryTimeout uint32
+}
+
+type DRIVER_INFO_8 struct {
+	Version                  uint32
+	Name                     *uint16
+	Environment              *uint16
+	DriverPath               *uint16
+	DataFile                 *uint16
+	ConfigFile               *uint16
+	HelpFile                 *uint16
+	DependentFiles           *uint16
+	MonitorName              *uint16
+	DefaultDataType          *uint16
+	PreviousNames            *uint16
+	DriverDate               syscall.Filetime
+	DriverVersion            uint64
+	MfgName                  *uint16
+	OEMUrl                   *uint16
+	HardwareID               *uint16
+	Provider                 *uint16
+	PrintProcessor           *uint16
+	VendorSetup              *uint16
+	ColorProfiles            *uint16
+	InfPath                  *uint16
+	PrinterDriverAttributes  uint32
+	CoreDriverDependencies   *uint16
+	MinInboxDriverVerDate    syscall.Filetime
+	MinInboxDriverVerVersion uint32
+}
+
+type DATATYPES_INFO_1 struct {
+	Name *uint16
+}
+
+type JOB_INFO_1 struct {
+	JobID        uint32
+	PrinterName  *uint16
+	MachineName  *uint16
+	UserName     *uint16
+	Document     *uint16
+	DataType     *uint16
+	Status       *uint16
+	StatusCode   uint32
+	Priority     uint32
+	Position     uint32
+	TotalPages   uint32
+	PagesPrinted uint32
+	Submitted    syscall.Systemtime
+}
+
+type JOB_INFO_2 struct {
+	JobID              uint32
+	PrinterName        *uint16
+	MachineName        *uint16
+	UserName           *uint16
+	Document           *uint16
+	NotifyName         *uint16
+	DataType           *uint16
+	PrintProcessor     *uint16
+	Parameters         *uint16
+	DriverName         *uint16
+	DevMode            *DEVMODE
+	Status             *uint16
+	SecurityDescriptor uintptr
+	StatusCode         uint32
+	Priority           uint32
+	Position           uint32
+	StartTime          uint32
+	UntilTime          uint32
+	TotalPages         uint32
+	Size               uint32
+	Submitted          syscall.Systemtime
+	Time               uint32
+	PagesPrinted       uint32
+}
+
+//sys	GetDefaultPrinter(buf *uint16, bufN *uint32) (err error) = winspool.GetDefaultPrinterW
+//sys	ClosePrinter(h syscall.Handle) (err error) = winspool.ClosePrinter
+//sys	OpenPrinter(name *uint16, h *syscall.Handle, defaults *PRINTER_DEFAULTS) (err error) = winspool.OpenPrinterW
+//sys	StartDocPrinter(h syscall.Handle, level uint32, docinfo *DOC_INFO_1) (job uint32, err error) = winspool.StartDocPrinterW
+//sys	EndDocPrinter(h syscall.Handle) (err error) = winspool.EndDocPrinter
+//sys	WritePrinter(h syscall.Handle, buf *byte, bufN uint32, written *uint32) (err error) = winspool.WritePrinter
+//sys	StartPagePrinter(h syscall.Handle) (err error) = winspool.StartPagePrinter
+//sys	EndPagePrinter(h syscall.Handle) (err error) = winspool.EndPagePrinter
+//sys	EnumPrinters(flags uint32, name *uint16, level uint32, buf *byte, bufN uint32, needed *uint32, returned *uint32) (err error) = winspool.EnumPrintersW
+//sys	GetPrinterDriver(h syscall.Handle, env *uint16, level uint32, di *byte, n uint32, needed *uint32) (err error) = winspool.GetPrinterDriverW
+//sys	GetPrinter(h syscall.Handle, level uint32, buf *byte, bufN uint32, needed *uint32) (err error) = winspool.GetPrinterW
+//sys	EnumPrintProcessorDatatypes(server *uint16, printProcessor *uint16, level uint32, buf *byte, bufN uint32, needed *uint32, returned *uint32) (err error) = winspool.EnumPrintProcessorDatatypesW
+//sys	EnumJobs(h syscall.Handle, firstJob uint32, noJobs uint32, level uint32, buf *byte, bufN uint32, bytesNeeded *uint32, jobsReturned *uint32) (err error) = winspool.EnumJobsW
+
+func Default() (string, error) {
+	b := make([]uint16, 3)
+	n := uint32(len(b))
+	err := GetDefaultPrinter(&b[0], &n)
+	if err != nil {
+		if err != syscall.ERROR_INSUFFICIENT_BUFFER {
+			return "", err
+		}
+		b = make([]uint16, n)
+		err = GetDefaultPrinter(&b[0], &n)
+		if err != nil {
+			return "", err
+		}
+	}
+	return syscall.UTF16ToString(b), nil
+}
+
+func Open(name string) (*Printer, error) {
+	p := Printer{name: name}
+	// TODO: implement pDefault parameter
+	u, err := newUTF16(name)
+	if err != nil {
+		return nil, err
+	}
+	defer u.free()
+	err = OpenPrinter(u.ptr(), &p.h, nil)
+	if err != nil {
+		return nil, accessDenied("Open", name, "Print", "the queue's security settings must grant the user the Print permission; a service running as LocalService or NetworkService usually lacks it on shared queues", err)
+	}
+	p.t = &spoolTransport{h: p.h}
+	return &p, nil
+}
+
+// Jobs returns information about all print jobs on this printer
+func (p *Printer) Jobs() ([]JobInfo, error) {
+	var jobsReturned uint32
+	buf, err := spoolCall(func(b []byte, needed *uint32) error {
+		return EnumJobs(p.h, 0, 255, 2, &b[0], uint32(len(b)), needed, &jobsReturned)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer buf.free()
+	if jobsReturned <= 0 {
+		return nil, nil
+	}
+	// bytes printed are only reported by change notifications
+	printed, err := p.jobBytesPrinted()
+	if err != nil {
+		return nil, err
+	}
+	pjs := make([]JobInfo, 0, jobsReturned)
+	for i := 0; i < int(jobsReturned); i++ {
+		j := (*JOB_INFO_2)(buf.at(i, unsafe.Sizeof(JOB_INFO_2{})))
+		pji := JobInfo{
+			JobID:        j.JobID,
+			StatusCode:   JobStatus(j.StatusCode),
+			Priority:     j.Priority,
+			Position:     j.Position,
+			TotalPages:   j.TotalPages,
+			PagesPrinted: j.PagesPrinted,
+			TotalBytes:   j.Size,
+			BytesPrinted: printed[j.JobID],
+			StartTime:    j.StartTime,
+			UntilTime:    j.UntilTime,
+			Elapsed:      time.Duration(j.Time) * time.Millisecond,
+			SubmittedRaw: SystemTime(j.Submitted),
+		}
+		if j.MachineName != nil {
+			pji.UserMachineName = windows.UTF16PtrToString(j.MachineName)
+		}
+		if j.UserName != nil {
+			pji.UserName = windows.UTF16PtrToString(j.UserName)
+		}
+		if j.Document != nil {
+			pji.DocumentName = windows.UTF16PtrToString(j.Document)
+		}
+		if j.DataType != nil {
+			pji.DataType = windows.UTF16PtrToString(j.DataType)
+		}
+		if j.Status != nil {
+			pji.Status = windows.UTF16PtrToString(j.Status)
+		}
+		if strings.TrimSpace(pji.Status) == "" {
+			pji.Status = pji.StatusCode.String()
+		}
+		pji.Submitted = pji.SubmittedRaw.Time(p.spoolerTimeZone()).UTC()
+		pjs = append(pjs, pji)
+	}
+	return pjs, nil
+}
+
+// DriverInfo returns information about printer p driver.
+func (p *Printer) DriverInfo() (*DriverInfo, error) {
+	buf, err := spoolCall(func(b []byte, needed *uint32) error {
+		return GetPrinterDriver(p.h, nil, 8, &b[0], uint32(len(b)), needed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer buf.free()
+	di := (*DRIVER_INFO_8)(unsafe.Pointer(&buf.b[0]))
+	return &DriverInfo{
+		Attributes:     di.PrinterDriverAttributes,
+		Version:        di.Version,
+		Name:           windows.UTF16PtrToString(di.Name),
+		DriverPath:     windows.UTF16PtrToString(di.DriverPath),
+		DataFile:       windows.UTF16PtrToString(di.DataFile),
+		ConfigFile:     windows.UTF16PtrToString(di.ConfigFile),
+		HelpFile:       windows.UTF16PtrToString(di.HelpFile),
+		DependentFiles: multiSZ(di.DependentFiles),
+		Environment:    windows.UTF16PtrToString(di.Environment),
+		DriverDate:     filetimeToTime(di.DriverDate),
+		DriverVersion:  di.DriverVersion,
+	}, nil
+}
+
+// Datatypes returns the datatypes supported by the print processor of
+// the printer queue.
+func (p *Printer) Datatypes() ([]string, error) {
+	pi, err := p.info2()
+	if err != nil {
+		return nil, err
+	}
+	var returned uint32
+	buf, err := spoolCall(func(b []byte, needed *uint32) error {
+		return EnumPrintProcessorDatatypes(nil, pi.PrintProcessor, 1, &b[0], uint32(len(b)), needed, &returned)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer buf.free()
+	datatypes := make([]string, 0, returned)
+	for i := 0; i < int(returned); i++ {
+		dt := (*DATATYPES_INFO_1)(buf.at(i, unsafe.Sizeof(DATATYPES_INFO_1{})))
+		datatypes = append(datatypes, windows.UTF16PtrToString(dt.Name))
+	}
+	return datatypes, nil
+}
+
+// info2 returns the PRINTER_INFO_2 of the printer queue.
+func (p *Printer) info2() (*PRINTER_INFO_2, error) {
+	buf, err := p.info(2)
+	if err != nil {
+		return nil, err
+	}
+	return (*PRINTER_INFO_2)(unsafe.Pointer(&buf[0])), nil
+}
+
+// info returns the PRINTER_INFO structure of the given level.
+func (p *Printer) info(level uint32) ([]byte, error) {
+	var needed uint32
+	buf := make([]byte, 1)
+	for {
+		err := GetPrinter(p.h, level, &buf[0], uint32(len(buf)), &needed)
+		if err == nil {
+			return buf, nil
+		}
+		if err != syscall.ERROR_INSUFFICIENT_BUFFER {
+			return nil, err
+		}
+		if needed <= uint32(len(buf)) {
+			return nil, err
+		}
+		buf = make([]byte, needed)
+	}
+}
+
+// handle is the spooler handle of a printer queue.
+type handle = syscall.Handle
+
+func (p *Printer) startSpoolDocument(name, datatype string) error {
+	if err := p.checkSpoolSpace(); err != nil {
+		return err
+	}
+	docName, err := newUTF16(name)
+	if err != nil {
+		return err
+	}
+	defer docName.free()
+	dt, err := newUTF16(datatype)
+	if err != nil {
+		return err
+	}
+	defer dt.free()
+	d := DOC_INFO_1{
+		DocName:    docName.ptr(),
+		OutputFile: nil,
+		Datatype:   dt.ptr(),
+	}
+	job, err := StartDocPrinter(p.h, 1, &d)
+	if err != nil {
+		return err
+	}
+	p.job = job
+	p.inDoc, p.inPage = true, false
+	p.datatype = datatype
+	return nil
+}
+
+func (p *Printer) endSpoolDocument() error {
+	if err := EndDocPrinter(p.h); err != nil {
+		return err
+	}
+	p.inDoc, p.inPage = false, false
+	return nil
+}
+
+func (p *Printer) startSpoolPage() error {
+	if err := StartPagePrinter(p.h); err != nil {
+		return err
+	}
+	p.inPage = true
+	return nil
+}
+
+func (p *Printer) endSpoolPage() error {
+	if err := EndPagePrinter(p.h); err != nil {
+		return err
+	}
+	p.inPage = false
+	return nil
+}
+
+func (p *Printer) closeSpooler() error {
+	return ClosePrinter(p.h)
+}
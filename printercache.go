@@ -0,0 +1,74 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+	"sync"
+)
+
+// PrinterCache is a list of printers kept up to date by the spooler: the
+// printers are enumerated again only after the spooler reports that one
+// was added, deleted or changed, so that dashboards can display the list
+// continuously without enumerating it on every refresh.
+type PrinterCache struct {
+	opts      EnumOptions
+	enumerate func(EnumOptions) ([]Info, error)
+
+	mu       sync.Mutex
+	printers []Info
+	stale    bool
+	watching bool // false once notifications stopped
+}
+
+// NewPrinterCache returns a cache of the printers selected by opts, which
+// watches the spooler until ctx is done. After that, or if the spooler
+// stops sending notifications, Printers enumerates on every call.
+func NewPrinterCache(ctx context.Context, opts EnumOptions) (*PrinterCache, error) {
+	changes, err := watchPrinters(ctx, opts.Server)
+	if err != nil {
+		return nil, err
+	}
+	return newPrinterCache(opts, changes, Enumerate), nil
+}
+
+func newPrinterCache(opts EnumOptions, changes <-chan struct{}, enumerate func(EnumOptions) ([]Info, error)) *PrinterCache {
+	c := &PrinterCache{opts: opts, enumerate: enumerate, stale: true, watching: true}
+	go func() {
+		for range changes {
+			c.mu.Lock()
+			c.stale = true
+			c.mu.Unlock()
+		}
+		c.mu.Lock()
+		c.watching = false
+		c.mu.Unlock()
+	}()
+	return c
+}
+
+// Printers returns the printers, enumerating them only if they changed
+// since the previous call. The returned slice belongs to the caller.
+func (c *PrinterCache) Printers() ([]Info, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stale || !c.watching {
+		printers, err := c.enumerate(c.opts)
+		if err != nil {
+			return nil, err
+		}
+		c.printers = printers
+		c.stale = false
+	}
+	return append([]Info(nil), c.printers...), nil
+}
+
+// Invalidate makes the next call to Printers enumerate the printers, for
+// changes the spooler does not report, such as a printer going offline.
+func (c *PrinterCache) Invalidate() {
+	c.mu.Lock()
+	c.stale = true
+	c.mu.Unlock()
+}
@@ -0,0 +1,63 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrinterCache(t *testing.T) {
+	calls := 0
+	enumerate := func(EnumOptions) ([]Info, error) {
+		calls++
+		return []Info{{Name: "Kitchen"}}, nil
+	}
+	changes := make(chan struct{})
+	c := newPrinterCache(EnumOptions{}, changes, enumerate)
+
+	for i := 0; i < 3; i++ {
+		printers, err := c.Printers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(printers) != 1 || printers[0].Name != "Kitchen" {
+			t.Fatalf("Printers = %+v", printers)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("enumerated %d times without changes, want 1", calls)
+	}
+
+	changes <- struct{}{}
+	waitFor(t, func() bool {
+		c.Printers()
+		return calls == 2
+	})
+
+	c.Invalidate()
+	c.Printers()
+	if calls != 3 {
+		t.Errorf("enumerated %d times after Invalidate, want 3", calls)
+	}
+
+	// without notifications every call enumerates
+	close(changes)
+	waitFor(t, func() bool {
+		n := calls
+		c.Printers()
+		return calls == n+1
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	PRINTER_CHANGE_ADD_PRINTER    = 0x00000001
+	PRINTER_CHANGE_SET_PRINTER    = 0x00000002
+	PRINTER_CHANGE_DELETE_PRINTER = 0x00000004
+	PRINTER_CHANGE_PRINTER        = 0x000000FF
+)
+
+// watchPrinters sends on the returned channel when a printer of server,
+// or of the local machine if server is empty, is added, deleted or
+// changed, until ctx is done. Changes are coalesced while the receiver is
+// busy. The channel is closed when watching stops.
+func watchPrinters(ctx context.Context, server string) (<-chan struct{}, error) {
+	var name *uint16
+	if server != "" {
+		var err error
+		if name, err = windows.UTF16PtrFromString(server); err != nil {
+			return nil, err
+		}
+	}
+	var h syscall.Handle
+	if err := OpenPrinter(name, &h, nil); err != nil {
+		return nil, err
+	}
+	change, err := FindFirstPrinterChangeNotification(h, PRINTER_CHANGE_PRINTER, 0, nil)
+	if err != nil {
+		ClosePrinter(h)
+		return nil, err
+	}
+	stop, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		FindClosePrinterChangeNotification(change)
+		ClosePrinter(h)
+		return nil, err
+	}
+	ch := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			windows.SetEvent(stop)
+		case <-done:
+		}
+	}()
+	go func() {
+		defer close(ch)
+		defer close(done)
+		defer windows.CloseHandle(stop)
+		defer ClosePrinter(h)
+		defer FindClosePrinterChangeNotification(change)
+		for {
+			ev, err := windows.WaitForMultipleObjects([]windows.Handle{windows.Handle(change), stop}, false, windows.INFINITE)
+			if err != nil || ev != windows.WAIT_OBJECT_0 {
+				return
+			}
+			var cause uint32
+			if err := FindNextPrinterChangeNotification(change, &cause, nil, nil); err != nil {
+				return
+			}
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return ch, nil
+}
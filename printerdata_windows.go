@@ -0,0 +1,118 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type PRINTER_ENUM_VALUES struct {
+	ValueName  *uint16
+	ValueNameN uint32
+	Type       uint32
+	Data       *byte
+	DataN      uint32
+}
+
+const (
+	REG_SZ    = 1
+	REG_DWORD = 4
+
+	ERROR_MORE_DATA = syscall.Errno(234)
+)
+
+//sys	EnumPrinterKey(h syscall.Handle, key *uint16, subkeys *uint16, subkeysN uint32, needed *uint32) (errno error) = winspool.EnumPrinterKeyW
+//sys	EnumPrinterDataEx(h syscall.Handle, key *uint16, buf *byte, bufN uint32, needed *uint32, returned *uint32) (errno error) = winspool.EnumPrinterDataExW
+
+// dataKeys returns the subkeys of the printer data key.
+func (p *Printer) dataKeys(key string) ([]string, error) {
+	u, err := newUTF16(key)
+	if err != nil {
+		return nil, err
+	}
+	defer u.free()
+	k := u.ptr()
+	b := make([]uint16, 1)
+	var needed uint32
+	for {
+		err := EnumPrinterKey(p.h, k, &b[0], uint32(2*len(b)), &needed)
+		if err == nil {
+			break
+		}
+		if err != ERROR_MORE_DATA || needed <= uint32(2*len(b)) {
+			return nil, err
+		}
+		b = make([]uint16, needed/2+1)
+	}
+	return multiSZ(&b[0]), nil
+}
+
+// dataValues returns the values of the printer data key. REG_SZ values
+// are returned as string, REG_DWORD values as uint32 and others as
+// []byte.
+func (p *Printer) dataValues(key string) (map[string]interface{}, error) {
+	u, err := newUTF16(key)
+	if err != nil {
+		return nil, err
+	}
+	defer u.free()
+	k := u.ptr()
+	var returned uint32
+	buf, err := spoolCall(func(b []byte, needed *uint32) error {
+		return EnumPrinterDataEx(p.h, k, &b[0], uint32(len(b)), needed, &returned)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer buf.free()
+	values := make(map[string]interface{}, returned)
+	for i := 0; i < int(returned); i++ {
+		v := (*PRINTER_ENUM_VALUES)(buf.at(i, unsafe.Sizeof(PRINTER_ENUM_VALUES{})))
+		name := windows.UTF16PtrToString(v.ValueName)
+		var data []byte
+		if v.DataN > 0 {
+			data = (*[1 << 20]byte)(unsafe.Pointer(v.Data))[:v.DataN:v.DataN]
+		}
+		switch {
+		case v.Type == REG_SZ && len(data) >= 2:
+			values[name] = windows.UTF16PtrToString((*uint16)(unsafe.Pointer(&data[0])))
+		case v.Type == REG_SZ:
+			values[name] = ""
+		case v.Type == REG_DWORD && len(data) >= 4:
+			values[name] = *(*uint32)(unsafe.Pointer(&data[0]))
+		default:
+			values[name] = append([]byte(nil), data...)
+		}
+	}
+	return values, nil
+}
+
+// dataString returns v as a string, as read by dataValues.
+func dataString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return ""
+}
+
+// dataInt returns v as an integer, or -1 if it is not a number.
+func dataInt(v interface{}) int {
+	switch v := v.(type) {
+	case uint32:
+		return int(int32(v))
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return -1
+}
@@ -0,0 +1,151 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// PrinterID identifies a printer model and its firmware, see
+// Printer.PrinterID.
+type PrinterID struct {
+	ModelID byte // GS I 1
+	TypeID  byte // GS I 2, see the Has methods
+	Version byte // GS I 3, the ROM version ID
+	// Maker, Model and Firmware are the manufacturer, model name and
+	// firmware version. They are empty if the printer does not report
+	// them.
+	Maker    string
+	Model    string
+	Firmware string
+}
+
+// Bits of the type ID.
+const (
+	printerTypeMultiByte = 1 << 0
+	printerTypeCutter    = 1 << 1
+	printerTypeDisplay   = 1 << 2
+)
+
+// HasMultiByte reports whether the printer supports two-byte character
+// codes, such as for Chinese or Japanese.
+func (id *PrinterID) HasMultiByte() bool { return id.TypeID&printerTypeMultiByte != 0 }
+
+// HasCutter reports whether the printer has an autocutter.
+func (id *PrinterID) HasCutter() bool { return id.TypeID&printerTypeCutter != 0 }
+
+// HasDisplay reports whether a customer display is connected to the
+// printer.
+func (id *PrinterID) HasDisplay() bool { return id.TypeID&printerTypeDisplay != 0 }
+
+// PrinterID queries the printer with GS I for its model, type and
+// version IDs, and for its maker, model name and firmware version where
+// supported. The printer must be reachable through a port that reads,
+// see Printer.Read; set a read deadline to bound the wait for printers
+// that do not answer.
+func (p *Printer) PrinterID() (*PrinterID, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var id PrinterID
+	for _, q := range []struct {
+		n byte
+		b *byte
+	}{{1, &id.ModelID}, {2, &id.TypeID}, {3, &id.Version}} {
+		b, err := p.transmitID(q.n)
+		if err != nil {
+			return nil, err
+		}
+		*q.b = b
+	}
+	for _, q := range []struct {
+		n byte
+		s *string
+	}{{66, &id.Maker}, {67, &id.Model}, {65, &id.Firmware}} {
+		s, err := p.transmitIDString(q.n)
+		if err != nil {
+			return nil, err
+		}
+		*q.s = s
+	}
+	return &id, nil
+}
+
+// transmit printer ID -- GS I n, for the IDs answered with a byte
+func (p *Printer) transmitID(n byte) (byte, error) {
+	if err := p.flushLocked(); err != nil {
+		return 0, err
+	}
+	p.lastIO = time.Now()
+	if _, err := p.t.Write([]byte{gs, 'I', n}); err != nil {
+		return 0, err
+	}
+	b, err := p.readByte()
+	if err != nil {
+		return 0, err
+	}
+	// bit 4 is never set, bit 7 never for the ID of a printer
+	if b&0x90 != 0 {
+		return 0, fmt.Errorf("printer: invalid printer ID %#x", b)
+	}
+	return b, nil
+}
+
+// transmitIDString returns the printer information answered to GS I n.
+func (p *Printer) transmitIDString(n byte) (string, error) {
+	return p.queryInfo([]byte{gs, 'I', n})
+}
+
+// queryInfo sends cmd and returns the information the printer answers
+// as "_" followed by the text and NUL, like to GS I 65 to 69. Printers
+// answering with a single byte, such as an ID, do not support cmd, for
+// which it returns "".
+func (p *Printer) queryInfo(cmd []byte) (string, error) {
+	if err := p.flushLocked(); err != nil {
+		return "", err
+	}
+	p.lastIO = time.Now()
+	if _, err := p.t.Write(cmd); err != nil {
+		return "", err
+	}
+	b, err := p.readByte()
+	if err != nil {
+		return "", err
+	}
+	if b != '_' {
+		return "", nil
+	}
+	var s []byte
+	for {
+		b, err := p.readByte()
+		if err != nil {
+			return "", err
+		}
+		if b == 0 {
+			return string(s), nil
+		}
+		if len(s) == 80 {
+			return "", fmt.Errorf("printer: information answered to % x too long", cmd)
+		}
+		s = append(s, b)
+	}
+}
+
+// readByte reads a byte sent back by the printer.
+func (p *Printer) readByte() (byte, error) {
+	if err := p.flushLocked(); err != nil {
+		return 0, err
+	}
+	b := make([]byte, 1)
+	n, err := p.t.Read(b)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return b[0], nil
+}
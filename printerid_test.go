@@ -0,0 +1,44 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrinterID(t *testing.T) {
+	ft := &fakeTransport{status: []byte("\x20\x02\x41_EPSON\x00_TM-T20\x00_1.00 ESC/POS\x00")}
+	p := NewPrinter("fake", ft)
+	id, err := p.PrinterID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &PrinterID{ModelID: 0x20, TypeID: 2, Version: 0x41, Maker: "EPSON", Model: "TM-T20", Firmware: "1.00 ESC/POS"}
+	if !reflect.DeepEqual(id, want) {
+		t.Errorf("got %+v, want %+v", id, want)
+	}
+	if !id.HasCutter() || id.HasMultiByte() || id.HasDisplay() {
+		t.Errorf("type %#x: wrong features", id.TypeID)
+	}
+	if got, want := ft.String(), "\x1DI\x01\x1DI\x02\x1DI\x03\x1DIB\x1DIC\x1DIA"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+
+	// no extended information
+	ft = &fakeTransport{status: []byte("\x20\x02\x41\x20\x20\x20")}
+	id, err = NewPrinter("fake", ft).PrinterID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Maker != "" || id.Model != "" || id.Firmware != "" {
+		t.Errorf("got %+v", id)
+	}
+
+	ft = &fakeTransport{status: []byte("\x20")}
+	if _, err := NewPrinter("fake", ft).PrinterID(); err == nil {
+		t.Error("no error for a missing answer")
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package printrpc serves the printers of a machine over gRPC, so that
+// services can print through a store-local agent with typed requests and
+// streaming job status. The service is defined in printer.proto.
+package printrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative printer.proto
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/icobani/printer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultInterval is the default polling interval of StreamStatus.
+const DefaultInterval = time.Second
+
+// Server implements PrintServiceServer on the printers of the local
+// machine. Register it with RegisterPrintServiceServer.
+type Server struct {
+	UnimplementedPrintServiceServer
+
+	// Interval is how often StreamStatus polls the print queue. Zero
+	// means DefaultInterval.
+	Interval time.Duration
+
+	// Quota, if set, limits the documents submitted per API key, taken
+	// from the x-api-key metadata of the calls.
+	Quota *printer.QuotaPolicy
+
+	// Dedup, if set, makes SubmitDocument fail with AlreadyExists for
+	// the documents whose document_id was printed recently.
+	Dedup *printer.Dedup
+}
+
+// NewServer returns a Server with the default settings.
+func NewServer() *Server {
+	return &Server{}
+}
+
+// ListPrinters returns the installed printers.
+func (s *Server) ListPrinters(ctx context.Context, req *ListPrintersRequest) (*ListPrintersResponse, error) {
+	printers, err := printer.Enumerate(printer.EnumOptions{Level: 2, Pattern: req.Pattern})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	def, _ := printer.Default()
+	resp := &ListPrintersResponse{Printers: make([]*PrinterInfo, len(printers))}
+	for i, p := range printers {
+		resp.Printers[i] = &PrinterInfo{
+			Name:      p.Name,
+			Port:      p.PortName,
+			Driver:    p.DriverName,
+			Location:  p.Location,
+			IsDefault: p.Name == def,
+			Status:    p.Status,
+			Jobs:      p.Jobs,
+		}
+	}
+	return resp, nil
+}
+
+// SubmitDocument prints req.Data unmodified, as raw data.
+func (s *Server) SubmitDocument(ctx context.Context, req *SubmitDocumentRequest) (*SubmitDocumentResponse, error) {
+	if len(req.Data) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty document")
+	}
+	name, err := resolve(req.Printer)
+	if err != nil {
+		return nil, err
+	}
+	p, err := printer.Open(name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	defer p.Close()
+	doc := req.Name
+	if doc == "" {
+		doc = "gRPC document"
+	}
+	if err := s.submit(ctx, p, doc, req); err != nil {
+		return nil, statusError(err)
+	}
+	return &SubmitDocumentResponse{JobId: p.JobID(), Printer: name}, nil
+}
+
+// submit prints req.Data on p as the document doc, accounting it to the
+// quota of the caller. The pages are counted by printer.ESCPOSPages.
+// Documents that fail to print are not accounted.
+func (s *Server) submit(ctx context.Context, p *printer.Printer, doc string, req *SubmitDocumentRequest) error {
+	p.Dedup = s.Dedup
+	return p.PrintOnce(req.DocumentId, func() error {
+		if s.Quota == nil {
+			return p.PrintRaw(doc, req.Data)
+		}
+		user, pages, n := apiKey(ctx), printer.ESCPOSPages(req.Data), int64(len(req.Data))
+		if err := s.Quota.Reserve(ctx, user, pages, n); err != nil {
+			return err
+		}
+		err := p.PrintRaw(doc, req.Data)
+		if err != nil {
+			s.Quota.Release(user, pages, n)
+		}
+		return err
+	})
+}
+
+// StreamStatus sends the changes of the jobs of req.Printer, or of the
+// job req.JobId only if set, until the client cancels the call or the job
+// leaves the queue.
+func (s *Server) StreamStatus(req *StreamStatusRequest, stream PrintService_StreamStatusServer) error {
+	p, err := open(req.Printer)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ctx := stream.Context()
+	events, err := p.WatchJobs(ctx, interval)
+	if err != nil {
+		return statusError(err)
+	}
+	for e := range events {
+		if req.JobId != 0 && e.Job.JobID != req.JobId {
+			continue
+		}
+		if err := stream.Send(newJobEvent(e)); err != nil {
+			return err
+		}
+		if req.JobId != 0 && e.Kind == printer.JobRemoved {
+			return nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Unavailable, "print queue cannot be read anymore")
+}
+
+// CancelJob cancels the job req.JobId of req.Printer.
+func (s *Server) CancelJob(ctx context.Context, req *CancelJobRequest) (*CancelJobResponse, error) {
+	if req.JobId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing job ID")
+	}
+	p, err := open(req.Printer)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+	if err := p.CancelJob(req.JobId); err != nil {
+		return nil, statusError(err)
+	}
+	return &CancelJobResponse{}, nil
+}
+
+// resolve returns the name of the printer name, an abbreviation of it or
+// the default printer if name is empty.
+func resolve(name string) (string, error) {
+	var err error
+	if name == "" {
+		name, err = printer.Default()
+	} else {
+		name, err = printer.Resolve(name)
+	}
+	if err != nil {
+		return "", status.Error(codes.NotFound, err.Error())
+	}
+	return name, nil
+}
+
+// open opens the printer name as resolved by resolve. Errors are returned
+// as gRPC status errors.
+func open(name string) (*printer.Printer, error) {
+	name, err := resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := printer.Open(name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return p, nil
+}
+
+// apiKey returns the API key of the call.
+func apiKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get("x-api-key"); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// statusError converts the errors of the printer package to gRPC status
+// errors.
+func statusError(err error) error {
+	code := codes.Internal
+	var quota *printer.QuotaExceededError
+	switch {
+	case errors.As(err, &quota):
+		code = codes.ResourceExhausted
+	case errors.Is(err, os.ErrPermission):
+		code = codes.PermissionDenied
+	case errors.Is(err, printer.ErrUnsupported):
+		code = codes.Unimplemented
+	case errors.Is(err, printer.ErrOffline):
+		code = codes.Unavailable
+	case errors.Is(err, printer.ErrDuplicate):
+		code = codes.AlreadyExists
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Error())
+}
+
+var jobEventKinds = map[printer.JobEventKind]JobEvent_Kind{
+	printer.JobAdded:   JobEvent_ADDED,
+	printer.JobChanged: JobEvent_CHANGED,
+	printer.JobRemoved: JobEvent_REMOVED,
+}
+
+func newJobEvent(e printer.JobEvent) *JobEvent {
+	return &JobEvent{
+		Kind: jobEventKinds[e.Kind],
+		Job: &Job{
+			Id:           e.Job.JobID,
+			Document:     e.Job.DocumentName,
+			User:         e.Job.UserName,
+			Status:       e.Job.Status,
+			StatusCode:   uint32(e.Job.StatusCode),
+			TotalPages:   e.Job.TotalPages,
+			PagesPrinted: e.Job.PagesPrinted,
+			Submitted:    e.Job.Submitted.Unix(),
+		},
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/icobani/printer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewJobEvent(t *testing.T) {
+	submitted := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	e := newJobEvent(printer.JobEvent{
+		Kind: printer.JobRemoved,
+		Job:  printer.JobInfo{JobID: 7, DocumentName: "ticket", TotalPages: 2, Submitted: submitted},
+	})
+	if e.Kind != JobEvent_REMOVED {
+		t.Errorf("kind = %v, want REMOVED", e.Kind)
+	}
+	if e.Job.Id != 7 || e.Job.Document != "ticket" || e.Job.TotalPages != 2 || e.Job.Submitted != submitted.Unix() {
+		t.Errorf("job = %v", e.Job)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	if code := status.Code(statusError(printer.ErrOffline)); code != codes.Unavailable {
+		t.Errorf("offline: code = %v, want %v", code, codes.Unavailable)
+	}
+	if code := status.Code(statusError(printer.ErrDuplicate)); code != codes.AlreadyExists {
+		t.Errorf("duplicate: code = %v, want %v", code, codes.AlreadyExists)
+	}
+}
+
+// bufTransport is a printer.Transport writing to a buffer, or failing
+// with err if set.
+type bufTransport struct {
+	bytes.Buffer
+	err error
+}
+
+func (t *bufTransport) Write(b []byte) (int, error) {
+	if t.err != nil {
+		return 0, t.err
+	}
+	return t.Buffer.Write(b)
+}
+
+func (t *bufTransport) Close() error                     { return nil }
+func (t *bufTransport) SetWriteDeadline(time.Time) error { return nil }
+func (t *bufTransport) SetReadDeadline(time.Time) error  { return nil }
+
+func TestSubmitQuota(t *testing.T) {
+	s := NewServer()
+	s.Quota = printer.NewQuotaPolicy(printer.Quota{Jobs: 1})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "till"))
+	req := &SubmitDocumentRequest{Data: []byte("one\ftwo\f")}
+
+	failing := printer.NewPrinter("fake", &bufTransport{err: errors.New("paper jam")})
+	if err := s.submit(ctx, failing, "doc", req); err == nil {
+		t.Fatal("no error from a failing printer")
+	}
+	if u := s.Quota.Usage("till"); u.Jobs != 0 || u.Pages != 0 || u.Bytes != 0 {
+		t.Errorf("failed print accounted: %+v", u)
+	}
+
+	tr := new(bufTransport)
+	if err := s.submit(ctx, printer.NewPrinter("fake", tr), "doc", req); err != nil {
+		t.Fatal(err)
+	}
+	if tr.String() != string(req.Data) {
+		t.Errorf("printed %q, want %q", tr.String(), req.Data)
+	}
+	if u := s.Quota.Usage("till"); u.Jobs != 1 || u.Pages != 2 || u.Bytes != int64(len(req.Data)) {
+		t.Errorf("usage = %+v, want 1 job of 2 pages and %d bytes", u, len(req.Data))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// PDFPrinterName is the default queue name of the PDF printer built into
+// Windows, and PDFPrinterDriver the name of its driver.
+const (
+	PDFPrinterName   = "Microsoft Print to PDF"
+	PDFPrinterDriver = "Microsoft Print To PDF"
+)
+
+// PrintToPDF prints doc to the file outputPath using the PDF printer built
+// into Windows. No save dialog is shown.
+func PrintToPDF(doc *Document, outputPath string) error {
+	path, err := filepath.Abs(outputPath)
+	if err != nil {
+		return err
+	}
+	name, err := pdfPrinter()
+	if err != nil {
+		return err
+	}
+	p, err := Open(name)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	dc, err := p.NewDC(doc.Options)
+	if err != nil {
+		return err
+	}
+	defer dc.Close()
+	return doc.print(dc, path)
+}
+
+// pdfPrinter returns the name of the PDF printer queue.
+func pdfPrinter() (string, error) {
+	names, err := ReadNames()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range names {
+		if strings.EqualFold(name, PDFPrinterName) {
+			return name, nil
+		}
+	}
+	// the queue may have been renamed, look for its driver instead
+	for _, name := range names {
+		p, err := Open(name)
+		if err != nil {
+			continue
+		}
+		di, err := p.DriverInfo()
+		p.Close()
+		if err == nil && strings.EqualFold(di.Name, PDFPrinterDriver) {
+			return name, nil
+		}
+	}
+	return "", errors.New("printer: " + PDFPrinterName + " is not installed")
+}
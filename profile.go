@@ -0,0 +1,123 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "time"
+
+// Profile describes the capabilities of an ESC/POS printer model, which
+// vary a lot between vendors and models.
+type Profile struct {
+	Name string
+	// Width is the printable width in dots.
+	Width int
+	// Fonts maps the fonts supported with ESC M to the number of
+	// characters per line they print.
+	Fonts map[Font]int
+	// MaxCharSize is the largest character width and height multiplier
+	// accepted by GS !. Zero means 8.
+	MaxCharSize uint8
+	// CharSets are the international character sets supported with
+	// ESC R. Nil means all of them.
+	CharSets []CharSet
+	// BandHeight is the number of rows of raster images sent in one
+	// command. Taller images are sent in bands so they do not overflow
+	// small printer buffers. Zero sends images whole.
+	BandHeight int
+	// BandDelay is the time waited between the bands of raster images.
+	BandDelay time.Duration
+	// Finish ends the documents, see Printer.End. Nil means
+	// DefaultFinish.
+	Finish *Finish
+	// CodePage is the character code table selected with ESC t by Init,
+	// such as 0 for PC437 or 16 for WPC1252.
+	CodePage uint8
+	// CodePages are the code pages the printer supports, which
+	// currency symbols missing from the current code page are printed
+	// from. Nil means all the code pages the package can encode.
+	CodePages []uint8
+	// CharSet is the international character set selected by Init.
+	CharSet CharSet
+	// LineSpacing is the line spacing in dots set by Init. Zero means
+	// the printer's default spacing.
+	LineSpacing uint8
+	// NoGraphics is set for printers that do not print raster images
+	// with GS v 0, such as most impact printers.
+	NoGraphics bool
+	// SerialQuery and MACQuery are the commands the printer answers
+	// with its serial number and network MAC address, as "_" followed
+	// by the text and NUL. Nil SerialQuery means GS I 68; nil MACQuery
+	// means the printer does not report its MAC address.
+	SerialQuery []byte
+	MACQuery    []byte
+	// Recovery tells how documents failing after part of their data was
+	// written are printed again, see PartialWriteError.
+	Recovery Recovery
+	// NetConfig encodes the utility commands configuring the network
+	// interface of the model, see Printer.ConfigureNetwork. Nil means
+	// it cannot be configured remotely.
+	NetConfig NetConfigEncoder
+	// NoSelfTest is set for printers that do not support starting their
+	// self-test with GS ( A, see Printer.SelfTest.
+	NoSelfTest bool
+}
+
+// DefaultProfile is the profile used by printers with no Profile set. It
+// matches a typical 80mm receipt printer.
+var DefaultProfile = &Profile{
+	Name:  "default",
+	Width: 576,
+	Fonts: map[Font]int{FontA: 48, FontB: 64},
+}
+
+// profile returns the profile of p.
+func (p *Printer) profile() *Profile {
+	if p.Profile != nil {
+		return p.Profile
+	}
+	return DefaultProfile
+}
+
+// SupportsCharSet reports whether the printer supports the character set
+// cs.
+func (pr *Profile) SupportsCharSet(cs CharSet) bool {
+	if pr.CharSets == nil {
+		return cs.valid()
+	}
+	for _, c := range pr.CharSets {
+		if c == cs {
+			return true
+		}
+	}
+	return false
+}
+
+// Columns returns the number of characters per line printed in font f at
+// normal size, or 0 if the printer does not support f.
+func (pr *Profile) Columns(f Font) int {
+	return pr.Fonts[f]
+}
+
+// initBytes returns the commands resetting a printer to the defaults of
+// pr, see Printer.Init.
+func (pr *Profile) initBytes() []byte {
+	b := []byte{
+		0x18,     // CAN: clear the page mode buffer
+		esc, 'S', // back to standard mode
+		esc, '@', // clear the print buffer and reset the settings
+		esc, 't', pr.CodePage,
+		esc, 'R', byte(pr.CharSet),
+	}
+	if pr.LineSpacing > 0 {
+		return append(b, esc, '3', pr.LineSpacing)
+	}
+	return append(b, esc, '2')
+}
+
+func (pr *Profile) maxCharSize() uint8 {
+	if pr.MaxCharSize == 0 {
+		return 8
+	}
+	return pr.MaxCharSize
+}
@@ -0,0 +1,31 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInitBytes(t *testing.T) {
+	want := []byte("\x18\x1BS\x1B@\x1Bt\x00\x1BR\x00\x1B2")
+	if got := DefaultProfile.initBytes(); !bytes.Equal(got, want) {
+		t.Errorf("default: got %q, want %q", got, want)
+	}
+	pr := &Profile{CodePage: 16, CharSet: CharSetGermany, LineSpacing: 30}
+	want = []byte("\x18\x1BS\x1B@\x1Bt\x10\x1BR\x02\x1B3\x1e")
+	if got := pr.initBytes(); !bytes.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// the decoder reads through the sequence
+	r, err := DecodeESCPOS(append(pr.initBytes(), "hello\n"...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.blocks) != 1 || r.blocks[0].text != "hello" {
+		t.Errorf("decoded %+v", r.blocks)
+	}
+}
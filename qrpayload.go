@@ -0,0 +1,212 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// The payload builders below produce the content of QR codes for common
+// receipt uses, to be printed with QRCode.
+
+// URLPayload returns the QR code content opening rawurl, such as a link to
+// review a visit. Only http and https URLs are accepted.
+func URLPayload(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("printer: %q is not an http or https URL", rawurl)
+	}
+	return u.String(), nil
+}
+
+// WiFi describes the credentials of a wireless network, such as a guest
+// network printed on the receipt.
+type WiFi struct {
+	SSID     string
+	Password string
+	// Security is "WPA", "WEP" or "nopass". Empty means "WPA", or
+	// "nopass" if there is no password.
+	Security string
+	Hidden   bool
+}
+
+// Payload returns the QR code content joining the network.
+func (w WiFi) Payload() string {
+	sec := w.Security
+	if sec == "" {
+		sec = "WPA"
+		if w.Password == "" {
+			sec = "nopass"
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "WIFI:T:%s;S:%s;", sec, wifiEscape(w.SSID))
+	if sec != "nopass" {
+		fmt.Fprintf(&b, "P:%s;", wifiEscape(w.Password))
+	}
+	if w.Hidden {
+		b.WriteString("H:true;")
+	}
+	b.WriteString(";")
+	return b.String()
+}
+
+func wifiEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `:`, `\:`, `"`, `\"`).Replace(s)
+}
+
+// VCard is a contact card, such as the one of the shop.
+type VCard struct {
+	Name         string // formatted name
+	Organization string
+	Title        string
+	Phone        string
+	Email        string
+	URL          string
+	Address      string // street address
+	City         string
+	PostalCode   string
+	Country      string
+	Note         string
+}
+
+// Payload returns the QR code content as a vCard 3.0.
+func (v VCard) Payload() string {
+	var b strings.Builder
+	line := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s:%s\r\n", key, value)
+		}
+	}
+	b.WriteString("BEGIN:VCARD\r\nVERSION:3.0\r\n")
+	line("N", vcardEscape(v.Name))
+	line("FN", vcardEscape(v.Name))
+	line("ORG", vcardEscape(v.Organization))
+	line("TITLE", vcardEscape(v.Title))
+	line("TEL", vcardEscape(v.Phone))
+	line("EMAIL", vcardEscape(v.Email))
+	line("URL", v.URL)
+	if v.Address != "" || v.City != "" || v.PostalCode != "" || v.Country != "" {
+		line("ADR", ";;"+vcardEscape(v.Address)+";"+vcardEscape(v.City)+";;"+vcardEscape(v.PostalCode)+";"+vcardEscape(v.Country))
+	}
+	line("NOTE", vcardEscape(v.Note))
+	b.WriteString("END:VCARD\r\n")
+	return b.String()
+}
+
+func vcardEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `,`, `\,`, `;`, `\;`, "\n", `\n`).Replace(s)
+}
+
+// EMVMerchant describes a merchant presented payment, encoded as an
+// EMVCo merchant presented QR code.
+type EMVMerchant struct {
+	// Accounts maps the merchant account information IDs, 02 to 51, to
+	// their values, as specified by the payment networks.
+	Accounts map[int]string
+	// CategoryCode is the ISO 18245 merchant category code. Empty means
+	// "0000".
+	CategoryCode string
+	// Currency is the ISO 4217 numeric currency code, such as "978".
+	Currency string
+	// Amount is the amount to pay, such as "12.50". Empty lets the
+	// customer enter it.
+	Amount      string
+	CountryCode string // ISO 3166-1 alpha-2
+	Name        string
+	City        string
+	PostalCode  string
+	// BillNumber and ReferenceLabel are added as additional data.
+	BillNumber     string
+	ReferenceLabel string
+}
+
+// Payload returns the QR code content, ending with its CRC.
+func (m EMVMerchant) Payload() (string, error) {
+	if len(m.Accounts) == 0 {
+		return "", fmt.Errorf("printer: EMV payload without merchant account")
+	}
+	if m.Currency == "" || m.CountryCode == "" || m.Name == "" || m.City == "" {
+		return "", fmt.Errorf("printer: EMV payload needs currency, country, name and city")
+	}
+	var b strings.Builder
+	var err error
+	tlv := func(id int, value string) {
+		if value == "" || err != nil {
+			return
+		}
+		if len(value) > 99 {
+			err = fmt.Errorf("printer: EMV field %02d longer than 99 characters", id)
+			return
+		}
+		fmt.Fprintf(&b, "%02d%02d%s", id, len(value), value)
+	}
+	tlv(0, "01")
+	if m.Amount != "" {
+		tlv(1, "12") // dynamic
+	} else {
+		tlv(1, "11") // static
+	}
+	ids := make([]int, 0, len(m.Accounts))
+	for id := range m.Accounts {
+		if id < 2 || id > 51 {
+			return "", fmt.Errorf("printer: invalid EMV merchant account ID: %d", id)
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		tlv(id, m.Accounts[id])
+	}
+	mcc := m.CategoryCode
+	if mcc == "" {
+		mcc = "0000"
+	}
+	tlv(52, mcc)
+	tlv(53, m.Currency)
+	tlv(54, m.Amount)
+	tlv(58, m.CountryCode)
+	tlv(59, m.Name)
+	tlv(60, m.City)
+	tlv(61, m.PostalCode)
+	if m.BillNumber != "" || m.ReferenceLabel != "" {
+		var add strings.Builder
+		if m.BillNumber != "" {
+			fmt.Fprintf(&add, "01%02d%s", len(m.BillNumber), m.BillNumber)
+		}
+		if m.ReferenceLabel != "" {
+			fmt.Fprintf(&add, "05%02d%s", len(m.ReferenceLabel), m.ReferenceLabel)
+		}
+		tlv(62, add.String())
+	}
+	if err != nil {
+		return "", err
+	}
+	b.WriteString("6304")
+	return b.String() + fmt.Sprintf("%04X", crc16CCITT([]byte(b.String()))), nil
+}
+
+// crc16CCITT returns the CRC-16/CCITT-FALSE of data, as used by EMV QR
+// codes.
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, c := range data {
+		crc ^= uint16(c) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
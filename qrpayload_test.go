@@ -0,0 +1,58 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWiFiPayload(t *testing.T) {
+	w := WiFi{SSID: "Cafe;Guest", Password: "p:ss"}
+	if got, want := w.Payload(), `WIFI:T:WPA;S:Cafe\;Guest;P:p\:ss;;`; got != want {
+		t.Errorf("Payload() = %q, want %q", got, want)
+	}
+	w = WiFi{SSID: "Open", Hidden: true}
+	if got, want := w.Payload(), `WIFI:T:nopass;S:Open;H:true;;`; got != want {
+		t.Errorf("Payload() = %q, want %q", got, want)
+	}
+}
+
+func TestURLPayload(t *testing.T) {
+	if _, err := URLPayload("https://example.com/review?id=1"); err != nil {
+		t.Error(err)
+	}
+	if _, err := URLPayload("javascript:alert(1)"); err == nil {
+		t.Error("URLPayload accepted a javascript URL")
+	}
+}
+
+func TestCRC16CCITT(t *testing.T) {
+	if got := crc16CCITT([]byte("123456789")); got != 0x29B1 {
+		t.Errorf("crc16CCITT = %#04x, want 0x29b1", got)
+	}
+}
+
+func TestEMVPayload(t *testing.T) {
+	m := EMVMerchant{
+		Accounts:    map[int]string{26: "0012com.example0104abcd"},
+		Currency:    "978",
+		Amount:      "12.50",
+		CountryCode: "NL",
+		Name:        "Bakery",
+		City:        "Utrecht",
+	}
+	got, err := m.Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const prefix = "000201010212" + "26230012com.example0104abcd" + "52040000" + "5303978" + "540512.50" + "5802NL" + "5906Bakery" + "6007Utrecht" + "6304"
+	if !strings.HasPrefix(got, prefix) || len(got) != len(prefix)+4 {
+		t.Errorf("Payload() = %q, want %q followed by the CRC", got, prefix)
+	}
+	if _, err := (EMVMerchant{}).Payload(); err == nil {
+		t.Error("empty EMV payload succeeded")
+	}
+}
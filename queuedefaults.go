@@ -0,0 +1,32 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+// DefaultsScope tells whose default DEVMODE of a queue is read or
+// written.
+type DefaultsScope uint32
+
+const (
+	// GlobalDefaults are the defaults of the queue for all users.
+	// Changing them requires a printer opened with OpenAdmin.
+	GlobalDefaults DefaultsScope = 8
+	// UserDefaults are the defaults of the queue for the current user,
+	// which take precedence over the global ones.
+	UserDefaults DefaultsScope = 9
+)
+
+func (s DefaultsScope) valid() bool {
+	return s == GlobalDefaults || s == UserDefaults
+}
+
+// QueueDefaults are common settings of the default DEVMODE of a queue.
+// Zero fields are left unchanged.
+type QueueDefaults struct {
+	Duplex Duplex
+	// PaperSize is one of the DMPAPER_ values, such as 9 for A4.
+	PaperSize   int16
+	Orientation Orientation
+	Copies      int16
+}
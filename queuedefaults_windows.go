@@ -0,0 +1,106 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type PRINTER_INFO_8 struct {
+	DevMode *DEVMODE
+}
+
+type PRINTER_INFO_9 struct {
+	DevMode *DEVMODE
+}
+
+// DefaultDevMode returns a copy of the default DEVMODE of the queue in
+// scope, as the spooler stores it, driver private data included. It
+// returns nil if the scope has no default, as when the user never
+// changed the preferences of the queue.
+func (p *Printer) DefaultDevMode(scope DefaultsScope) ([]byte, error) {
+	if !scope.valid() {
+		return nil, fmt.Errorf("printer: invalid defaults scope: %d", scope)
+	}
+	buf, err := p.info(uint32(scope))
+	if err != nil {
+		return nil, err
+	}
+	// PRINTER_INFO_8 and PRINTER_INFO_9 have the same layout
+	dm := (*PRINTER_INFO_8)(unsafe.Pointer(&buf[0])).DevMode
+	if dm == nil {
+		return nil, nil
+	}
+	n := int(dm.Size) + int(dm.DriverExtra)
+	b := make([]byte, n)
+	copy(b, (*[1 << 20]byte)(unsafe.Pointer(dm))[:n:n])
+	return b, nil
+}
+
+// SetDefaultDevMode has the driver validate dm, a DEVMODE such as one
+// returned by DefaultDevMode, and stores it as the default of the queue
+// in scope. Documents started afterwards by any program, on this or
+// other terminals sharing the queue, use it.
+func (p *Printer) SetDefaultDevMode(scope DefaultsScope, dm []byte) error {
+	if !scope.valid() {
+		return fmt.Errorf("printer: invalid defaults scope: %d", scope)
+	}
+	if len(dm) < int(unsafe.Sizeof(DEVMODE{})) {
+		return fmt.Errorf("printer: invalid DEVMODE of %d bytes", len(dm))
+	}
+	name, err := windows.UTF16PtrFromString(p.name)
+	if err != nil {
+		return err
+	}
+	n, err := DocumentProperties(0, p.h, name, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	out := make([]byte, n)
+	_, err = DocumentProperties(0, p.h, name, &out[0], &dm[0], DM_IN_BUFFER|DM_OUT_BUFFER)
+	if err != nil {
+		return err
+	}
+	pi := PRINTER_INFO_8{DevMode: (*DEVMODE)(unsafe.Pointer(&out[0]))}
+	return SetPrinter(p.h, uint32(scope), (*byte)(unsafe.Pointer(&pi)), 0)
+}
+
+// SetQueueDefaults changes the settings d of the default DEVMODE of the
+// queue in scope, starting from the driver defaults if the scope has
+// none, so that administrators can standardize them across terminals.
+func (p *Printer) SetQueueDefaults(scope DefaultsScope, d QueueDefaults) error {
+	b, err := p.DefaultDevMode(scope)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		if _, err := p.devMode(); err != nil {
+			return err
+		}
+		b = make([]byte, len(p.dm))
+		copy(b, p.dm)
+	}
+	dm := (*DEVMODE)(unsafe.Pointer(&b[0]))
+	if d.Duplex != 0 {
+		dm.Fields |= DM_DUPLEX
+		dm.Duplex = int16(d.Duplex)
+	}
+	if d.PaperSize != 0 {
+		dm.Fields |= DM_PAPERSIZE
+		dm.PaperSize = d.PaperSize
+	}
+	if d.Orientation != 0 {
+		dm.Fields |= DM_ORIENTATION
+		dm.Orientation = int16(d.Orientation)
+	}
+	if d.Copies != 0 {
+		dm.Fields |= DM_COPIES
+		dm.Copies = d.Copies
+	}
+	return p.SetDefaultDevMode(scope, b)
+}
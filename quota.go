@@ -0,0 +1,180 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Quota limits the printing of a user or API key over a period. Zero
+// limits are unlimited.
+type Quota struct {
+	Jobs  int
+	Pages int
+	Bytes int64
+	// Period is the length of the window the limits apply to, starting
+	// with the first job of the window. Zero means the limits never reset.
+	Period time.Duration
+}
+
+// QuotaExceededError is returned by QuotaPolicy.Reserve for jobs beyond
+// the quota of a user.
+type QuotaExceededError struct {
+	User  string
+	Limit string // "jobs", "pages" or "bytes"
+	// Reset is when the quota resets, zero if it never does.
+	Reset time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	if e.Reset.IsZero() {
+		return fmt.Sprintf("printer: %s quota of %q exceeded", e.Limit, e.User)
+	}
+	return fmt.Sprintf("printer: %s quota of %q exceeded until %s", e.Limit, e.User, e.Reset.Format(time.RFC3339))
+}
+
+// QuotaUsage is what a user printed in the current window of a quota.
+type QuotaUsage struct {
+	Jobs  int
+	Pages int
+	Bytes int64
+	Start time.Time
+}
+
+// QuotaPolicy tracks the printing of users or API keys and enforces
+// their quotas, for print servers shared by several users. It is safe for
+// concurrent use.
+type QuotaPolicy struct {
+	// Default is the quota of users not in Users.
+	Default Quota
+	// Users holds the quotas of specific users or API keys.
+	Users map[string]Quota
+	// Wait, if set, makes Reserve wait for the quota to reset instead
+	// of returning a *QuotaExceededError, queueing the jobs beyond it.
+	// Jobs never fitting the quota are rejected anyway.
+	Wait bool
+
+	mu    sync.Mutex
+	usage map[string]*QuotaUsage
+	now   func() time.Time
+}
+
+// NewQuotaPolicy returns a QuotaPolicy applying def to all users.
+func NewQuotaPolicy(def Quota) *QuotaPolicy {
+	return &QuotaPolicy{Default: def}
+}
+
+func (q *QuotaPolicy) quota(user string) Quota {
+	if quota, ok := q.Users[user]; ok {
+		return quota
+	}
+	return q.Default
+}
+
+// Reserve accounts a job of pages pages and n bytes to user, or returns a
+// *QuotaExceededError if it does not fit the quota of user. Unknown page
+// counts can be passed as 0.
+func (q *QuotaPolicy) Reserve(ctx context.Context, user string, pages int, n int64) error {
+	for {
+		err := q.reserve(user, pages, n)
+		e, ok := err.(*QuotaExceededError)
+		if !ok || !q.Wait || e.Reset.IsZero() || !q.fits(user, pages, n) {
+			return err
+		}
+		t := time.NewTimer(time.Until(e.Reset))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// fits reports whether a job fits an empty quota of user.
+func (q *QuotaPolicy) fits(user string, pages int, n int64) bool {
+	quota := q.quota(user)
+	return (quota.Pages == 0 || pages <= quota.Pages) &&
+		(quota.Bytes == 0 || n <= quota.Bytes)
+}
+
+func (q *QuotaPolicy) reserve(user string, pages int, n int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	quota := q.quota(user)
+	now := time.Now
+	if q.now != nil {
+		now = q.now
+	}
+	t := now()
+	if q.usage == nil {
+		q.usage = make(map[string]*QuotaUsage)
+	}
+	u := q.usage[user]
+	if u == nil || quota.Period > 0 && !t.Before(u.Start.Add(quota.Period)) {
+		u = &QuotaUsage{Start: t}
+		q.usage[user] = u
+	}
+	var limit string
+	switch {
+	case quota.Jobs > 0 && u.Jobs+1 > quota.Jobs:
+		limit = "jobs"
+	case quota.Pages > 0 && u.Pages+pages > quota.Pages:
+		limit = "pages"
+	case quota.Bytes > 0 && u.Bytes+n > quota.Bytes:
+		limit = "bytes"
+	}
+	if limit != "" {
+		e := &QuotaExceededError{User: user, Limit: limit}
+		if quota.Period > 0 {
+			e.Reset = u.Start.Add(quota.Period)
+		}
+		return e
+	}
+	u.Jobs++
+	u.Pages += pages
+	u.Bytes += n
+	return nil
+}
+
+// Release gives back a job of pages pages and n bytes reserved for user
+// with Reserve but not printed, such as one that failed.
+func (q *QuotaPolicy) Release(user string, pages int, n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u := q.usage[user]
+	if u == nil {
+		return
+	}
+	if u.Jobs > 0 {
+		u.Jobs--
+	}
+	if u.Pages -= pages; u.Pages < 0 {
+		u.Pages = 0
+	}
+	if u.Bytes -= n; u.Bytes < 0 {
+		u.Bytes = 0
+	}
+}
+
+// Usage returns what user printed in the current window of its quota.
+func (q *QuotaPolicy) Usage(user string) QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if u := q.usage[user]; u != nil {
+		return *u
+	}
+	return QuotaUsage{}
+}
+
+// Reset forgets the usage of user, restoring its full quota.
+func (q *QuotaPolicy) Reset(user string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.usage, user)
+}
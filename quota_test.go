@@ -0,0 +1,92 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuotaPolicy(t *testing.T) {
+	now := time.Date(2021, 6, 1, 9, 0, 0, 0, time.UTC)
+	q := NewQuotaPolicy(Quota{Jobs: 2, Bytes: 1000, Period: time.Hour})
+	q.Users = map[string]Quota{"admin": {}}
+	q.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := q.Reserve(ctx, "alice", 1, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := q.Reserve(ctx, "alice", 1, 100)
+	e, ok := err.(*QuotaExceededError)
+	if !ok || e.Limit != "jobs" || !e.Reset.Equal(now.Add(time.Hour)) {
+		t.Fatalf("third job: %v", err)
+	}
+	if err := q.Reserve(ctx, "bob", 1, 2000); err == nil || err.(*QuotaExceededError).Limit != "bytes" {
+		t.Errorf("large job: %v", err)
+	}
+	if u := q.Usage("bob"); u.Jobs != 0 || u.Bytes != 0 {
+		t.Errorf("rejected job accounted: %+v", u)
+	}
+	for i := 0; i < 5; i++ {
+		if err := q.Reserve(ctx, "admin", 10, 1e6); err != nil {
+			t.Fatalf("unlimited user: %v", err)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if err := q.Reserve(ctx, "alice", 1, 100); err != nil {
+		t.Errorf("after reset: %v", err)
+	}
+	if u := q.Usage("alice"); u.Jobs != 1 || u.Pages != 1 || u.Bytes != 100 {
+		t.Errorf("usage = %+v", u)
+	}
+}
+
+func TestQuotaPolicyWait(t *testing.T) {
+	q := NewQuotaPolicy(Quota{Jobs: 1, Bytes: 100, Period: 20 * time.Millisecond})
+	q.Wait = true
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := q.Reserve(ctx, "", 0, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("second job not queued, took %v", d)
+	}
+	if err := q.Reserve(ctx, "", 0, 1000); err == nil {
+		t.Error("job never fitting the quota queued")
+	}
+
+	q = NewQuotaPolicy(Quota{Jobs: 1, Period: time.Hour})
+	q.Wait = true
+	q.Reserve(ctx, "", 0, 10)
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := q.Reserve(ctx, "", 0, 10); err != context.Canceled {
+		t.Errorf("cancelled wait: %v", err)
+	}
+}
+
+func TestQuotaPolicyRelease(t *testing.T) {
+	q := NewQuotaPolicy(Quota{Jobs: 1, Pages: 2})
+	ctx := context.Background()
+	q.Release("alice", 1, 10) // nothing reserved
+	if err := q.Reserve(ctx, "alice", 2, 100); err != nil {
+		t.Fatal(err)
+	}
+	q.Release("alice", 2, 100)
+	if u := q.Usage("alice"); u.Jobs != 0 || u.Pages != 0 || u.Bytes != 0 {
+		t.Errorf("usage after Release = %+v", u)
+	}
+	if err := q.Reserve(ctx, "alice", 2, 100); err != nil {
+		t.Errorf("after Release: %v", err)
+	}
+}
@@ -0,0 +1,430 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"image"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/message/catalog"
+)
+
+// Receipt is a receipt printed on an ESC/POS printer. Content is added in
+// reading order as blocks, which are only sent to the printer by Print or
+// Render, so the whole receipt can be laid out at once.
+type Receipt struct {
+	Name string
+
+	// UpsideDown prints the receipt rotated by 180 degrees, for printers
+	// mounted upside down under a counter: upside-down printing is
+	// enabled and the blocks are sent last first, so the receipt reads
+	// from top to bottom once torn off.
+	UpsideDown bool
+
+	// Replacements, if set, are applied to the text of the receipt
+	// instead of the replacements of the printer.
+	Replacements *Replacements
+
+	// Locale, if set, is the BCP 47 locale of the receipt, such as
+	// "de-DE". Label translates to it and Render selects the code page
+	// printing its language, see LocaleCodePage.
+	Locale string
+	// Catalog translates the labels of the receipt. Nil means
+	// DefaultCatalog.
+	Catalog catalog.Catalog
+
+	style       Style
+	barcodeOpts BarcodeOptions
+	blocks      []receiptBlock
+}
+
+type receiptBlockKind int
+
+const (
+	receiptText receiptBlockKind = iota
+	receiptFeed
+	receiptImage
+	receiptQRCode
+	receiptBarcode
+	receiptRow
+	receiptRule
+	receiptCut
+	receiptPulse
+)
+
+// receiptBlock is a piece of receipt content.
+type receiptBlock struct {
+	kind  receiptBlockKind
+	text  string
+	style Style
+
+	// feed
+	lines int
+
+	// image
+	img image.Image
+
+	// QR code
+	size, ec uint8
+
+	// barcode
+	barcode     BarcodeType
+	barcodeOpts BarcodeOptions
+
+	// row
+	cols  []Column
+	cells []string
+}
+
+// NewReceipt returns an empty receipt.
+func NewReceipt(name string) *Receipt {
+	return &Receipt{Name: name, style: Style{Width: 1, Height: 1}}
+}
+
+// SetStyle sets the formatting of the content added after it. Only the
+// alignment applies to images and QR codes.
+func (r *Receipt) SetStyle(s Style) {
+	r.style = s
+}
+
+// Style returns the formatting of the content added next.
+func (r *Receipt) Style() Style {
+	return r.style
+}
+
+// SetAlign sets the alignment of the content added after it.
+func (r *Receipt) SetAlign(align Align) {
+	r.style.Align = align
+}
+
+// Text adds a paragraph of text wrapped to the width of the paper.
+func (r *Receipt) Text(s string) {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptText, text: s, style: r.style})
+}
+
+// Row adds a line made of cells laid out in the columns cols, whose
+// widths are in characters; columns with no width share the rest of the
+// line. Cells are cut to the width of their column.
+func (r *Receipt) Row(cols []Column, cells ...string) {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptRow, cols: cols, cells: cells, style: r.style})
+}
+
+// TwoColumns adds a line with left on the left and right, such as an
+// amount, on the right.
+func (r *Receipt) TwoColumns(left, right string) {
+	r.Row([]Column{{}, {Width: utf8.RuneCountInString(right) + 1, Align: AlignRight}}, left, right)
+}
+
+// Rule adds a line of char across the paper.
+func (r *Receipt) Rule(char rune) {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptRule, text: string(char), style: r.style})
+}
+
+// Divider adds a line across the paper drawn in the given style.
+func (r *Receipt) Divider(style DividerStyle) {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptRule, text: style.pattern(), style: r.style})
+}
+
+// Feed adds lines empty lines.
+func (r *Receipt) Feed(lines int) {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptFeed, lines: lines})
+}
+
+// Image adds img, printed one dot per pixel.
+func (r *Receipt) Image(img image.Image) {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptImage, img: img, style: r.style})
+}
+
+// QRCode adds a QR code; see Printer.QRCode.
+func (r *Receipt) QRCode(data string, size, ec uint8) {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptQRCode, text: data, size: size, ec: ec, style: r.style})
+}
+
+// SetBarcodeOptions sets the options of the barcodes added after it.
+func (r *Receipt) SetBarcodeOptions(o BarcodeOptions) {
+	r.barcodeOpts = o
+}
+
+// Barcode adds a barcode; see Printer.Barcode.
+func (r *Receipt) Barcode(data string, t BarcodeType) {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptBarcode, text: data, barcode: t, barcodeOpts: r.barcodeOpts, style: r.style})
+}
+
+// Cut adds a paper cut.
+func (r *Receipt) Cut() {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptCut})
+}
+
+// Pulse adds a pulse opening the cash drawer.
+func (r *Receipt) Pulse() {
+	r.blocks = append(r.blocks, receiptBlock{kind: receiptPulse})
+}
+
+// Print prints r on p as a print job of its own.
+func (r *Receipt) Print(p *Printer) error {
+	err := p.StartRawDocument(r.Name)
+	if err != nil {
+		return err
+	}
+	err = p.StartPage()
+	if err == nil {
+		err = r.Render(p)
+	}
+	if err == nil {
+		err = p.EndPage()
+	}
+	if err != nil {
+		p.EndDocument()
+		return err
+	}
+	return p.EndDocument()
+}
+
+// Render sends r to p, which must have a document and page started.
+func (r *Receipt) Render(p *Printer) error {
+	p.Init()
+	if r.Locale != "" {
+		if err := p.SetCodePage(LocaleCodePage(r.Locale)); err != nil {
+			return err
+		}
+	}
+	blocks, tail := r.blocks, []receiptBlock(nil)
+	if r.UpsideDown {
+		// the cuts, pulses and feeds ending the receipt still come last
+		n := len(blocks)
+		for n > 0 && (blocks[n-1].kind == receiptFeed || blocks[n-1].kind == receiptCut || blocks[n-1].kind == receiptPulse) {
+			n--
+		}
+		blocks, tail = reverseBlocks(blocks[:n]), blocks[n:]
+		p.SetUpsidedown(1)
+	}
+	for _, b := range append(blocks, tail...) {
+		if err := r.renderBlock(p, b); err != nil {
+			return err
+		}
+	}
+	if r.UpsideDown {
+		p.SetUpsidedown(0)
+	}
+	return nil
+}
+
+func (r *Receipt) renderBlock(p *Printer, b receiptBlock) error {
+	switch b.kind {
+	case receiptText:
+		if err := p.SetStyle(b.style); err != nil {
+			return err
+		}
+		lines := wrapColumns(r.replace(p, b.text), p.Columns())
+		if r.UpsideDown {
+			reverseStrings(lines)
+		}
+		for _, line := range lines {
+			if _, err := p.WriteString(line + "\n"); err != nil {
+				return err
+			}
+		}
+	case receiptRow:
+		style := b.style
+		style.Align = AlignLeft
+		if err := p.SetStyle(style); err != nil {
+			return err
+		}
+		cells := make([]string, len(b.cells))
+		for i, c := range b.cells {
+			cells[i] = r.replace(p, c)
+		}
+		if _, err := p.WriteString(formatRow(b.cols, cells, p.Columns()) + "\n"); err != nil {
+			return err
+		}
+	case receiptRule:
+		style := b.style
+		style.Align = AlignLeft
+		if err := p.SetStyle(style); err != nil {
+			return err
+		}
+		if _, err := p.WriteString(ruleLine(b.text, p.Columns()) + "\n"); err != nil {
+			return err
+		}
+	case receiptFeed:
+		if b.lines > 0 {
+			p.FormfeedN(b.lines)
+		}
+	case receiptImage:
+		p.SetAlign(b.style.Align.escpos())
+		img := b.img
+		if r.UpsideDown {
+			img = rotate180(img)
+		}
+		return p.PrintImage(img)
+	case receiptQRCode:
+		p.SetAlign(b.style.Align.escpos())
+		if err := p.QRCode(b.text, b.size, b.ec); err != nil {
+			return err
+		}
+		p.Linefeed()
+	case receiptBarcode:
+		p.SetAlign(b.style.Align.escpos())
+		if err := p.SetBarcodeOptions(b.barcodeOpts); err != nil {
+			return err
+		}
+		if err := p.Barcode(b.text, b.barcode); err != nil {
+			return err
+		}
+		p.Linefeed()
+	case receiptCut:
+		p.Cut()
+	case receiptPulse:
+		p.Pulse()
+	}
+	return nil
+}
+
+// replace applies the replacements of r, or else of p, to s.
+func (r *Receipt) replace(p *Printer, s string) string {
+	if r.Replacements != nil {
+		return r.Replacements.Replace(s)
+	}
+	return p.replacements().Replace(s)
+}
+
+// escpos returns the alignment name accepted by Printer.SetAlign.
+func (a Align) escpos() string {
+	switch a {
+	case AlignCenter:
+		return "center"
+	case AlignRight:
+		return "right"
+	}
+	return "left"
+}
+
+func boolByte(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// wrapColumns wraps s at word boundaries into lines of at most cols
+// characters, breaking words longer than a line.
+func wrapColumns(s string, cols int) []string {
+	if cols <= 0 {
+		return strings.Split(s, "\n")
+	}
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for utf8.RuneCountInString(word) > cols {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				r := []rune(word)
+				lines = append(lines, string(r[:cols]))
+				word = string(r[cols:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= cols:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func reverseBlocks(blocks []receiptBlock) []receiptBlock {
+	rev := make([]receiptBlock, len(blocks))
+	for i, b := range blocks {
+		rev[len(blocks)-1-i] = b
+	}
+	return rev
+}
+
+func reverseStrings(s []string) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// rotate180 returns img rotated by 180 degrees.
+func rotate180(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dst.Set(b.Max.X-1-x, b.Max.Y-1-y, img.At(x, y))
+		}
+	}
+	return dst
+}
+
+// receiptLine is a line of a receipt laid out for a preview.
+type receiptLine struct {
+	kind  receiptBlockKind // receiptText for text, rows, rules and feeds
+	text  string
+	style Style
+	cols  int // characters per line in style
+	block *receiptBlock
+}
+
+// layout breaks the blocks of r into the lines printed on a printer
+// described by pr, in reading order.
+func (r *Receipt) layout(pr *Profile) []receiptLine {
+	if pr == nil {
+		pr = DefaultProfile
+	}
+	replace := r.Replacements
+	if replace == nil {
+		replace = DefaultReplacements
+	}
+	var lines []receiptLine
+	for i := range r.blocks {
+		b := &r.blocks[i]
+		style := b.style
+		if style.Width == 0 {
+			style.Width = 1
+		}
+		if style.Height == 0 {
+			style.Height = 1
+		}
+		cols := pr.Columns(style.Font)
+		if cols == 0 {
+			cols = pr.Columns(FontA)
+		}
+		cols /= int(style.Width)
+		switch b.kind {
+		case receiptText:
+			for _, s := range wrapColumns(replace.Replace(b.text), cols) {
+				lines = append(lines, receiptLine{kind: receiptText, text: s, style: style, cols: cols})
+			}
+		case receiptRow:
+			cells := make([]string, len(b.cells))
+			for i, c := range b.cells {
+				cells[i] = replace.Replace(c)
+			}
+			style.Align = AlignLeft
+			lines = append(lines, receiptLine{kind: receiptText, text: formatRow(b.cols, cells, cols), style: style, cols: cols})
+		case receiptRule:
+			style.Align = AlignLeft
+			lines = append(lines, receiptLine{kind: receiptText, text: ruleLine(b.text, cols), style: style, cols: cols})
+		case receiptFeed:
+			for n := 0; n < b.lines; n++ {
+				lines = append(lines, receiptLine{kind: receiptText, style: Style{Width: 1, Height: 1}, cols: pr.Columns(FontA)})
+			}
+		default:
+			lines = append(lines, receiptLine{kind: b.kind, style: style, cols: cols, block: b})
+		}
+	}
+	return lines
+}
@@ -0,0 +1,58 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapColumns(t *testing.T) {
+	tests := []struct {
+		s    string
+		cols int
+		want []string
+	}{
+		{"hello world", 20, []string{"hello world"}},
+		{"hello world", 8, []string{"hello", "world"}},
+		{"abcdefghij xy", 4, []string{"abcd", "efgh", "ij", "xy"}},
+		{"two\n\nparas", 10, []string{"two", "", "paras"}},
+		{"çöğüş ışık", 5, []string{"çöğüş", "ışık"}},
+	}
+	for _, test := range tests {
+		if got := wrapColumns(test.s, test.cols); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("wrapColumns(%q, %d) = %q, want %q", test.s, test.cols, got, test.want)
+		}
+	}
+}
+
+func TestRenderUpsideDown(t *testing.T) {
+	r := NewReceipt("upside down")
+	r.UpsideDown = true
+	r.Text("first\nsecond")
+	r.SetAlign(AlignRight)
+	r.Text("total")
+	r.Feed(2)
+	r.Cut()
+
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	p.Init()
+	init := ft.String()
+	ft.Reset()
+	if err := r.Render(p); err != nil {
+		t.Fatal(err)
+	}
+	// the blocks and their lines are sent last first, the feed and cut
+	// ending the receipt still come last
+	want := init + "\x1B{\x01" +
+		"\x1Ba\x02total\n" +
+		"\x1Ba\x00second\nfirst\n" +
+		"\x1Bd\x02\x1DVA0" +
+		"\x1B{\x00"
+	if got := ft.String(); got != want {
+		t.Errorf("Render wrote\n%q, want\n%q", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// receiptCSS styles the HTML written by Receipt.HTML.
+const receiptCSS = `<style>
+.receipt{font-family:"Courier New",Courier,monospace;background:#fff;color:#000;padding:1ch;white-space:pre;overflow:hidden}
+.receipt .line{min-height:1.2em;line-height:1.2em}
+.receipt .center{text-align:center}
+.receipt .right{text-align:right}
+.receipt .reverse{background:#000;color:#fff}
+.receipt img{image-rendering:pixelated}
+.receipt .code{display:inline-block;border:1px solid #000;padding:.5em;white-space:normal;word-break:break-all}
+.receipt .cut{border:0;border-top:1px dashed #888;margin:.5em -1ch}
+.receipt .pulse{color:#888;font-style:italic}
+</style>
+`
+
+// HTML writes r to w as an HTML fragment previewing how it prints on a
+// printer described by pr, or DefaultProfile if pr is nil. The fragment
+// is a div of class "receipt" preceded by its stylesheet. Character
+// sizes and fonts are approximated, images are embedded as PNG data and
+// barcodes and QR codes are shown as boxes with their data.
+//
+// To preview a captured print job, decode it with DecodeESCPOS first.
+func (r *Receipt) HTML(w io.Writer, pr *Profile) error {
+	if pr == nil {
+		pr = DefaultProfile
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(receiptCSS)
+	fmt.Fprintf(bw, "<div class=\"receipt\" style=\"width:%dch\">\n", pr.Columns(FontA))
+	for _, l := range r.layout(pr) {
+		if err := htmlLine(bw, l, pr); err != nil {
+			return err
+		}
+	}
+	bw.WriteString("</div>\n")
+	return bw.Flush()
+}
+
+// htmlLine writes one line of a receipt preview.
+func htmlLine(w *bufio.Writer, l receiptLine, pr *Profile) error {
+	class := "line"
+	switch l.style.Align {
+	case AlignCenter:
+		class += " center"
+	case AlignRight:
+		class += " right"
+	}
+	switch l.kind {
+	case receiptText:
+		fmt.Fprintf(w, "<div class=%q>%s</div>\n", class, htmlText(l, pr))
+	case receiptImage:
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, l.block.img); err != nil {
+			return err
+		}
+		width := 100 * float64(l.block.img.Bounds().Dx()) / float64(pr.Width)
+		fmt.Fprintf(w, "<div class=%q><img style=\"width:%.2f%%\" src=\"data:image/png;base64,%s\"></div>\n",
+			class, width, base64.StdEncoding.EncodeToString(buf.Bytes()))
+	case receiptQRCode:
+		fmt.Fprintf(w, "<div class=%q><span class=\"code qrcode\">QR: %s</span></div>\n", class, html.EscapeString(l.block.text))
+	case receiptBarcode:
+		fmt.Fprintf(w, "<div class=%q><span class=\"code barcode\">%s: %s</span></div>\n",
+			class, l.block.barcode, html.EscapeString(l.block.text))
+	case receiptCut:
+		w.WriteString("<hr class=\"cut\">\n")
+	case receiptPulse:
+		w.WriteString("<div class=\"line pulse\">[cash drawer]</div>\n")
+	}
+	return nil
+}
+
+// htmlText returns the HTML of a line of text, formatted with its style.
+func htmlText(l receiptLine, pr *Profile) string {
+	s := html.EscapeString(strings.TrimRight(l.text, " "))
+	if s == "" {
+		return ""
+	}
+	var css []string
+	if l.style.Emphasize {
+		css = append(css, "font-weight:bold")
+	}
+	switch l.style.Underline {
+	case 1:
+		css = append(css, "text-decoration:underline")
+	case 2:
+		css = append(css, "text-decoration:underline;text-decoration-thickness:2px")
+	}
+	size := float64(l.style.Width)
+	if n := pr.Columns(l.style.Font); n > 0 && pr.Columns(FontA) > 0 {
+		size *= float64(pr.Columns(FontA)) / float64(n)
+	}
+	if size != 1 {
+		css = append(css, fmt.Sprintf("font-size:%.3gem", size))
+	}
+	if l.style.Height != l.style.Width {
+		css = append(css, fmt.Sprintf("display:inline-block;transform:scaleY(%.3g)", float64(l.style.Height)/float64(l.style.Width)))
+	}
+	if l.style.Height > 1 {
+		css = append(css, fmt.Sprintf("line-height:%.3gem", 1.2*float64(l.style.Height)/size))
+	}
+	class := ""
+	if l.style.Reverse {
+		class = ` class="reverse"`
+	}
+	if css == nil && class == "" {
+		return s
+	}
+	return fmt.Sprintf("<span%s style=%q>%s</span>", class, strings.Join(css, ";"), s)
+}
@@ -0,0 +1,39 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReceiptHTML(t *testing.T) {
+	r := NewReceipt("test")
+	r.SetStyle(Style{Width: 2, Height: 2, Emphasize: true, Align: AlignCenter})
+	r.Text("<Shop>")
+	r.SetStyle(Style{Width: 1, Height: 1})
+	r.TwoColumns("Tea", "1.50")
+	r.Rule('-')
+	r.Barcode("123", BarcodeCODE39)
+	r.Cut()
+	var buf bytes.Buffer
+	if err := r.HTML(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	for _, want := range []string{
+		`<div class="receipt" style="width:48ch">`,
+		`<div class="line center"><span style="font-weight:bold;font-size:2em;line-height:1.2em">&lt;Shop&gt;</span></div>`,
+		"Tea" + strings.Repeat(" ", 41) + "1.50",
+		strings.Repeat("-", 48),
+		`<span class="code barcode">CODE39: 123</span>`,
+		`<hr class="cut">`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("HTML does not contain %q:\n%s", want, s)
+		}
+	}
+}
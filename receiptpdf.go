@@ -0,0 +1,187 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	// receiptDPI is the resolution of receipt printers, 8 dots per mm.
+	receiptDPI = 203
+
+	// receiptMargin is the margin around receipts in PDF documents, in
+	// points.
+	receiptMargin = 8
+)
+
+// PDF writes r to w as a PDF document laid out like it prints on a
+// printer described by pr, or DefaultProfile if pr is nil, for digital
+// receipts. Pages are as wide as the paper and as long as their content,
+// and every cut starts a new page. Text is set in Courier sized so that a
+// line of FontA characters spans the paper; barcodes and QR codes are
+// shown as boxes with their data.
+func (r *Receipt) PDF(w io.Writer, pr *Profile) error {
+	if pr == nil {
+		pr = DefaultProfile
+	}
+	cols := pr.Columns(FontA)
+	if cols == 0 || pr.Width <= 0 {
+		return fmt.Errorf("printer: profile %q has no width or FontA", pr.Name)
+	}
+	paper := float64(pr.Width) * 72 / receiptDPI
+	rp := &receiptPDF{
+		pw:    newPDFWriter(),
+		width: paper + 2*receiptMargin,
+		charW: paper / float64(cols),
+	}
+	rp.size = rp.charW / 0.6 // Courier characters are 0.6 em wide
+	rp.fonts = fmt.Sprintf("/F1 %d 0 R /F2 %d 0 R",
+		rp.pw.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>"),
+		rp.pw.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>"))
+
+	var page []receiptLine
+	for _, l := range r.layout(pr) {
+		if l.kind == receiptCut {
+			if page != nil {
+				rp.page(page)
+			}
+			page = nil
+			continue
+		}
+		page = append(page, l)
+	}
+	if page != nil || len(rp.pw.pages) == 0 {
+		rp.page(page)
+	}
+	_, err := rp.pw.WriteTo(w)
+	return err
+}
+
+// receiptPDF lays out the pages of a receipt in a PDF document.
+type receiptPDF struct {
+	pw    *pdfWriter
+	fonts string // font resources
+	width float64
+	charW float64 // width of a FontA character
+	size  float64 // font size of FontA
+}
+
+func (rp *receiptPDF) lineHeight(l receiptLine) float64 {
+	switch l.kind {
+	case receiptText:
+		return 1.2 * rp.size * float64(l.style.Height)
+	case receiptImage:
+		return float64(l.block.img.Bounds().Dy()) * 72 / receiptDPI
+	case receiptQRCode, receiptBarcode:
+		return 2.4 * rp.size
+	}
+	return 0
+}
+
+// page adds a page showing lines.
+func (rp *receiptPDF) page(lines []receiptLine) {
+	height := 2.0 * receiptMargin
+	for _, l := range lines {
+		height += rp.lineHeight(l)
+	}
+	var c, images bytes.Buffer
+	nimages := 0
+	top := height - receiptMargin
+	for _, l := range lines {
+		h := rp.lineHeight(l)
+		switch l.kind {
+		case receiptText:
+			rp.text(&c, l, top, h)
+		case receiptImage:
+			b := l.block.img.Bounds()
+			w := float64(b.Dx()) * 72 / receiptDPI
+			name := fmt.Sprintf("Im%d", nimages)
+			nimages++
+			fmt.Fprintf(&images, "/%s %d 0 R ", name, rp.pw.image(l.block.img))
+			fmt.Fprintf(&c, "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", w, h, rp.alignX(w, l.style.Align), top-h, name)
+		case receiptQRCode:
+			rp.box(&c, "QR: "+l.block.text, l.style.Align, top, h)
+		case receiptBarcode:
+			rp.box(&c, l.block.barcode.String()+": "+l.block.text, l.style.Align, top, h)
+		}
+		top -= h
+	}
+	rp.pw.page(rp.width, height, fmt.Sprintf("<< /Font << %s >> /XObject << %s>> >>", rp.fonts, images.String()), c.Bytes())
+}
+
+// alignX returns the x position of an item w points wide aligned on the
+// paper.
+func (rp *receiptPDF) alignX(w float64, align Align) float64 {
+	paper := rp.width - 2*receiptMargin
+	switch align {
+	case AlignCenter:
+		return receiptMargin + (paper-w)/2
+	case AlignRight:
+		return receiptMargin + paper - w
+	}
+	return receiptMargin
+}
+
+// text draws a line of text below top in a line h points high.
+func (rp *receiptPDF) text(c *bytes.Buffer, l receiptLine, top, h float64) {
+	s := textLine(l)
+	text := strings.TrimLeft(s, " ")
+	if text == "" {
+		return
+	}
+	width, height := float64(l.style.Width), float64(l.style.Height)
+	x := receiptMargin + float64(len(s)-len(text))*rp.charW
+	w := float64(utf8.RuneCountInString(text)) * rp.charW * width
+	size := rp.size * height
+	baseline := top - h + (h-size)/2 + 0.2*size
+	font := 1
+	if l.style.Emphasize {
+		font = 2
+	}
+	if l.style.Reverse {
+		fmt.Fprintf(c, "0 g %.2f %.2f %.2f %.2f re f 1 g 1 G\n", x, top-h, w, h)
+	}
+	fmt.Fprintf(c, "BT /F%d %.2f Tf %.2f Tz %.2f %.2f Td (%s) Tj ET\n",
+		font, size, 100*width/height, x, baseline, pdfString(text))
+	if l.style.Underline > 0 {
+		fmt.Fprintf(c, "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+			0.5*float64(l.style.Underline), x, baseline-1.5, x+w, baseline-1.5)
+	}
+	c.WriteString("0 g 0 G\n")
+}
+
+// box draws label in a box standing for a barcode or QR code.
+func (rp *receiptPDF) box(c *bytes.Buffer, label string, align Align, top, h float64) {
+	w := float64(utf8.RuneCountInString(label)+2) * rp.charW
+	x := rp.alignX(w, align)
+	fmt.Fprintf(c, "0.5 w %.2f %.2f %.2f %.2f re S\n", x, top-h+0.2*rp.size, w, h-0.4*rp.size)
+	fmt.Fprintf(c, "BT /F1 %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		rp.size, x+rp.charW, top-h+(h-rp.size)/2+0.2*rp.size, pdfString(label))
+}
+
+// pdfString returns s encoded for a PDF literal string in a font with
+// WinAnsiEncoding. Characters the encoding lacks are replaced with '?'.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		c, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			c = '?'
+		}
+		switch c {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestReceiptPDF(t *testing.T) {
+	r := NewReceipt("test")
+	r.SetStyle(Style{Width: 2, Height: 2, Emphasize: true, Align: AlignCenter})
+	r.Text("Shop")
+	r.SetStyle(Style{Width: 1, Height: 1, Underline: 1})
+	r.TwoColumns("Tea", "1.50")
+	r.Image(image.NewGray(image.Rect(0, 0, 64, 32)))
+	r.Cut()
+	r.Text("Copy")
+	r.QRCode("x", 3, QRCodeErrorCorrectionLevelL)
+	r.Cut()
+	var buf bytes.Buffer
+	if err := r.PDF(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	if !strings.HasPrefix(s, "%PDF-") || !strings.HasSuffix(s, "%%EOF\n") {
+		t.Errorf("not a PDF document")
+	}
+	if n := strings.Count(s, "/Type /Page "); n != 2 {
+		t.Errorf("got %d pages, want 2", n)
+	}
+	for _, want := range []string{"/BaseFont /Courier ", "/BaseFont /Courier-Bold ", "/Subtype /Image", "/Count 2"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	if got, want := pdfString(`a(b)\ €1 ş`), "a\\(b\\)\\\\ \x801 ?"; got != want {
+		t.Errorf("pdfString = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// PlainText writes r to w as monospaced plain text laid out like it
+// prints on a printer described by pr, or DefaultProfile if pr is nil,
+// for email or log copies. Lines are as wide as lines printed in FontA.
+// Styles are dropped but text keeps the position it has on paper;
+// images, barcodes and QR codes are replaced with a short placeholder.
+func (r *Receipt) PlainText(w io.Writer, pr *Profile) error {
+	if pr == nil {
+		pr = DefaultProfile
+	}
+	width := pr.Columns(FontA)
+	bw := bufio.NewWriter(w)
+	for _, l := range r.layout(pr) {
+		var s string
+		switch l.kind {
+		case receiptText:
+			s = textLine(l)
+		case receiptImage:
+			s = alignText("[image]", width, l.style.Align)
+		case receiptQRCode:
+			s = alignText("[QR: "+l.block.text+"]", width, l.style.Align)
+		case receiptBarcode:
+			s = alignText("["+l.block.barcode.String()+": "+l.block.text+"]", width, l.style.Align)
+		case receiptCut:
+			s = ruleLine("- ", width)
+		default:
+			continue
+		}
+		bw.WriteString(strings.TrimRight(s, " ") + "\n")
+	}
+	return bw.Flush()
+}
+
+// textLine returns a line of text positioned as it prints: characters
+// wider than normal take up more than one column of the paper, so the
+// indentation of aligned text is scaled with them.
+func textLine(l receiptLine) string {
+	n := utf8.RuneCountInString(l.text)
+	pad := 0
+	switch l.style.Align {
+	case AlignCenter:
+		pad = (l.cols - n) / 2
+	case AlignRight:
+		pad = l.cols - n
+	}
+	if pad < 0 {
+		pad = 0
+	}
+	return strings.Repeat(" ", pad*int(l.style.Width)) + l.text
+}
+
+// alignText aligns s in a line of width characters.
+func alignText(s string, width int, align Align) string {
+	return textLine(receiptLine{text: s, cols: width, style: Style{Width: 1, Align: align}})
+}
@@ -0,0 +1,42 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReceiptPlainText(t *testing.T) {
+	pr := &Profile{Width: 160, Fonts: map[Font]int{FontA: 20}}
+	r := NewReceipt("test")
+	r.SetStyle(Style{Width: 2, Height: 2, Align: AlignCenter})
+	r.Text("Shop")
+	r.SetStyle(Style{Width: 1, Height: 1})
+	r.Text("Thank you for your visit")
+	r.TwoColumns("Tea", "1.50")
+	r.Divider(DividerDouble)
+	r.Feed(1)
+	r.SetAlign(AlignRight)
+	r.QRCode("x", 3, QRCodeErrorCorrectionLevelL)
+	r.Cut()
+	r.Pulse()
+	var buf bytes.Buffer
+	if err := r.PlainText(&buf, pr); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"      Shop\n" +
+		"Thank you for your\n" +
+		"visit\n" +
+		"Tea             1.50\n" +
+		"====================\n" +
+		"\n" +
+		"             [QR: x]\n" +
+		"- - - - - - - - - -\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
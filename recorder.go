@@ -0,0 +1,155 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder saves the data sent to printers, one file per document, so
+// that print jobs can be inspected, decoded with DecodeESCPOS or sent
+// again with Replay. Set Printer.Recorder to record a printer. It is safe
+// for concurrent use.
+type Recorder struct {
+	// Dir is the directory the captures are saved in, created if needed.
+	Dir string
+	// MaxSize, if set, is the total size of the captures kept in Dir;
+	// the oldest captures are removed beyond it.
+	MaxSize int64
+	// MaxFiles, if set, is the number of captures kept in Dir.
+	MaxFiles int
+	// Redact, if set, is applied to the text of the captures, for
+	// instance RedactPII; see RedactESCPOS.
+	Redact Redactor
+
+	mu   sync.Mutex
+	last time.Time // of the last capture, to keep the names ordered
+}
+
+// NewRecorder returns a Recorder saving the captures in dir.
+func NewRecorder(dir string) *Recorder {
+	return &Recorder{Dir: dir}
+}
+
+// captureExt is the file name extension of the captures.
+const captureExt = ".pj"
+
+// Save saves data, the document named document sent to printer, and
+// returns the path of the capture. Captures are named after the time they
+// are saved, the printer and the document, such as
+// 20210601-120000.000-Kitchen-Order 12.pj.
+func (r *Recorder) Save(printer, document string, data []byte) (string, error) {
+	if r.Redact != nil {
+		// the redacted text is kept even if the rest fails to decode
+		data, _ = RedactESCPOS(data, r.Redact)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return "", err
+	}
+	t := time.Now().Truncate(time.Millisecond)
+	if !t.After(r.last) {
+		t = r.last.Add(time.Millisecond)
+	}
+	r.last = t
+	name := t.Format("20060102-150405.000") + "-" + fileName(printer) + "-" + fileName(document)
+	var f *os.File
+	var err error
+	for i := 0; ; i++ {
+		path := filepath.Join(r.Dir, name+captureExt)
+		if i > 0 {
+			path = filepath.Join(r.Dir, fmt.Sprintf("%s-%d%s", name, i, captureExt))
+		}
+		f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if !os.IsExist(err) {
+			break
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), r.rotate()
+}
+
+// fileName returns s without the characters file names cannot contain.
+func fileName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > 64 {
+		s = s[:64]
+	}
+	return strings.TrimSpace(s)
+}
+
+// Captures returns the paths of the captures in r.Dir, oldest first.
+func (r *Recorder) Captures() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(r.Dir, "*"+captureExt))
+	if err != nil {
+		return nil, err
+	}
+	// names start with the time they were saved
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// rotate removes the oldest captures beyond MaxSize and MaxFiles.
+func (r *Recorder) rotate() error {
+	if r.MaxSize <= 0 && r.MaxFiles <= 0 {
+		return nil
+	}
+	paths, err := r.Captures()
+	if err != nil {
+		return err
+	}
+	sizes := make([]int64, len(paths))
+	var total int64
+	for i, path := range paths {
+		if fi, err := os.Stat(path); err == nil {
+			sizes[i] = fi.Size()
+			total += sizes[i]
+		}
+	}
+	// the newest capture is always kept
+	for i := 0; i < len(paths)-1; i++ {
+		if (r.MaxSize <= 0 || total <= r.MaxSize) && (r.MaxFiles <= 0 || len(paths)-i <= r.MaxFiles) {
+			break
+		}
+		if err := os.Remove(paths[i]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= sizes[i]
+	}
+	return nil
+}
+
+// Replay sends the capture at path to p again, unmodified, as a document
+// named after the capture.
+func Replay(path string, p *Printer) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return p.PrintRaw(filepath.Base(path), data)
+}
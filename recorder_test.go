@@ -0,0 +1,101 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	r := NewRecorder(filepath.Join(dir, "captures"))
+	r.MaxFiles = 2
+	r.Redact = RedactPII
+	var paths []string
+	for _, doc := range []string{"a", "b/c", "d"} {
+		path, err := r.Save("Kitchen", doc, []byte("Card 4111111111111111\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	if base := filepath.Base(paths[1]); !strings.HasSuffix(base, "-Kitchen-b_c.pj") {
+		t.Errorf("capture named %q", base)
+	}
+	captures, err := r.Captures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(captures) != 2 || captures[0] != paths[1] || captures[1] != paths[2] {
+		t.Errorf("captures %q, want the last 2 of %q", captures, paths)
+	}
+	b, err := ioutil.ReadFile(paths[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "Card ************1111\n"; got != want {
+		t.Errorf("capture %q, want %q", got, want)
+	}
+}
+
+func TestRecorderMaxSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	r := NewRecorder(dir)
+	r.MaxSize = 25
+	for i := 0; i < 4; i++ {
+		if _, err := r.Save("p", "doc", make([]byte, 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	captures, _ := r.Captures()
+	if len(captures) != 2 {
+		t.Errorf("%d captures kept, want 2", len(captures))
+	}
+	// a capture larger than MaxSize is kept until the next one
+	if _, err := r.Save("p", "doc", make([]byte, 100)); err != nil {
+		t.Fatal(err)
+	}
+	if captures, _ = r.Captures(); len(captures) != 1 {
+		t.Errorf("%d captures kept, want 1", len(captures))
+	}
+}
+
+func TestReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	p.Recorder = NewRecorder(dir)
+	p.StartRawDocument("receipt")
+	p.Write([]byte("hello\n"))
+	if err := p.EndDocument(); err != nil {
+		t.Fatal(err)
+	}
+	captures, err := p.Recorder.Captures()
+	if err != nil || len(captures) != 1 {
+		t.Fatalf("captures %q, %v", captures, err)
+	}
+	if err := Replay(captures[0], p); err != nil {
+		t.Fatal(err)
+	}
+	if got := ft.String(); got != "hello\nhello\n" {
+		t.Errorf("wrote %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "errors"
+
+// StaleHandleError is returned when the spooler handle of a printer
+// became invalid, as it does when the spooler restarts, and the printer
+// could not be reopened or the document in progress could not be
+// started again on the new handle.
+type StaleHandleError struct {
+	Op  string
+	Err error // why recovery failed
+}
+
+func (e *StaleHandleError) Error() string {
+	return "printer: " + e.Op + ": spooler handle lost: " + e.Err.Error()
+}
+
+func (e *StaleHandleError) Unwrap() error {
+	return e.Err
+}
+
+// errNoReplay tells that a document cannot be replayed on a new handle.
+var errNoReplay = errors.New("document data not kept, see Printer.Replay")
+
+// replayData returns the data written so far to the current document, if
+// it was kept.
+func (p *Printer) replayData() ([]byte, bool) {
+	switch {
+	case p.recorder() != nil || p.Replay:
+		return p.data, true
+	case p.copies > 1:
+		return p.doc, true
+	}
+	return nil, false
+}
+
+// retry calls f, and calls it again if it failed because the spooler
+// handle of p was stale and recoverHandle recovered it.
+func (p *Printer) retry(op string, f func() error) error {
+	err := f()
+	if err == nil {
+		return nil
+	}
+	if err := p.recoverHandle(op, err); err != nil {
+		return err
+	}
+	return f()
+}
+
+// recoverHandle reopens p if err tells that its spooler handle is stale,
+// then starts the document in progress again and replays the data written
+// to it, including the data buffered by WriteBuffer. Since the spooler
+// knows no pages in RAW data, the data is replayed in the current page.
+// It returns nil if the failed call can be retried, err if err is not
+// about a stale handle and a *StaleHandleError if recovery failed.
+func (p *Printer) recoverHandle(op string, err error) error {
+	if p.h == 0 || !isStaleHandle(err) {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.reopen(); err != nil {
+		return &StaleHandleError{Op: op, Err: err}
+	}
+	if !p.inDoc {
+		return nil
+	}
+	data, ok := p.replayData()
+	if !ok {
+		p.inDoc, p.inPage = false, false
+		return &StaleHandleError{Op: op, Err: errNoReplay}
+	}
+	inPage := p.inPage
+	p.wbuf = p.wbuf[:0]
+	p.acked = 0
+	if err := p.startSpoolDocument(p.docName, p.datatype); err != nil {
+		return &StaleHandleError{Op: op, Err: err}
+	}
+	if inPage {
+		if err := p.startSpoolPage(); err != nil {
+			return &StaleHandleError{Op: op, Err: err}
+		}
+	}
+	if len(data) > 0 {
+		if _, err := p.transmit(data); err != nil {
+			return &StaleHandleError{Op: op, Err: err}
+		}
+	}
+	return nil
+}
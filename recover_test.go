@@ -0,0 +1,43 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStaleHandleError(t *testing.T) {
+	err := error(&StaleHandleError{Op: "Write", Err: errNoReplay})
+	if !errors.Is(err, errNoReplay) {
+		t.Errorf("%v does not wrap errNoReplay", err)
+	}
+	var se *StaleHandleError
+	if !errors.As(err, &se) || se.Op != "Write" {
+		t.Errorf("errors.As(%v) = %+v", err, se)
+	}
+}
+
+func TestReplayData(t *testing.T) {
+	p := NewPrinter("fake", new(fakeTransport))
+	p.StartDocument("doc", "RAW")
+	p.WriteString("hello")
+	if _, ok := p.replayData(); ok {
+		t.Error("data kept without Replay")
+	}
+
+	p.Replay = true
+	p.StartDocument("doc", "RAW")
+	p.WriteString("hello")
+	if data, ok := p.replayData(); !ok || string(data) != "hello" {
+		t.Errorf("replayData = %q, %v, want %q", data, ok, "hello")
+	}
+
+	// printers created with NewPrinter have no handle to recover
+	err := errors.New("port gone")
+	if got := p.recoverHandle("Write", err); got != err {
+		t.Errorf("recoverHandle = %v, want %v", got, err)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// isStaleHandle reports whether err tells that a spooler handle is no
+// longer valid.
+func isStaleHandle(err error) bool {
+	return errors.Is(err, windows.ERROR_INVALID_HANDLE)
+}
+
+// reopen replaces the spooler handle of p by a new one opened with the
+// same access, keeping the deadlines of its transport and the DEVMODE
+// set on it. p.mu must be held.
+func (p *Printer) reopen() error {
+	u, err := newUTF16(p.name)
+	if err != nil {
+		return err
+	}
+	defer u.free()
+	var d *PRINTER_DEFAULTS
+	if p.access != 0 {
+		d = &PRINTER_DEFAULTS{DesiredAccess: p.access}
+	}
+	var h syscall.Handle
+	if err := OpenPrinter(u.ptr(), &h, d); err != nil {
+		return err
+	}
+	p.closeJobNotify()
+	ClosePrinter(p.h)
+	t := &spoolTransport{h: h}
+	if old, ok := p.t.(*spoolTransport); ok {
+		t.write, t.read = old.deadlines()
+	}
+	p.h, p.t = h, t
+	if p.dm != nil {
+		return ResetPrinter(h, &PRINTER_DEFAULTS{DevMode: (*DEVMODE)(unsafe.Pointer(&p.dm[0]))})
+	}
+	return nil
+}
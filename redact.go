@@ -0,0 +1,224 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// A Redactor masks personal data, such as card numbers, in text before it
+// leaves the program in a capture or a log.
+type Redactor func(s string) string
+
+// Redactors returns a Redactor applying rs in turn.
+func Redactors(rs ...Redactor) Redactor {
+	return func(s string) string {
+		for _, r := range rs {
+			s = r(s)
+		}
+		return s
+	}
+}
+
+var (
+	// 13 to 19 digits, possibly grouped with spaces or dashes
+	panPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	// 9 to 15 digits, possibly grouped, a leading + or a parenthesized
+	// area code
+	phonePattern = regexp.MustCompile(`(?:\+|\b)\(?\d(?:[ ()./-]{0,2}\d){8,14}\b`)
+)
+
+// RedactCards masks the payment card numbers in s, keeping their last 4
+// digits as printed on receipts, such as "**** **** **** 1234". Only
+// digit sequences passing the Luhn check are masked.
+func RedactCards(s string) string {
+	return panPattern.ReplaceAllStringFunc(s, func(pan string) string {
+		if !luhn(pan) {
+			return pan
+		}
+		return maskDigits(pan, 4)
+	})
+}
+
+// RedactPhones masks the phone numbers in s, keeping their last 2 digits.
+func RedactPhones(s string) string {
+	return phonePattern.ReplaceAllStringFunc(s, func(phone string) string {
+		return maskDigits(phone, 2)
+	})
+}
+
+// RedactPII masks card numbers and phone numbers, see RedactCards and
+// RedactPhones.
+var RedactPII Redactor = Redactors(RedactCards, RedactPhones)
+
+// maskDigits replaces the digits of s but the last keep with '*', leaving
+// the separators so the length and layout of the text are unchanged.
+func maskDigits(s string, keep int) string {
+	b := []byte(s)
+	n := 0
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '0' || b[i] > '9' {
+			continue
+		}
+		if n++; n > keep {
+			b[i] = '*'
+		}
+	}
+	return string(b)
+}
+
+// luhn reports whether the digits of s pass the Luhn check.
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// Redact applies f to the text of r: text, rows, QR codes and barcodes.
+// The cells of a row are redacted as a single line.
+func (r *Receipt) Redact(f Redactor) {
+	for i := range r.blocks {
+		b := &r.blocks[i]
+		switch b.kind {
+		case receiptText, receiptQRCode, receiptBarcode:
+			b.text = f(b.text)
+		case receiptRow:
+			b.cells = redactParts(b.cells, f)
+		}
+	}
+}
+
+// redactParts applies f to the text made of parts, such as a line printed
+// in several styles, so that personal data split across the parts is
+// found, and returns the redacted parts. If f changes the length of the
+// text, the parts cannot be told apart anymore and each is redacted on
+// its own instead.
+func redactParts(parts []string, f Redactor) []string {
+	text := strings.Join(parts, "")
+	redacted := []rune(f(text))
+	out := make([]string, len(parts))
+	if len(redacted) != utf8.RuneCountInString(text) {
+		for i, s := range parts {
+			out[i] = f(s)
+		}
+		return out
+	}
+	for i, s := range parts {
+		n := utf8.RuneCountInString(s)
+		out[i], redacted = string(redacted[:n]), redacted[n:]
+	}
+	return out
+}
+
+// RedactESCPOS returns a copy of the ESC/POS commands in data with f
+// applied to the text they print and to the data of QR codes and
+// barcodes, such as a captured print job to be shared. The text of a
+// line is redacted as a whole, even if it is printed in several styles.
+// Text is decoded and encoded in the code page selected with ESC t;
+// characters f introduces that the code page lacks become '?'. Other
+// command parameters are copied unchanged. If data cannot be decoded, the
+// text found up to the error is redacted and the rest is dropped.
+func RedactESCPOS(data []byte, f Redactor) ([]byte, error) {
+	d := decoder{data: data, r: NewReceipt(""), cp: charmap.CodePage437}
+	d.reset()
+	err := d.decode()
+	end := len(data)
+	if err != nil {
+		end = d.cmd
+	}
+
+	edits := make([]paramSpan, 0, len(d.spans)+len(d.params))
+	for i := 0; i < len(d.spans); {
+		// the spans of a line
+		j := i + 1
+		for j < len(d.spans) && d.spans[j].line == d.spans[i].line {
+			j++
+		}
+		parts := make([]string, j-i)
+		for k, s := range d.spans[i:j] {
+			text, _ := s.cp.NewDecoder().Bytes(data[s.start:s.end])
+			parts[k] = string(text)
+		}
+		for k, text := range redactParts(parts, f) {
+			s := d.spans[i+k]
+			edits = append(edits, paramSpan{start: s.start, end: s.end, lenAt: -1, repl: encodeText(s.cp, text)})
+		}
+		i = j
+	}
+	for _, p := range d.params {
+		p.repl = []byte(f(string(data[p.start:p.end])))
+		edits = append(edits, p)
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	out := make([]byte, 0, len(data))
+	pos := 0
+	for _, e := range edits {
+		out = append(out, data[pos:e.start]...)
+		if e.lenAt >= 0 {
+			// update the length preceding the data
+			max := 1<<(8*uint(e.lenSize)) - 1 - e.lenExtra
+			if len(e.repl) > max {
+				e.repl = e.repl[:max]
+			}
+			n := len(e.repl) + e.lenExtra
+			at := len(out) - (e.start - e.lenAt)
+			for k := 0; k < e.lenSize; k++ {
+				out[at+k] = byte(n >> (8 * uint(k)))
+			}
+		}
+		out = append(out, e.repl...)
+		pos = e.end
+	}
+	return append(out, data[pos:end]...), err
+}
+
+// textSpan is a run of printed text in ESC/POS data.
+type textSpan struct {
+	start, end int
+	cp         *charmap.Charmap
+	line       int // the spans of a line share it
+}
+
+// paramSpan is the data of a QR code or a barcode in ESC/POS data.
+// RedactESCPOS also records the text it replaces as paramSpans with repl
+// set.
+type paramSpan struct {
+	start, end int
+	// lenAt is the offset of the little-endian length of lenSize bytes
+	// preceding the data, which counts lenExtra more bytes, or -1 if the
+	// data has no length, such as NUL terminated data.
+	lenAt, lenSize, lenExtra int
+
+	repl []byte
+}
+
+// addText records the printable byte at d.pos-1 in the text spans.
+func (d *decoder) addText() {
+	i := d.pos - 1
+	if n := len(d.spans); n > 0 && d.spans[n-1].end == i && d.spans[n-1].cp == d.cp {
+		d.spans[n-1].end++
+		return
+	}
+	d.spans = append(d.spans, textSpan{i, i + 1, d.cp, d.lineNo})
+}
@@ -0,0 +1,96 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactPII(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Card: 4111 1111 1111 1111", "Card: **** **** **** 1111"},
+		{"PAN 4111111111111111 ok", "PAN ************1111 ok"},
+		// fails the Luhn check, so not a card, but long enough for a phone
+		{"Order 1234567890123", "Order ***********23"},
+		{"Tel: +90 (212) 555-12-34", "Tel: +** (***) ***-**-34"},
+		{"Total 12.50 EUR", "Total 12.50 EUR"},
+		{"Table 12, 3 guests", "Table 12, 3 guests"},
+	}
+	for _, tt := range tests {
+		if got := RedactPII(tt.in); got != tt.want {
+			t.Errorf("RedactPII(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactESCPOS(t *testing.T) {
+	data := []byte("\x1b@\x1bE\x01Card 4111 1111 1111 1111\n\x1bE\x00\x1dv0\x00\x01\x00\x02\x00\x34\x31Tel 0212 555 12 34\n\x1dV\x00")
+	want := []byte("\x1b@\x1bE\x01Card **** **** **** 1111\n\x1bE\x00\x1dv0\x00\x01\x00\x02\x00\x34\x31Tel **** *** ** 34\n\x1dV\x00")
+	got, err := RedactESCPOS(data, RedactPII)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	// truncated raster image: text before it is kept, redacted
+	got, err = RedactESCPOS([]byte("4111111111111111\n\x1dv0\x00\x10"), RedactPII)
+	if err == nil {
+		t.Error("no error for truncated data")
+	}
+	if want := "************1111\n"; string(got) != want {
+		t.Errorf("truncated: got %q, want %q", got, want)
+	}
+}
+
+func TestRedactESCPOSSplit(t *testing.T) {
+	// a card number printed in two styles, a barcode and a QR code
+	data := []byte("Card 4111 \x1bE\x011111 1111\x1bE\x00 1111\n" +
+		"\x1dkE\x104111111111111111" +
+		"\x1d(k\x13\x001P04111111111111111")
+	want := []byte("Card **** \x1bE\x01**** ****\x1bE\x00 1111\n" +
+		"\x1dkE\x10************1111" +
+		"\x1d(k\x13\x001P0************1111")
+	got, err := RedactESCPOS(data, RedactPII)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	// the lengths of barcodes and QR codes follow the redacted data
+	got, err = RedactESCPOS([]byte("\x1dkI\x04{Bab\x1d(k\x05\x001P0ab"), func(s string) string {
+		return strings.Replace(s, "ab", "xyz", -1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\x1dkI\x05{Bxyz\x1d(k\x06\x001P0xyz"; string(got) != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestReceiptRedact(t *testing.T) {
+	r := NewReceipt("")
+	r.Text("4111 1111 1111 1111")
+	r.TwoColumns("Phone", "+1 555 123 4567")
+	r.Row([]Column{{}, {}}, "Card 4111 1111", "1111 1111")
+	r.Redact(RedactPII)
+	if got := r.blocks[0].text; got != "**** **** **** 1111" {
+		t.Errorf("text %q", got)
+	}
+	if got := r.blocks[1].cells[1]; got != "+* *** *** **67" {
+		t.Errorf("cell %q", got)
+	}
+	if got := r.blocks[2].cells; got[0] != "Card **** ****" || got[1] != "**** 1111" {
+		t.Errorf("split card cells %q", got)
+	}
+}
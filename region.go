@@ -0,0 +1,63 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"image"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// FitWidth returns img scaled to print on paper width dots wide. Images
+// wider than the paper are scaled down smoothly to fit. Images narrower
+// than half the paper, such as small screen captures, are enlarged by the
+// largest whole factor that fits, which keeps their pixels sharp. Other
+// images are returned unchanged.
+func FitWidth(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || width <= 0 {
+		return img
+	}
+	var scaler xdraw.Interpolator
+	switch {
+	case w > width:
+		h = (h*width + w/2) / w
+		w = width
+		scaler = xdraw.CatmullRom
+	case 2*w <= width:
+		n := width / w
+		w, h = w*n, h*n
+		scaler = xdraw.NearestNeighbor
+	default:
+		return img
+	}
+	if h == 0 {
+		h = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	scaler.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	return dst
+}
+
+// print the region r of img, such as a screen capture or a rendered
+// chart, scaled to the width of the paper with FitWidth and dithered
+func (p *Printer) PrintRegion(img image.Image, r image.Rectangle) error {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return fmt.Errorf("printer: region %v is outside of the image", r)
+	}
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		img = s.SubImage(r)
+	} else {
+		m := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+		xdraw.Copy(m, image.Point{}, img, r, xdraw.Src, nil)
+		img = m
+	}
+	return p.PrintImage(Dither(FitWidth(img, p.profile().Width)))
+}
@@ -0,0 +1,29 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"image"
+	"testing"
+)
+
+func TestFitWidth(t *testing.T) {
+	tests := []struct {
+		w, h, width int
+		want        image.Point
+	}{
+		{1152, 200, 576, image.Pt(576, 100)},
+		{100, 50, 576, image.Pt(500, 250)},
+		{400, 100, 576, image.Pt(400, 100)},
+		{576, 10, 576, image.Pt(576, 10)},
+		{5000, 1, 576, image.Pt(576, 1)},
+	}
+	for _, test := range tests {
+		img := image.NewGray(image.Rect(10, 10, 10+test.w, 10+test.h))
+		if got := FitWidth(img, test.width).Bounds().Size(); got != test.want {
+			t.Errorf("FitWidth(%dx%d, %d) = %v, want %v", test.w, test.h, test.width, got, test.want)
+		}
+	}
+}
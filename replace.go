@@ -0,0 +1,90 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strings"
+	"sync"
+)
+
+// Replacements is an ordered list of text replacements, such as entities
+// to decode, emoji to spell out or brand names to abbreviate. It is safe
+// for concurrent use.
+type Replacements struct {
+	mu    sync.RWMutex
+	pairs []string // old, new, ...
+}
+
+// NewReplacements returns replacements of each old string with the new
+// string following it, applied in order.
+func NewReplacements(oldnew ...string) *Replacements {
+	if len(oldnew)%2 == 1 {
+		panic("printer: NewReplacements with odd argument count")
+	}
+	return &Replacements{pairs: append([]string(nil), oldnew...)}
+}
+
+// DefaultReplacements decodes the XML entities and character references
+// of text nodes.
+var DefaultReplacements = NewReplacements(
+	// horizontal tab
+	"&#9;", "\x09",
+	"&#x9;", "\x09",
+
+	// linefeed
+	"&#10;", "\n",
+	"&#xA;", "\n",
+
+	// xml stuff
+	"&apos;", "'",
+	"&quot;", `"`,
+	"&gt;", ">",
+	"&lt;", "<",
+
+	// ampersand must be last to avoid double decoding
+	"&amp;", "&",
+)
+
+// Add appends the replacement of old with new, applied after the
+// existing ones.
+func (r *Replacements) Add(old, new string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pairs = append(r.pairs, old, new)
+}
+
+// Prepend inserts the replacement of old with new, applied before the
+// existing ones.
+func (r *Replacements) Prepend(old, new string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pairs = append([]string{old, new}, r.pairs...)
+}
+
+// Clone returns a copy of r, to be extended without changing r.
+func (r *Replacements) Clone() *Replacements {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return NewReplacements(r.pairs...)
+}
+
+// Replace applies the replacements to s in order, each to the result of
+// the previous one.
+func (r *Replacements) Replace(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := 0; i < len(r.pairs); i += 2 {
+		s = strings.Replace(s, r.pairs[i], r.pairs[i+1], -1)
+	}
+	return s
+}
+
+// replacements returns the replacements of p.
+func (p *Printer) replacements() *Replacements {
+	if p.Replacements != nil {
+		return p.Replacements
+	}
+	return DefaultReplacements
+}
@@ -0,0 +1,22 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestReplacements(t *testing.T) {
+	if got, want := DefaultReplacements.Replace("a&amp;lt;b &lt;c&gt;&#10;"), "a&lt;b <c>\n"; got != want {
+		t.Errorf("DefaultReplacements.Replace = %q, want %q", got, want)
+	}
+	r := DefaultReplacements.Clone()
+	r.Prepend("&euro;", "EUR")
+	r.Add("Limited", "Ltd")
+	if got, want := r.Replace("ACME Limited &euro;5 &amp; more"), "ACME Ltd EUR5 & more"; got != want {
+		t.Errorf("Replace = %q, want %q", got, want)
+	}
+	if got := DefaultReplacements.Replace("&euro;"); got != "&euro;" {
+		t.Errorf("Clone changed DefaultReplacements: %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Exists reports whether a printer named name, ignoring case, is
+// installed.
+func Exists(name string) (bool, error) {
+	names, err := ReadNames()
+	if err != nil {
+		return false, err
+	}
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Resolve returns the name of the installed printer best matching name;
+// see MatchNames. It returns an *AmbiguousNameError if several printers
+// match equally well, so names drifting between driver versions, such as
+// "EPSON TM-T20" and "EPSON TM-T20III Receipt", still resolve.
+func Resolve(name string) (string, error) {
+	names, err := ReadNames()
+	if err != nil {
+		return "", err
+	}
+	candidates := MatchNames(name, names)
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("printer: no printer matches %q", name)
+	case 1:
+		return candidates[0], nil
+	}
+	return "", &AmbiguousNameError{Name: name, Candidates: candidates}
+}
+
+// AmbiguousNameError is returned by Resolve when several printers match.
+type AmbiguousNameError struct {
+	Name       string
+	Candidates []string
+}
+
+func (e *AmbiguousNameError) Error() string {
+	return fmt.Sprintf("printer: %q matches several printers: %s", e.Name, strings.Join(e.Candidates, ", "))
+}
+
+// MatchNames returns the names matching name best, trying in turn an exact
+// match, a match ignoring case, names starting with name and names
+// containing name, the last two ignoring case. Only the matches of the
+// first successful rule are returned.
+func MatchNames(name string, names []string) []string {
+	if name == "" {
+		return nil
+	}
+	lower := strings.ToLower(name)
+	rules := []func(n string) bool{
+		func(n string) bool { return n == name },
+		func(n string) bool { return strings.ToLower(n) == lower },
+		func(n string) bool { return strings.HasPrefix(strings.ToLower(n), lower) },
+		func(n string) bool { return strings.Contains(strings.ToLower(n), lower) },
+	}
+	for _, match := range rules {
+		var matches []string
+		for _, n := range names {
+			if match(n) {
+				matches = append(matches, n)
+			}
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchNames(t *testing.T) {
+	names := []string{
+		"EPSON TM-T20III Receipt",
+		"epson tm-t20",
+		"Kitchen EPSON TM-T88",
+		"Microsoft Print to PDF",
+	}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"epson tm-t20", []string{"epson tm-t20"}},
+		{"EPSON TM-T20", []string{"epson tm-t20"}},
+		{"EPSON TM-T2", []string{"EPSON TM-T20III Receipt", "epson tm-t20"}},
+		{"TM-T88", []string{"Kitchen EPSON TM-T88"}},
+		{"pdf", []string{"Microsoft Print to PDF"}},
+		{"Zebra", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		if got := MatchNames(test.name, names); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("MatchNames(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
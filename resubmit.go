@@ -0,0 +1,146 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "fmt"
+
+// Recovery tells how a document that failed to print is printed again.
+type Recovery int
+
+const (
+	// RecoverResubmit prints the whole document again. Part of it may
+	// then print twice, but the receipt printed last is whole.
+	RecoverResubmit Recovery = iota
+	// RecoverResume sends the data from the first byte the printer did
+	// not accept. It suits printers that keep what they received across
+	// the failure, such as those behind a serial link or a print server
+	// that buffers the data; others print half receipts.
+	RecoverResume
+	// RecoverNone does not print the document again, such as when all
+	// its data was written and only ending the document failed, where
+	// printing again would duplicate the receipt.
+	RecoverNone
+)
+
+func (r Recovery) String() string {
+	switch r {
+	case RecoverResubmit:
+		return "resubmit"
+	case RecoverResume:
+		return "resume"
+	case RecoverNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+// PartialWriteError is returned by PrintRaw when a document fails to
+// print. It tells how much of the document the printer accepted and how
+// it should be printed again, see Printer.Recover.
+type PartialWriteError struct {
+	Written  int // bytes of the document accepted by the printer
+	Total    int
+	Recovery Recovery
+	Err      error
+}
+
+func (e *PartialWriteError) Error() string {
+	return fmt.Sprintf("printer: document failed after %d of %d bytes (%v): %v", e.Written, e.Total, e.Recovery, e.Err)
+}
+
+func (e *PartialWriteError) Unwrap() error {
+	return e.Err
+}
+
+// Acked returns the number of bytes of the current or last document
+// accepted by the transport: written to the spooler, or to the socket or
+// port of printers created with NewPrinter.
+func (p *Printer) Acked() int {
+	return p.acked
+}
+
+// PrintRaw prints data as a raw document called name. If the document
+// fails, the error is a *PartialWriteError telling how much of data the
+// printer accepted and, from the profile's Recovery, how to print it
+// again without losing or duplicating the receipt. Nothing is printed
+// twice by PrintRaw itself; the caller decides, with Recover.
+func (p *Printer) PrintRaw(name string, data []byte) error {
+	err := p.printRaw(name, data)
+	if err == nil {
+		return nil
+	}
+	return &PartialWriteError{
+		Written:  p.acked,
+		Total:    len(data),
+		Recovery: p.recovery(p.acked, len(data)),
+		Err:      err,
+	}
+}
+
+// PrintRaw prints data as a raw document called doc on the printer name,
+// as Printer.PrintRaw does.
+func PrintRaw(name, doc string, data []byte) error {
+	p, err := Open(name)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	return p.PrintRaw(doc, data)
+}
+
+func (p *Printer) printRaw(name string, data []byte) error {
+	if err := p.StartRawDocument(name); err != nil {
+		return err
+	}
+	if err := p.StartPage(); err != nil {
+		p.EndDocument()
+		return err
+	}
+	if _, err := p.Write(data); err != nil {
+		p.EndDocument()
+		return err
+	}
+	if err := p.EndPage(); err != nil {
+		p.EndDocument()
+		return err
+	}
+	return p.EndDocument()
+}
+
+// recovery returns how a document of total bytes that failed after
+// written bytes is printed again.
+func (p *Printer) recovery(written, total int) Recovery {
+	switch {
+	case written == 0:
+		// nothing printed, nothing to duplicate
+		return RecoverResubmit
+	case written >= total:
+		return RecoverNone
+	}
+	return p.profile().Recovery
+}
+
+// Recover prints again the document data called name that PrintRaw
+// failed to print with e, as e.Recovery tells. It returns a
+// *PartialWriteError relative to the whole of data if it fails again,
+// so it can be called until it succeeds or gives up.
+func (p *Printer) Recover(name string, data []byte, e *PartialWriteError) error {
+	switch e.Recovery {
+	case RecoverResubmit:
+		return p.PrintRaw(name, data)
+	case RecoverResume:
+		err := p.PrintRaw(name, data[e.Written:])
+		if pe, ok := err.(*PartialWriteError); ok {
+			pe.Written += e.Written
+			pe.Total = len(data)
+			if pe.Written < pe.Total {
+				// keep resuming, even if nothing was written
+				pe.Recovery = RecoverResume
+			}
+		}
+		return err
+	}
+	return e
+}
@@ -0,0 +1,61 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"testing"
+)
+
+var errUnplugged = errors.New("unplugged")
+
+// failTransport accepts at most limit bytes, then fails.
+type failTransport struct {
+	fakeTransport
+	limit int
+}
+
+func (t *failTransport) Write(b []byte) (int, error) {
+	if n := t.limit - t.Len(); n < len(b) {
+		t.fakeTransport.Write(b[:n])
+		return n, errUnplugged
+	}
+	return t.fakeTransport.Write(b)
+}
+
+func TestPrintRawRecover(t *testing.T) {
+	data := []byte("0123456789")
+	tests := []struct {
+		profile  Recovery
+		limit    int
+		recovery Recovery
+		printed  string
+	}{
+		{RecoverResubmit, 4, RecoverResubmit, "01230123456789"},
+		{RecoverResume, 4, RecoverResume, "0123456789"},
+		{RecoverResume, 0, RecoverResubmit, "0123456789"},
+		{RecoverNone, 4, RecoverNone, "0123"},
+	}
+	for _, tt := range tests {
+		ft := &failTransport{limit: tt.limit}
+		p := NewPrinter("fake", ft)
+		p.Profile = &Profile{Recovery: tt.profile}
+		err := p.PrintRaw("receipt", data)
+		var pe *PartialWriteError
+		if !errors.As(err, &pe) {
+			t.Fatalf("got %v, want a *PartialWriteError", err)
+		}
+		if pe.Written != tt.limit || pe.Total != len(data) || pe.Recovery != tt.recovery || !errors.Is(err, errUnplugged) {
+			t.Errorf("limit %d: got %+v", tt.limit, pe)
+		}
+		ft.limit = 100
+		if err := p.Recover("receipt", data, pe); err != nil && tt.recovery != RecoverNone {
+			t.Errorf("recover %v: %v", tt.recovery, err)
+		}
+		if got := ft.String(); got != tt.printed {
+			t.Errorf("recover %v: printed %q, want %q", tt.recovery, got, tt.printed)
+		}
+	}
+}
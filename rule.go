@@ -0,0 +1,57 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"strings"
+)
+
+// DividerStyle is the look of a line drawn by Divider.
+type DividerStyle int
+
+const (
+	DividerSingle DividerStyle = iota // ------
+	DividerDouble                     // ======
+	DividerDotted                     // ......
+	DividerDashed                     // - - -
+	DividerStars                      // ******
+)
+
+// pattern returns the characters repeated to draw the divider.
+func (s DividerStyle) pattern() string {
+	switch s {
+	case DividerDouble:
+		return "="
+	case DividerDotted:
+		return "."
+	case DividerDashed:
+		return "- "
+	case DividerStars:
+		return "*"
+	}
+	return "-"
+}
+
+// ruleLine repeats pattern over cols characters.
+func ruleLine(pattern string, cols int) string {
+	r := []rune(pattern)
+	if len(r) == 0 || cols <= 0 {
+		return ""
+	}
+	line := []rune(strings.Repeat(pattern, cols/len(r)+1))[:cols]
+	return strings.TrimRight(string(line), " ")
+}
+
+// print a line of char across the paper
+func (p *Printer) Rule(char rune) error {
+	_, err := p.WriteString(ruleLine(string(char), p.Columns()) + "\n")
+	return err
+}
+
+// print a divider across the paper
+func (p *Printer) Divider(style DividerStyle) error {
+	_, err := p.WriteString(ruleLine(style.pattern(), p.Columns()) + "\n")
+	return err
+}
@@ -0,0 +1,26 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestRuleLine(t *testing.T) {
+	tests := []struct {
+		pattern string
+		cols    int
+		want    string
+	}{
+		{DividerSingle.pattern(), 5, "-----"},
+		{DividerDashed.pattern(), 6, "- - -"},
+		{DividerDashed.pattern(), 5, "- - -"},
+		{"═", 3, "═══"},
+		{"-", 0, ""},
+	}
+	for _, test := range tests {
+		if got := ruleLine(test.pattern, test.cols); got != test.want {
+			t.Errorf("ruleLine(%q, %d) = %q, want %q", test.pattern, test.cols, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "fmt"
+
+// SelfTest is a test pattern printed by Printer.SelfTest.
+type SelfTest uint8
+
+const (
+	// SelfTestHexDump prints the hexadecimal dump test pattern.
+	SelfTestHexDump SelfTest = 1
+	// SelfTestStatus prints the printer status sheet: firmware version,
+	// interfaces and settings, as with the feed button held at power on.
+	SelfTestStatus SelfTest = 2
+	// SelfTestRolling prints the rolling character pattern.
+	SelfTestRolling SelfTest = 3
+)
+
+// execute test print on roll paper -- GS ( A pL pH n m, so remote
+// support can check the hardware without someone at the printer. It
+// returns ErrUnsupported if the profile sets NoSelfTest.
+func (p *Printer) SelfTest(t SelfTest) error {
+	if p.profile().NoSelfTest {
+		return ErrUnsupported
+	}
+	if t < SelfTestHexDump || t > SelfTestRolling {
+		return fmt.Errorf("printer: invalid self-test %d", t)
+	}
+	_, err := p.Write([]byte{gs, '(', 'A', 2, 0, 1, byte(t)})
+	return err
+}
@@ -0,0 +1,25 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestSelfTest(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	if err := p.SelfTest(SelfTestStatus); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ft.String(), "\x1D(A\x02\x00\x01\x02"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+	if err := p.SelfTest(4); err == nil {
+		t.Error("no error for an invalid test")
+	}
+	p.Profile = &Profile{NoSelfTest: true}
+	if err := p.SelfTest(SelfTestHexDump); err != ErrUnsupported {
+		t.Errorf("got %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Handshake is the flow control of a serial port.
+type Handshake int
+
+const (
+	HandshakeNone Handshake = iota
+	HandshakeRTSCTS
+	HandshakeXonXoff
+)
+
+// SerialConfig is the configuration of a serial port.
+type SerialConfig struct {
+	Baud      int  // zero means 9600
+	DataBits  int  // zero means 8
+	Parity    byte // 'N', 'E' or 'O', zero means 'N'
+	StopBits  int  // 1 or 2, zero means 1
+	Handshake Handshake
+}
+
+// openSerial opens serial:PORT?baud=19200&data=8&parity=N&stop=1&flow=rtscts.
+func openSerial(port string, params url.Values) (Transport, error) {
+	var c SerialConfig
+	var err error
+	for _, p := range []struct {
+		name string
+		v    *int
+	}{{"baud", &c.Baud}, {"data", &c.DataBits}, {"stop", &c.StopBits}} {
+		if s := params.Get(p.name); s != "" {
+			if *p.v, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("printer: invalid %s %q", p.name, s)
+			}
+		}
+	}
+	if s := params.Get("parity"); s != "" {
+		c.Parity = strings.ToUpper(s)[0]
+	}
+	switch strings.ToLower(params.Get("flow")) {
+	case "", "none":
+	case "rtscts":
+		c.Handshake = HandshakeRTSCTS
+	case "xonxoff":
+		c.Handshake = HandshakeXonXoff
+	default:
+		return nil, fmt.Errorf("printer: invalid flow control %q", params.Get("flow"))
+	}
+	return OpenSerial(port, c)
+}
@@ -0,0 +1,20 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "golang.org/x/sys/unix"
+
+const (
+	ioctlGetTermios = unix.TIOCGETA
+	ioctlSetTermios = unix.TIOCSETA
+)
+
+// setSpeed sets the baud rate of t. Any rate the driver accepts can be
+// set.
+func setSpeed(t *unix.Termios, baud int) error {
+	t.Ispeed = uint64(baud)
+	t.Ospeed = uint64(baud)
+	return nil
+}
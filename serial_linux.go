@@ -0,0 +1,39 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
+
+var bauds = map[int]uint32{
+	1200:   unix.B1200,
+	2400:   unix.B2400,
+	4800:   unix.B4800,
+	9600:   unix.B9600,
+	19200:  unix.B19200,
+	38400:  unix.B38400,
+	57600:  unix.B57600,
+	115200: unix.B115200,
+	230400: unix.B230400,
+}
+
+// setSpeed sets the baud rate of t, one of the standard rates.
+func setSpeed(t *unix.Termios, baud int) error {
+	b, ok := bauds[baud]
+	if !ok {
+		return fmt.Errorf("printer: unsupported baud rate %d", baud)
+	}
+	t.Cflag &^= unix.CBAUD
+	t.Cflag |= b
+	return nil
+}
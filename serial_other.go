@@ -0,0 +1,12 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows && !linux && !darwin
+// +build !windows,!linux,!darwin
+
+package printer
+
+func OpenSerial(port string, c SerialConfig) (Transport, error) {
+	return nil, &PlatformError{Op: "OpenSerial"}
+}
@@ -0,0 +1,101 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+// +build linux darwin
+
+package printer
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// OpenSerial opens the serial port, such as "/dev/ttyUSB0", configured
+// with c. The port is an *os.File, whose deadlines the runtime poller
+// implements.
+func OpenSerial(port string, c SerialConfig) (Transport, error) {
+	f, err := os.OpenFile(port, os.O_RDWR|unix.O_NOCTTY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	// Fd would put the file back in blocking mode
+	cerr := rc.Control(func(fd uintptr) {
+		err = configureSerial(int(fd), c)
+	})
+	if err == nil {
+		err = cerr
+	}
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("printer: %s: %v", port, err)
+	}
+	return f, nil
+}
+
+// configureSerial puts the terminal fd in raw mode with the settings of
+// c.
+func configureSerial(fd int, c SerialConfig) error {
+	t, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return err
+	}
+	t.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON | unix.IXOFF
+	t.Oflag &^= unix.OPOST
+	t.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	t.Cflag &^= unix.CSIZE | unix.PARENB | unix.PARODD | unix.CSTOPB | unix.CRTSCTS
+	t.Cflag |= unix.CREAD | unix.CLOCAL
+	switch c.DataBits {
+	case 0, 8:
+		t.Cflag |= unix.CS8
+	case 7:
+		t.Cflag |= unix.CS7
+	case 6:
+		t.Cflag |= unix.CS6
+	case 5:
+		t.Cflag |= unix.CS5
+	default:
+		return fmt.Errorf("printer: invalid number of data bits %d", c.DataBits)
+	}
+	switch c.Parity {
+	case 0, 'N', 'n':
+	case 'E', 'e':
+		t.Cflag |= unix.PARENB
+	case 'O', 'o':
+		t.Cflag |= unix.PARENB | unix.PARODD
+	default:
+		return fmt.Errorf("printer: invalid parity %q", c.Parity)
+	}
+	switch c.StopBits {
+	case 0, 1:
+	case 2:
+		t.Cflag |= unix.CSTOPB
+	default:
+		return fmt.Errorf("printer: invalid number of stop bits %d", c.StopBits)
+	}
+	switch c.Handshake {
+	case HandshakeRTSCTS:
+		t.Cflag |= unix.CRTSCTS
+	case HandshakeXonXoff:
+		t.Iflag |= unix.IXON | unix.IXOFF
+	}
+	// return from reads as soon as a byte arrived
+	t.Cc[unix.VMIN] = 1
+	t.Cc[unix.VTIME] = 0
+	baud := 9600
+	if c.Baud != 0 {
+		baud = c.Baud
+	}
+	if err := setSpeed(t, baud); err != nil {
+		return err
+	}
+	return unix.IoctlSetTermios(fd, ioctlSetTermios, t)
+}
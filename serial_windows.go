@@ -0,0 +1,218 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+//sys	GetCommState(h syscall.Handle, dcb *DCB) (err error) = kernel32.GetCommState
+//sys	SetCommState(h syscall.Handle, dcb *DCB) (err error) = kernel32.SetCommState
+
+type DCB struct {
+	DCBlength  uint32
+	BaudRate   uint32
+	Flags      uint32
+	WReserved  uint16
+	XonLim     uint16
+	XoffLim    uint16
+	ByteSize   byte
+	Parity     byte
+	StopBits   byte
+	XonChar    byte
+	XoffChar   byte
+	ErrorChar  byte
+	EofChar    byte
+	EvtChar    byte
+	WReserved1 uint16
+}
+
+const (
+	DCB_BINARY           = 0x0001
+	DCB_PARITY           = 0x0002
+	DCB_OUTX_CTS_FLOW    = 0x0004
+	DCB_OUTX             = 0x0100
+	DCB_INX              = 0x0200
+	DCB_DTR_CONTROL_MASK = 0x0030
+	DCB_DTR_CONTROL_ON   = 0x0010
+	DCB_RTS_CONTROL_MASK = 0x3000
+	DCB_RTS_CONTROL_ON   = 0x1000
+	DCB_RTS_HANDSHAKE    = 0x2000
+
+	NOPARITY   = 0
+	ODDPARITY  = 1
+	EVENPARITY = 2
+
+	ONESTOPBIT  = 0
+	TWOSTOPBITS = 2
+)
+
+// serialTransport is a transport to a printer on a serial port.
+// Deadlines are implemented with the timeouts of the port.
+type serialTransport struct {
+	h windows.Handle
+
+	mu          sync.Mutex
+	write, read time.Time // deadlines
+}
+
+// OpenSerial opens the serial port, such as "COM3", configured with c.
+func OpenSerial(port string, c SerialConfig) (Transport, error) {
+	path := port
+	if !strings.HasPrefix(path, `\\.\`) {
+		path = `\\.\` + port
+	}
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: port, Err: err}
+	}
+	h, err := windows.CreateFile(name,
+		windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: port, Err: err}
+	}
+	if err := configureSerial(h, c); err != nil {
+		windows.CloseHandle(h)
+		return nil, fmt.Errorf("printer: %s: %v", port, err)
+	}
+	return &serialTransport{h: h}, nil
+}
+
+func configureSerial(h windows.Handle, c SerialConfig) error {
+	var dcb DCB
+	dcb.DCBlength = uint32(unsafe.Sizeof(dcb))
+	if err := GetCommState(syscall.Handle(h), &dcb); err != nil {
+		return err
+	}
+	dcb.BaudRate = 9600
+	if c.Baud != 0 {
+		dcb.BaudRate = uint32(c.Baud)
+	}
+	dcb.ByteSize = 8
+	if c.DataBits != 0 {
+		dcb.ByteSize = byte(c.DataBits)
+	}
+	dcb.Flags &^= DCB_PARITY | DCB_OUTX_CTS_FLOW | DCB_OUTX | DCB_INX | DCB_DTR_CONTROL_MASK | DCB_RTS_CONTROL_MASK
+	dcb.Flags |= DCB_BINARY | DCB_DTR_CONTROL_ON
+	switch c.Parity {
+	case 0, 'N', 'n':
+		dcb.Parity = NOPARITY
+	case 'E', 'e':
+		dcb.Parity, dcb.Flags = EVENPARITY, dcb.Flags|DCB_PARITY
+	case 'O', 'o':
+		dcb.Parity, dcb.Flags = ODDPARITY, dcb.Flags|DCB_PARITY
+	default:
+		return fmt.Errorf("printer: invalid parity %q", c.Parity)
+	}
+	switch c.StopBits {
+	case 0, 1:
+		dcb.StopBits = ONESTOPBIT
+	case 2:
+		dcb.StopBits = TWOSTOPBITS
+	default:
+		return fmt.Errorf("printer: invalid number of stop bits %d", c.StopBits)
+	}
+	switch c.Handshake {
+	case HandshakeNone:
+		dcb.Flags |= DCB_RTS_CONTROL_ON
+	case HandshakeRTSCTS:
+		dcb.Flags |= DCB_OUTX_CTS_FLOW | DCB_RTS_HANDSHAKE
+	case HandshakeXonXoff:
+		dcb.Flags |= DCB_OUTX | DCB_INX | DCB_RTS_CONTROL_ON
+	}
+	return SetCommState(syscall.Handle(h), &dcb)
+}
+
+// timeout returns the milliseconds left until deadline d, for
+// CommTimeouts: zero d means waiting as long as possible.
+func timeout(d time.Time) uint32 {
+	if d.IsZero() {
+		return windows.INFINITE - 1
+	}
+	ms := time.Until(d) / time.Millisecond
+	if ms < 1 {
+		ms = 1
+	}
+	if ms >= windows.INFINITE {
+		ms = windows.INFINITE - 1
+	}
+	return uint32(ms)
+}
+
+func (t *serialTransport) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	d := t.write
+	t.mu.Unlock()
+	if !d.IsZero() && !time.Now().Before(d) {
+		return 0, fmt.Errorf("printer: write: %w", os.ErrDeadlineExceeded)
+	}
+	ct := windows.CommTimeouts{WriteTotalTimeoutConstant: timeout(d)}
+	if d.IsZero() {
+		ct.WriteTotalTimeoutConstant = 0 // no timeout
+	}
+	if err := windows.SetCommTimeouts(t.h, &ct); err != nil {
+		return 0, err
+	}
+	var n uint32
+	if err := windows.WriteFile(t.h, b, &n, nil); err != nil {
+		return int(n), err
+	}
+	if int(n) < len(b) {
+		return int(n), fmt.Errorf("printer: write: %w", os.ErrDeadlineExceeded)
+	}
+	return int(n), nil
+}
+
+func (t *serialTransport) Read(b []byte) (int, error) {
+	t.mu.Lock()
+	d := t.read
+	t.mu.Unlock()
+	if !d.IsZero() && !time.Now().Before(d) {
+		return 0, fmt.Errorf("printer: read: %w", os.ErrDeadlineExceeded)
+	}
+	// return as soon as a byte arrived
+	ct := windows.CommTimeouts{
+		ReadIntervalTimeout:        windows.INFINITE,
+		ReadTotalTimeoutMultiplier: windows.INFINITE,
+		ReadTotalTimeoutConstant:   timeout(d),
+	}
+	if err := windows.SetCommTimeouts(t.h, &ct); err != nil {
+		return 0, err
+	}
+	var n uint32
+	if err := windows.ReadFile(t.h, b, &n, nil); err != nil {
+		return int(n), err
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("printer: read: %w", os.ErrDeadlineExceeded)
+	}
+	return int(n), nil
+}
+
+func (t *serialTransport) SetWriteDeadline(d time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.write = d
+	return nil
+}
+
+func (t *serialTransport) SetReadDeadline(d time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.read = d
+	return nil
+}
+
+func (t *serialTransport) Close() error {
+	return windows.CloseHandle(t.h)
+}
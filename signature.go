@@ -0,0 +1,122 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"image"
+)
+
+// Stroke is a line drawn without lifting the pen while capturing a
+// signature, as points in the coordinates of the capture device.
+type Stroke []image.Point
+
+// DefaultPenWidth is the width in dots of the lines of signatures printed
+// with PrintSignature.
+const DefaultPenWidth = 3
+
+// SignatureImage draws strokes in a black and white image width dots
+// wide, with lines pen dots wide. The strokes are scaled to fill the
+// width, keeping their aspect ratio.
+func SignatureImage(strokes []Stroke, width, pen int) (*image.Paletted, error) {
+	var bounds image.Rectangle
+	n := 0
+	for _, s := range strokes {
+		for _, pt := range s {
+			if n == 0 {
+				bounds = image.Rectangle{pt, pt}
+			}
+			bounds = bounds.Union(image.Rectangle{pt, pt.Add(image.Pt(1, 1))})
+			n++
+		}
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("printer: empty signature")
+	}
+	if pen < 1 {
+		pen = 1
+	}
+	if width <= pen {
+		return nil, fmt.Errorf("printer: signature width %d too small for pen width %d", width, pen)
+	}
+	// scale so the strokes with the pen around them fill width
+	span := bounds.Dx() - 1
+	if span == 0 {
+		span = 1
+	}
+	scale := float64(width-pen) / float64(span)
+	height := int(float64(bounds.Dy()-1)*scale) + pen
+	img := image.NewPaletted(image.Rect(0, 0, width, height), monochrome)
+	for i := range img.Pix {
+		img.Pix[i] = 1
+	}
+	at := func(pt image.Point) image.Point {
+		return image.Pt(
+			int(float64(pt.X-bounds.Min.X)*scale)+pen/2,
+			int(float64(pt.Y-bounds.Min.Y)*scale)+pen/2)
+	}
+	for _, s := range strokes {
+		for i := range s {
+			from := at(s[i])
+			to := from
+			if i+1 < len(s) {
+				to = at(s[i+1])
+			}
+			drawLine(img, from, to, pen)
+		}
+	}
+	return img, nil
+}
+
+// drawLine draws a line pen dots wide from a to b.
+func drawLine(img *image.Paletted, a, b image.Point, pen int) {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := 1, 1
+	if a.X > b.X {
+		sx = -1
+	}
+	if a.Y > b.Y {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		dot(img, a, pen)
+		if a == b {
+			return
+		}
+		if 2*e >= dy {
+			e += dy
+			a.X += sx
+		}
+		if 2*e <= dx {
+			e += dx
+			a.Y += sy
+		}
+	}
+}
+
+// dot draws a round dot pen dots wide centered on c.
+func dot(img *image.Paletted, c image.Point, pen int) {
+	r := pen / 2
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if pen > 2 && x*x+y*y > r*r {
+				continue
+			}
+			if pt := c.Add(image.Pt(x, y)); pt.In(img.Rect) {
+				img.Pix[img.PixOffset(pt.X, pt.Y)] = 0
+			}
+		}
+	}
+}
+
+// print a captured signature width dots wide -- see SignatureImage
+func (p *Printer) PrintSignature(strokes []Stroke, width int) error {
+	img, err := SignatureImage(strokes, width, DefaultPenWidth)
+	if err != nil {
+		return err
+	}
+	return p.PrintImage(img)
+}
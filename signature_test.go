@@ -0,0 +1,37 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"image"
+	"testing"
+)
+
+func TestSignatureImage(t *testing.T) {
+	strokes := []Stroke{
+		{{100, 100}, {300, 100}},
+		{{200, 50}, {200, 150}},
+	}
+	img, err := SignatureImage(strokes, 203, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 203, 103); got != want {
+		t.Fatalf("bounds = %v, want %v", got, want)
+	}
+	for _, pt := range []image.Point{{1, 51}, {201, 51}, {101, 1}, {101, 101}, {101, 51}} {
+		if img.ColorIndexAt(pt.X, pt.Y) != 0 {
+			t.Errorf("%v is not black", pt)
+		}
+	}
+	for _, pt := range []image.Point{{1, 1}, {201, 102}, {50, 20}} {
+		if img.ColorIndexAt(pt.X, pt.Y) != 1 {
+			t.Errorf("%v is not white", pt)
+		}
+	}
+	if _, err := SignatureImage(nil, 203, 3); err == nil {
+		t.Error("empty signature drawn")
+	}
+}
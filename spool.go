@@ -0,0 +1,19 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "fmt"
+
+// LowDiskSpaceError is returned by StartDocument when the free space in
+// the spool directory is below Printer.MinSpoolSpace.
+type LowDiskSpaceError struct {
+	Dir  string
+	Free uint64
+	Min  uint64
+}
+
+func (e *LowDiskSpaceError) Error() string {
+	return fmt.Sprintf("printer: only %d bytes free in spool directory %s, need %d", e.Free, e.Dir, e.Min)
+}
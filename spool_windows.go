@@ -0,0 +1,78 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const SPLREG_DEFAULT_SPOOL_DIRECTORY = "DefaultSpoolDirectory"
+
+//sys	GetPrinterData(h syscall.Handle, value *uint16, typ *uint32, buf *byte, bufN uint32, needed *uint32) (errno error) = winspool.GetPrinterDataW
+
+// SpoolDirectory returns the directory where the spooler stores the jobs
+// waiting to print.
+func SpoolDirectory() (string, error) {
+	var h syscall.Handle
+	err := OpenPrinter(nil, &h, nil)
+	if err != nil {
+		return "", err
+	}
+	defer ClosePrinter(h)
+	value, err := windows.UTF16PtrFromString(SPLREG_DEFAULT_SPOOL_DIRECTORY)
+	if err != nil {
+		return "", err
+	}
+	b := make([]uint16, syscall.MAX_PATH)
+	var typ, needed uint32
+	for {
+		err := GetPrinterData(h, value, &typ, (*byte)(unsafe.Pointer(&b[0])), uint32(2*len(b)), &needed)
+		if err == nil {
+			break
+		}
+		if err != ERROR_MORE_DATA || needed <= uint32(2*len(b)) {
+			return "", err
+		}
+		b = make([]uint16, needed/2+1)
+	}
+	return syscall.UTF16ToString(b), nil
+}
+
+// SpoolFreeSpace returns the spool directory and the number of bytes
+// available to the spooler in it.
+func SpoolFreeSpace() (dir string, free uint64, err error) {
+	dir, err = SpoolDirectory()
+	if err != nil {
+		return "", 0, err
+	}
+	d, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return "", 0, err
+	}
+	err = windows.GetDiskFreeSpaceEx(d, &free, nil, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	return dir, free, nil
+}
+
+// checkSpoolSpace returns a *LowDiskSpaceError if p.MinSpoolSpace is set
+// and the spool directory has less space available.
+func (p *Printer) checkSpoolSpace() error {
+	if p.MinSpoolSpace == 0 {
+		return nil
+	}
+	dir, free, err := SpoolFreeSpace()
+	if err != nil {
+		return err
+	}
+	if free < p.MinSpoolSpace {
+		return &LowDiskSpaceError{Dir: dir, Free: free, Min: p.MinSpoolSpace}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// spoolBuffer is a buffer filled by a spooler call, reused across calls
+// so that servers polling queues do not allocate one every time.
+type spoolBuffer struct {
+	b []byte
+}
+
+// maxPooledBuffer is the size above which buffers are not kept for reuse.
+const maxPooledBuffer = 1 << 20
+
+var spoolBuffers = sync.Pool{
+	New: func() interface{} { return &spoolBuffer{b: make([]byte, 4096)} },
+}
+
+// spoolCall calls fn with a pooled buffer, growing it as long as fn fails
+// with ERROR_INSUFFICIENT_BUFFER or ERROR_MORE_DATA and reports a larger
+// needed size. The returned buffer must be released with free once
+// nothing points into it anymore.
+func spoolCall(fn func(b []byte, needed *uint32) error) (*spoolBuffer, error) {
+	sb := spoolBuffers.Get().(*spoolBuffer)
+	for {
+		var needed uint32
+		err := fn(sb.b, &needed)
+		if err == nil {
+			return sb, nil
+		}
+		if (err != syscall.ERROR_INSUFFICIENT_BUFFER && err != ERROR_MORE_DATA) || needed <= uint32(len(sb.b)) {
+			sb.free()
+			return nil, err
+		}
+		sb.b = make([]byte, needed)
+	}
+}
+
+func (sb *spoolBuffer) free() {
+	if cap(sb.b) > maxPooledBuffer {
+		return
+	}
+	spoolBuffers.Put(sb)
+}
+
+// at returns a pointer to the i-th element of size bytes of the array the
+// spooler wrote at the start of sb.
+func (sb *spoolBuffer) at(i int, size uintptr) unsafe.Pointer {
+	return unsafe.Pointer(&sb.b[i*int(size)])
+}
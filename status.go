@@ -0,0 +1,142 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Status is the printer status returned by DLE EOT 1.
+type Status byte
+
+const (
+	StatusDrawerOpen      Status = 1 << 2 // drawer kick connector pin 3 high
+	StatusOffline         Status = 1 << 3
+	StatusWaitingRecovery Status = 1 << 5 // waiting for online recovery
+	StatusFeedButton      Status = 1 << 6 // paper feed button pressed
+)
+
+// ErrOffline is returned by writes with flow control when the printer
+// stays offline longer than FlowControl.Timeout.
+var ErrOffline = errors.New("printer: printer offline")
+
+// Read reads data sent back by the printer, such as status bytes. Not
+// every port supports it.
+func (p *Printer) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.flushLocked(); err != nil {
+		return 0, err
+	}
+	p.lastIO = time.Now()
+	return p.t.Read(b)
+}
+
+// transmit real-time status -- DLE EOT n, n is 1 for the printer status,
+// 2 for the offline cause, 3 for error causes and 4 for the paper
+// sensors
+func (p *Printer) RealtimeStatus(n byte) (byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.realtimeStatus(n)
+}
+
+func (p *Printer) realtimeStatus(n byte) (byte, error) {
+	if err := p.flushLocked(); err != nil {
+		return 0, err
+	}
+	p.lastIO = time.Now()
+	if _, err := p.t.Write([]byte{DLE, EOT, n}); err != nil {
+		return 0, err
+	}
+	b := make([]byte, 1)
+	if _, err := p.t.Read(b); err != nil {
+		return 0, err
+	}
+	// bits 1 and 4 are always set, bits 0 and 7 never
+	if b[0]&0x93 != 0x12 {
+		return 0, fmt.Errorf("printer: invalid status byte %#x", b[0])
+	}
+	return b[0], nil
+}
+
+// Status returns the printer status -- DLE EOT 1
+func (p *Printer) Status() (Status, error) {
+	b, err := p.RealtimeStatus(1)
+	return Status(b), err
+}
+
+// FlowControl paces the data written to printers on slow links, such as
+// serial or Bluetooth ports, whose buffers overflow and drop data when
+// the printer goes offline, for instance when its cover is opened.
+type FlowControl struct {
+	// Burst is the number of bytes written between status checks.
+	// Zero means 4096.
+	Burst int
+	// Poll is the interval between status checks while the printer is
+	// offline. Zero means 100ms.
+	Poll time.Duration
+	// Timeout is how long writes wait for an offline printer before
+	// failing with ErrOffline. Zero means they wait forever.
+	Timeout time.Duration
+}
+
+func (fc *FlowControl) burst() int {
+	if fc.Burst <= 0 {
+		return 4096
+	}
+	return fc.Burst
+}
+
+func (fc *FlowControl) poll() time.Duration {
+	if fc.Poll <= 0 {
+		return 100 * time.Millisecond
+	}
+	return fc.Poll
+}
+
+// flowWrite writes b in bursts, waiting for the printer to be online
+// between them.
+func (p *Printer) flowWrite(b []byte) (int, error) {
+	n := 0
+	for len(b) > 0 {
+		if n > 0 {
+			if err := p.waitOnline(); err != nil {
+				return n, err
+			}
+		}
+		k := min(len(b), p.Flow.burst())
+		m, err := p.send(b[:k])
+		n += m
+		if err != nil {
+			return n, err
+		}
+		b = b[k:]
+	}
+	return n, nil
+}
+
+// waitOnline polls the printer status until the printer is online.
+func (p *Printer) waitOnline() error {
+	var deadline time.Time
+	if p.Flow.Timeout > 0 {
+		deadline = time.Now().Add(p.Flow.Timeout)
+	}
+	for {
+		s, err := p.Status()
+		if err != nil {
+			return err
+		}
+		if s&StatusOffline == 0 {
+			return nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return ErrOffline
+		}
+		time.Sleep(p.Flow.poll())
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"io"
+)
+
+// Style is the text formatting state of an ESC/POS printer.
+type Style struct {
+	Font Font
+	// Width and Height are the character size multipliers, see
+	// SetCharSize. Zero means 1.
+	Width, Height uint8
+	// Underline is the underline thickness in dots: 0, 1 or 2.
+	Underline uint8
+	Emphasize bool
+	Reverse   bool
+	Rotate    bool
+	Align     Align
+}
+
+// Style returns the current text formatting.
+func (p *Printer) Style() Style {
+	w, h := p.CharSize()
+	return Style{
+		Font:      p.font,
+		Width:     w,
+		Height:    h,
+		Underline: p.underline,
+		Emphasize: p.emphasize != 0,
+		Reverse:   p.reverse != 0,
+		Rotate:    p.rotate != 0,
+		Align:     p.align,
+	}
+}
+
+// SetStyle applies s, sending only the commands of the fields that
+// differ from the current formatting.
+func (p *Printer) SetStyle(s Style) error {
+	cur := p.Style()
+	if s.Font != cur.Font {
+		if err := p.SelectFont(s.Font); err != nil {
+			return err
+		}
+	}
+	w, h := s.Width, s.Height
+	if w == 0 {
+		w = 1
+	}
+	if h == 0 {
+		h = 1
+	}
+	if w != cur.Width || h != cur.Height {
+		if err := p.SetCharSize(w, h); err != nil {
+			return err
+		}
+	}
+	if s.Underline != cur.Underline {
+		p.SetUnderline(s.Underline)
+	}
+	if s.Emphasize != cur.Emphasize {
+		p.SetEmphasize(boolByte(s.Emphasize))
+	}
+	if s.Reverse != cur.Reverse {
+		p.SetReverse(boolByte(s.Reverse))
+	}
+	if s.Rotate != cur.Rotate {
+		p.SetRotate(boolByte(s.Rotate))
+	}
+	if s.Align != cur.Align {
+		if err := p.SelectAlign(s.Align); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PushStyle saves the current text formatting, to be restored by
+// PopStyle.
+func (p *Printer) PushStyle() {
+	p.styles = append(p.styles, p.Style())
+}
+
+// PopStyle restores the text formatting saved by the last PushStyle.
+func (p *Printer) PopStyle() error {
+	if len(p.styles) == 0 {
+		return errors.New("printer: PopStyle without PushStyle")
+	}
+	s := p.styles[len(p.styles)-1]
+	p.styles = p.styles[:len(p.styles)-1]
+	return p.SetStyle(s)
+}
+
+// Writer writes text to a printer.
+type Writer interface {
+	io.Writer
+	io.StringWriter
+}
+
+// WithStyle applies s, calls fn to write the styled text and restores the
+// previous formatting, even if fn fails or panics.
+func (p *Printer) WithStyle(s Style, fn func(w Writer) error) (err error) {
+	p.PushStyle()
+	defer func() {
+		if perr := p.PopStyle(); err == nil {
+			err = perr
+		}
+	}()
+	if err := p.SetStyle(s); err != nil {
+		return err
+	}
+	return fn(p)
+}
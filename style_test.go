@@ -0,0 +1,130 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPushPopStyle(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		style Style
+		pop   string // sent by PopStyle
+	}{
+		{"unchanged", Style{}, ""},
+		{"font", Style{Font: FontB}, "\x1BM\x00"},
+		{"size", Style{Width: 2, Height: 3}, "\x1D!\x00"},
+		{"underline", Style{Underline: 2}, "\x1B-\x00"},
+		{"emphasize", Style{Emphasize: true}, "\x1BG\x00"},
+		{"reverse", Style{Reverse: true}, "\x1DB\x00"},
+		{"rotate", Style{Rotate: true}, "\x1BV\x00"},
+		{"align", Style{Align: AlignRight}, "\x1Ba\x00"},
+		{"all", Style{Font: FontB, Width: 2, Height: 2, Underline: 1, Emphasize: true, Reverse: true, Rotate: true, Align: AlignCenter},
+			"\x1BM\x00\x1D!\x00\x1B-\x00\x1BG\x00\x1DB\x00\x1BV\x00\x1Ba\x00"},
+	} {
+		ft := new(fakeTransport)
+		p := NewPrinter("fake", ft)
+		before := p.Style()
+		p.PushStyle()
+		if err := p.SetStyle(tt.style); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		ft.Reset()
+		if err := p.PopStyle(); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := ft.String(); got != tt.pop {
+			t.Errorf("%s: PopStyle wrote %q, want %q", tt.name, got, tt.pop)
+		}
+		if got := p.Style(); got != before {
+			t.Errorf("%s: style after PopStyle %+v, want %+v", tt.name, got, before)
+		}
+	}
+}
+
+func TestPopStyleEmpty(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	if err := p.PopStyle(); err == nil {
+		t.Error("no error popping an empty stack")
+	}
+	if ft.Len() != 0 {
+		t.Errorf("PopStyle of an empty stack wrote %q", ft.String())
+	}
+}
+
+func TestNestedStyles(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	outer := Style{Emphasize: true}
+	inner := Style{Emphasize: true, Underline: 1, Align: AlignCenter}
+	p.PushStyle()
+	p.SetStyle(outer)
+	p.PushStyle()
+	p.SetStyle(inner)
+	ft.Reset()
+	if err := p.PopStyle(); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Style(); got != (Style{Width: 1, Height: 1, Emphasize: true}) {
+		t.Errorf("after first PopStyle %+v, want the outer style", got)
+	}
+	if got, want := ft.String(), "\x1B-\x00\x1Ba\x00"; got != want {
+		t.Errorf("first PopStyle wrote %q, want %q", got, want)
+	}
+	ft.Reset()
+	if err := p.PopStyle(); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Style(); got != (Style{Width: 1, Height: 1}) {
+		t.Errorf("after second PopStyle %+v, want the default style", got)
+	}
+	if got, want := ft.String(), "\x1BG\x00"; got != want {
+		t.Errorf("second PopStyle wrote %q, want %q", got, want)
+	}
+}
+
+func TestWithStyleRestores(t *testing.T) {
+	bold := Style{Emphasize: true, Align: AlignCenter}
+	fail := errors.New("render failed")
+	for _, tt := range []struct {
+		name string
+		fn   func(w Writer) error
+	}{
+		{"error", func(w Writer) error {
+			w.WriteString("x")
+			return fail
+		}},
+		{"panic", func(w Writer) error {
+			w.WriteString("x")
+			panic(fail)
+		}},
+	} {
+		ft := new(fakeTransport)
+		p := NewPrinter("fake", ft)
+		before := p.Style()
+		func() {
+			defer func() {
+				if r := recover(); r != nil && r != fail {
+					t.Errorf("%s: recovered %v", tt.name, r)
+				}
+			}()
+			if err := p.WithStyle(bold, tt.fn); err != fail {
+				t.Errorf("%s: WithStyle = %v, want %v", tt.name, err, fail)
+			}
+		}()
+		if got := p.Style(); got != before {
+			t.Errorf("%s: style after WithStyle %+v, want %+v", tt.name, got, before)
+		}
+		if got, want := ft.String(), "\x1BG\x01\x1Ba\x01x\x1BG\x00\x1Ba\x00"; got != want {
+			t.Errorf("%s: wrote %q, want %q", tt.name, got, want)
+		}
+		if len(p.styles) != 0 {
+			t.Errorf("%s: %d styles left on the stack", tt.name, len(p.styles))
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reconnect tells a TCPTransport how to recover when its connection
+// drops, which happens whenever a receipt printer is power-cycled.
+type Reconnect struct {
+	// Attempts is the number of dials tried before giving up. Zero
+	// disables reconnection.
+	Attempts int
+	// Backoff is the wait before the first dial, doubled after every
+	// failed one. Zero means 500ms.
+	Backoff time.Duration
+	// Resume makes a connection dropped in the middle of a document
+	// resend the document from its start after reconnecting. Otherwise
+	// the document fails with a *ConnectionLostError; the next one
+	// starts on a new connection.
+	Resume bool
+}
+
+// ConnectionLostError is returned when the connection to a printer drops
+// and cannot be recovered.
+type ConnectionLostError struct {
+	Addr string
+	Err  error
+}
+
+func (e *ConnectionLostError) Error() string {
+	return fmt.Sprintf("printer: connection to %s lost: %v", e.Addr, e.Err)
+}
+
+func (e *ConnectionLostError) Unwrap() error {
+	return e.Err
+}
+
+// TCPTransport is a transport to a printer listening on a raw TCP port,
+// usually 9100. Dropped connections are reconnected as set by Reconnect.
+// It is safe for concurrent use.
+type TCPTransport struct {
+	Addr        string
+	DialTimeout time.Duration // zero means 5s
+	Reconnect   Reconnect
+
+	mu          sync.Mutex
+	conn        net.Conn
+	err         error     // why conn was dropped
+	write, read time.Time // deadlines
+	inDoc       bool
+	sent        int    // bytes of the current document written
+	doc         []byte // data of the current document, for Resume
+	lost        error  // the current document failed
+	closed      bool
+	done        chan struct{} // closed by Close
+	redialing   chan struct{} // closed when the redial in progress ends
+}
+
+var errClosed = errors.New("printer: transport closed")
+
+// DialTCP connects to the printer at addr, a host and port, retrying up
+// to 3 times when the connection later drops.
+func DialTCP(addr string) (*TCPTransport, error) {
+	t := &TCPTransport{Addr: addr, Reconnect: Reconnect{Attempts: 3}}
+	conn, err := t.dial()
+	if err != nil {
+		return nil, err
+	}
+	t.conn = conn
+	return t, nil
+}
+
+func (t *TCPTransport) dial() (net.Conn, error) {
+	timeout := t.DialTimeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	return net.DialTimeout("tcp", t.Addr, timeout)
+}
+
+// doneChan returns the channel closed by Close.
+func (t *TCPTransport) doneChan() chan struct{} {
+	if t.done == nil {
+		t.done = make(chan struct{})
+	}
+	return t.done
+}
+
+// drop closes the connection after err.
+func (t *TCPTransport) drop(err error) {
+	t.conn.Close()
+	t.conn, t.err = nil, err
+}
+
+// redial reconnects after the connection dropped. It is called with t.mu
+// held but releases it while waiting and dialing, so only the writes wait
+// for the new connection, and returns early if t is closed.
+func (t *TCPTransport) redial() error {
+	backoff := t.Reconnect.Backoff
+	if backoff == 0 {
+		backoff = 500 * time.Millisecond
+	}
+	done, redialing := t.doneChan(), make(chan struct{})
+	t.redialing = redialing
+	defer func() {
+		t.redialing = nil
+		close(redialing)
+	}()
+	err := t.err
+	for i := 0; i < t.Reconnect.Attempts; i++ {
+		t.mu.Unlock()
+		var conn net.Conn
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+			conn, err = t.dial()
+		case <-done:
+			timer.Stop()
+		}
+		t.mu.Lock()
+		if t.closed {
+			if conn != nil {
+				conn.Close()
+			}
+			return errClosed
+		}
+		if err == nil {
+			conn.SetWriteDeadline(t.write)
+			conn.SetReadDeadline(t.read)
+			t.conn, t.err = conn, nil
+			return nil
+		}
+		backoff *= 2
+	}
+	if err == nil {
+		err = errors.New("connection closed")
+	}
+	return &ConnectionLostError{Addr: t.Addr, Err: err}
+}
+
+func (t *TCPTransport) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.redialing != nil {
+		// keep the writes in order behind the redial of another one
+		redialing := t.redialing
+		t.mu.Unlock()
+		<-redialing
+		t.mu.Lock()
+	}
+	if t.closed {
+		return 0, errClosed
+	}
+	if t.lost != nil {
+		return 0, t.lost
+	}
+	if t.conn != nil {
+		n, err := t.conn.Write(b)
+		if err == nil {
+			t.record(b)
+			return n, nil
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return n, err
+		}
+		t.drop(err)
+	}
+	// redial clears the error the connection was dropped with
+	cause := t.err
+	if err := t.redial(); err != nil {
+		if t.inDoc {
+			t.lost = err
+		}
+		return 0, err
+	}
+	data := b
+	if t.inDoc && t.sent > 0 {
+		if !t.Reconnect.Resume {
+			t.lost = &ConnectionLostError{Addr: t.Addr, Err: cause}
+			return 0, t.lost
+		}
+		data = append(t.doc[:len(t.doc):len(t.doc)], b...)
+	}
+	if _, err := t.conn.Write(data); err != nil {
+		t.drop(err)
+		return 0, err
+	}
+	t.record(b)
+	return len(b), nil
+}
+
+// record keeps the data of the current document if it may be resent.
+func (t *TCPTransport) record(b []byte) {
+	t.sent += len(b)
+	if t.inDoc && t.Reconnect.Resume {
+		t.doc = append(t.doc, b...)
+	}
+}
+
+func (t *TCPTransport) Read(b []byte) (int, error) {
+	t.mu.Lock()
+	conn, cause, closed := t.conn, t.err, t.closed
+	t.mu.Unlock()
+	if closed {
+		return 0, errClosed
+	}
+	if conn == nil {
+		return 0, &ConnectionLostError{Addr: t.Addr, Err: cause}
+	}
+	n, err := conn.Read(b)
+	if err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.mu.Lock()
+		if t.conn == conn {
+			t.drop(err)
+		}
+		t.mu.Unlock()
+	}
+	return n, err
+}
+
+// StartDocument marks the start of a document, which Resume resends
+// from.
+func (t *TCPTransport) StartDocument(name, datatype string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inDoc, t.sent, t.doc, t.lost = true, 0, t.doc[:0], nil
+	return nil
+}
+
+// EndDocument marks the end of a document. It returns the
+// *ConnectionLostError the document failed with, if any.
+func (t *TCPTransport) EndDocument() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	err := t.lost
+	t.inDoc, t.sent, t.doc, t.lost = false, 0, t.doc[:0], nil
+	return err
+}
+
+func (t *TCPTransport) SetWriteDeadline(d time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.write = d
+	if t.conn != nil {
+		return t.conn.SetWriteDeadline(d)
+	}
+	return nil
+}
+
+func (t *TCPTransport) SetReadDeadline(d time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.read = d
+	if t.conn != nil {
+		return t.conn.SetReadDeadline(d)
+	}
+	return nil
+}
+
+func (t *TCPTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.closed {
+		t.closed = true
+		close(t.doneChan())
+	}
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
@@ -0,0 +1,143 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+// printerServer accepts connections on a local port and sends the data
+// received on each of them once it is closed.
+func printerServer(t *testing.T) (net.Listener, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make(chan string, 10)
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				b, _ := ioutil.ReadAll(c)
+				c.Close()
+				data <- string(b)
+			}()
+		}
+	}()
+	return l, data
+}
+
+func receive(t *testing.T, data <-chan string) string {
+	select {
+	case s := <-data:
+		return s
+	case <-time.After(5 * time.Second):
+		t.Fatal("no data received")
+	}
+	return ""
+}
+
+func TestTCPTransportResume(t *testing.T) {
+	l, data := printerServer(t)
+	defer l.Close()
+	tt, err := DialTCP(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt.Reconnect = Reconnect{Attempts: 2, Backoff: time.Millisecond, Resume: true}
+	tt.StartDocument("doc", "RAW")
+	if _, err := tt.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	tt.conn.Close() // drop the connection
+	if got := receive(t, data); got != "abc" {
+		t.Errorf("first connection got %q", got)
+	}
+	if _, err := tt.Write([]byte("def")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tt.EndDocument(); err != nil {
+		t.Fatal(err)
+	}
+	tt.Close()
+	if got := receive(t, data); got != "abcdef" {
+		t.Errorf("second connection got %q, want the document resent", got)
+	}
+}
+
+func TestTCPTransportLost(t *testing.T) {
+	l, data := printerServer(t)
+	defer l.Close()
+	tt, err := DialTCP(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt.Reconnect = Reconnect{Attempts: 2, Backoff: time.Millisecond}
+	tt.StartDocument("doc", "RAW")
+	tt.Write([]byte("abc"))
+	tt.conn.Close()
+	receive(t, data)
+	var lost *ConnectionLostError
+	if _, err := tt.Write([]byte("def")); !errors.As(err, &lost) {
+		t.Fatalf("Write after drop: %v, want *ConnectionLostError", err)
+	}
+	var netErr *net.OpError
+	if lost.Err == nil || !errors.As(lost, &netErr) {
+		t.Errorf("ConnectionLostError %v does not wrap the network error", lost)
+	}
+	if err := tt.EndDocument(); !errors.As(err, &lost) {
+		t.Fatalf("EndDocument: %v, want *ConnectionLostError", err)
+	}
+	tt.StartDocument("next", "RAW")
+	if _, err := tt.Write([]byte("ghi")); err != nil {
+		t.Fatal(err)
+	}
+	tt.EndDocument()
+	tt.Close()
+	if got := receive(t, data); got != "ghi" {
+		t.Errorf("next document got %q", got)
+	}
+	if _, err := tt.Write([]byte("x")); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
+
+func TestTCPTransportCloseWhileRedialing(t *testing.T) {
+	l, data := printerServer(t)
+	tt, err := DialTCP(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt.Reconnect = Reconnect{Attempts: 2, Backoff: time.Hour}
+	tt.conn.Close()
+	l.Close()
+	receive(t, data)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := tt.Write([]byte("abc"))
+		errc <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	// not blocked by the backoff of the write
+	if err := tt.SetReadDeadline(time.Now()); err != nil {
+		t.Error(err)
+	}
+	tt.Close()
+	select {
+	case err := <-errc:
+		if err != errClosed {
+			t.Errorf("Write = %v, want %v", err, errClosed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write still redialing after Close")
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeviceClass tells how a printer is driven.
+type DeviceClass int
+
+const (
+	// ClassGDI is a page printer rendered through GDI.
+	ClassGDI DeviceClass = iota
+	// ClassESCPOS is a receipt printer accepting raw ESC/POS commands.
+	ClassESCPOS
+)
+
+func (c DeviceClass) String() string {
+	if c == ClassESCPOS {
+		return "ESC/POS"
+	}
+	return "GDI"
+}
+
+// escposDrivers are fragments of driver names used by ESC/POS receipt
+// printers.
+var escposDrivers = []string{
+	"generic / text only",
+	"esc/pos",
+	"escpos",
+	"receipt",
+	"thermal",
+	"epson tm-",
+	"star tsp",
+	"bixolon",
+	"xprinter",
+	"pos-58",
+	"pos-80",
+	"pos58",
+	"pos80",
+}
+
+// DeviceClass tells whether p is an ESC/POS receipt printer or a page
+// printer, from the command set the printer reports in its device ID or
+// else by guessing from the driver name.
+func (p *Printer) DeviceClass() (DeviceClass, error) {
+	if id, err := p.DeviceID(); err == nil && len(id.CommandSet) > 0 {
+		if id.Supports("ESC/POS") || id.Supports("ESCPOS") {
+			return ClassESCPOS, nil
+		}
+		return ClassGDI, nil
+	}
+	di, err := p.DriverInfo()
+	if err != nil {
+		return ClassGDI, err
+	}
+	name := strings.ToLower(di.Name)
+	for _, s := range escposDrivers {
+		if strings.Contains(name, s) {
+			return ClassESCPOS, nil
+		}
+	}
+	return ClassGDI, nil
+}
+
+// PrintTestPage prints a diagnostic page on the printer name. Receipt
+// printers get every font and size, alignment marks, a barcode and a QR
+// code followed by a cut; page printers get a GDI rendered equivalent.
+func PrintTestPage(name string) error {
+	p, err := Open(name)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	class, err := p.DeviceClass()
+	if err != nil {
+		return err
+	}
+	if class == ClassESCPOS {
+		return p.escposTestPage()
+	}
+	return p.gdiTestPage()
+}
+
+// testPageInfo returns the lines identifying the printer on a test page.
+func (p *Printer) testPageInfo() []string {
+	info := []string{
+		"Printer: " + p.name,
+	}
+	if di, err := p.DriverInfo(); err == nil {
+		info = append(info, "Driver: "+di.Name)
+	}
+	return append(info, "Date: "+time.Now().Format("2006-01-02 15:04:05"))
+}
+
+func (p *Printer) escposTestPage() error {
+	err := p.StartRawDocument("Test page")
+	if err != nil {
+		return err
+	}
+	err = p.StartPage()
+	if err == nil {
+		err = p.writeTestPage()
+	}
+	if err == nil {
+		err = p.EndPage()
+	}
+	if err != nil {
+		p.EndDocument()
+		return err
+	}
+	return p.EndDocument()
+}
+
+// writeTestPage writes the ESC/POS test page to the current page.
+func (p *Printer) writeTestPage() error {
+	p.Init()
+	p.SetAlign("center")
+	p.SetEmphasize(1)
+	p.WriteString("*** TEST PAGE ***\n")
+	p.SetEmphasize(0)
+	p.SetAlign("left")
+	for _, line := range p.testPageInfo() {
+		p.WriteString(line + "\n")
+	}
+	p.Formfeed()
+
+	for _, font := range []string{"A", "B"} {
+		p.SetFont(font)
+		for size := uint8(1); size <= 3; size++ {
+			p.SetCharSize(size, size)
+			p.WriteString(fmt.Sprintf("Font %s %dx%d\n", font, size, size))
+		}
+		p.SetCharSize(1, 1)
+	}
+	p.SetFont("A")
+	p.Formfeed()
+
+	for _, align := range []string{"left", "center", "right"} {
+		p.SetAlign(align)
+		p.WriteString("|" + align + "|\n")
+	}
+	p.SetAlign("center")
+	p.Formfeed()
+
+	if err := p.Barcode("TEST123", BarcodeCODE39); err != nil {
+		return err
+	}
+	p.Formfeed()
+	if err := p.QRCode("TEST PAGE "+p.name, 6, QRCodeErrorCorrectionLevelM); err != nil {
+		return err
+	}
+	p.FormfeedN(3)
+	p.Cut()
+	return nil
+}
+
+func (p *Printer) gdiTestPage() error {
+	doc := NewDocument("Test page", PageOptions{
+		Layout: PageLayout{Margins: Margins{Left: 36, Top: 36, Right: 36, Bottom: 36}},
+	})
+	doc.SetFont(FontSpec{Family: "Arial", Size: 18, Weight: FW_BOLD})
+	doc.Text("Test Page", AlignCenter)
+	doc.Line()
+	doc.SetFont(DefaultFont)
+	for _, line := range p.testPageInfo() {
+		doc.Text(line, AlignLeft)
+	}
+	doc.Space(12)
+	for _, family := range []string{"Arial", "Times New Roman", "Courier New"} {
+		for _, size := range []int{8, 12, 18} {
+			doc.SetFont(FontSpec{Family: family, Size: size})
+			doc.Text(fmt.Sprintf("%s %dpt", family, size), AlignLeft)
+		}
+	}
+	doc.SetFont(DefaultFont)
+	doc.Space(12)
+	doc.Text("left", AlignLeft)
+	doc.Text("center", AlignCenter)
+	doc.Text("right", AlignRight)
+	doc.Line()
+	return doc.Print(p)
+}
@@ -0,0 +1,122 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"time"
+)
+
+// Bits of the offline cause, error cause and paper sensor status
+// returned by DLE EOT 2, 3 and 4.
+const (
+	offlineCoverOpen = 1 << 2
+	offlinePaperEnd  = 1 << 5
+	offlineError     = 1 << 6
+
+	errorCutter        = 1 << 3
+	errorUnrecoverable = 1 << 5
+	errorAutoRecovery  = 1 << 6
+
+	paperEnd = 3 << 5
+)
+
+// NotPrintedError is returned by Transact when the printer does not
+// confirm printing a receipt.
+type NotPrintedError struct {
+	Reason string // such as "cover open" or "paper end"
+}
+
+func (e *NotPrintedError) Error() string {
+	return "printer: receipt not printed: " + e.Reason
+}
+
+// Transact buffers the data render writes to p and sends it in a single
+// write, then asks the printer to confirm it processed the data and
+// checks its status, so a POS can mark an order as printed with
+// confidence. Nothing is sent if render fails. It returns a
+// *NotPrintedError if the printer does not answer within timeout, is
+// offline, reports an error or is out of paper. The printer must be
+// reachable through a port that reads, see Printer.Read.
+func (p *Printer) Transact(render func() error, timeout time.Duration) error {
+	var data []byte
+	err := func() error {
+		p.tx = make([]byte, 0, 1024)
+		// stop buffering even if render panics
+		defer func() { data, p.tx = p.tx, nil }()
+		return render()
+	}()
+	if err != nil {
+		return err
+	}
+	p.txID = p.txID%9999 + 1
+	id := []byte(fmt.Sprintf("%04d", p.txID))
+	// transmission response of the process ID, sent by the printer once
+	// the data before it is processed -- GS ( H pL pH fn m d1 d2 d3 d4
+	data = append(data, gs, '(', 'H', 6, 0, 48, 48)
+	data = append(data, id...)
+	if _, err := p.Write(data); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.t.SetReadDeadline(time.Now().Add(timeout))
+	defer p.t.SetReadDeadline(time.Time{})
+	if err := p.waitProcessID(id); err != nil {
+		return &NotPrintedError{Reason: "no response: " + err.Error()}
+	}
+	return p.checkPrinted()
+}
+
+// waitProcessID reads the data sent back by the printer until the
+// process ID response of id: 0x37 0x22 id NUL.
+func (p *Printer) waitProcessID(id []byte) error {
+	want := append([]byte{0x37, 0x22}, id...)
+	want = append(want, 0)
+	n := 0
+	for n < len(want) {
+		b, err := p.readByte()
+		if err != nil {
+			return err
+		}
+		switch {
+		case b == want[n]:
+			n++
+		case b == want[0]:
+			n = 1
+		default:
+			n = 0
+		}
+	}
+	return nil
+}
+
+// checkPrinted returns a *NotPrintedError if the real-time status of
+// the printer shows it could not print.
+func (p *Printer) checkPrinted() error {
+	var s [5]byte
+	for n := byte(1); n <= 4; n++ {
+		b, err := p.realtimeStatus(n)
+		if err != nil {
+			return &NotPrintedError{Reason: "no status: " + err.Error()}
+		}
+		s[n] = b
+	}
+	switch {
+	case s[2]&offlineCoverOpen != 0:
+		return &NotPrintedError{Reason: "cover open"}
+	case s[2]&offlinePaperEnd != 0 || s[4]&paperEnd != 0:
+		return &NotPrintedError{Reason: "paper end"}
+	case s[3]&errorCutter != 0:
+		return &NotPrintedError{Reason: "cutter error"}
+	case s[3]&errorUnrecoverable != 0:
+		return &NotPrintedError{Reason: "unrecoverable error"}
+	case s[3]&errorAutoRecovery != 0 || s[2]&offlineError != 0:
+		return &NotPrintedError{Reason: "printer error"}
+	case Status(s[1])&StatusOffline != 0:
+		return &NotPrintedError{Reason: "printer offline"}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransact(t *testing.T) {
+	tests := []struct {
+		status string
+		reason string
+	}{
+		{"\x37\x22\x37\x220001\x00\x12\x12\x12\x12", ""},
+		{"\x37\x22\x37\x220001\x00\x1A\x16\x12\x12", "cover open"},
+		{"\x37\x22\x37\x220001\x00\x12\x12\x12\x72", "paper end"},
+		{"\x37\x22\x37\x220001\x00\x1A\x52\x1A\x12", "cutter error"},
+		{"\x37\x220002\x00", "no response: unexpected EOF"},
+	}
+	for _, tt := range tests {
+		ft := &fakeTransport{status: []byte(tt.status)}
+		p := NewPrinter("fake", ft)
+		err := p.Transact(func() error {
+			_, err := p.WriteString("order 42\n")
+			return err
+		}, time.Second)
+		var npe *NotPrintedError
+		switch {
+		case tt.reason == "" && err != nil:
+			t.Errorf("status %q: %v", tt.status, err)
+		case tt.reason != "" && (!errors.As(err, &npe) || npe.Reason != tt.reason):
+			t.Errorf("status %q: got %v, want %s", tt.status, err, tt.reason)
+		}
+		want := "order 42\n\x1D(H\x06\x00\x30\x300001"
+		if got := ft.String(); len(got) < len(want) || got[:len(want)] != want {
+			t.Errorf("wrote %q, want %q first", got, want)
+		}
+	}
+
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	err := p.Transact(func() error {
+		p.WriteString("order 43\n")
+		return errors.New("out of stock")
+	}, time.Second)
+	if err == nil || ft.Len() != 0 {
+		t.Errorf("got %v, wrote %q", err, ft.String())
+	}
+}
+
+func TestTransactPanic(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	func() {
+		defer func() { recover() }()
+		p.Transact(func() error {
+			p.WriteString("order 44\n")
+			panic("render failed")
+		}, time.Second)
+	}()
+	p.WriteString("next\n")
+	if got := ft.String(); got != "next\n" {
+		t.Errorf("wrote %q after a panicking render, want %q", got, "next\n")
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"io"
+	"time"
+)
+
+// Transport carries the data of a Printer to the device: the Windows
+// spooler for printers opened with Open, or a direct connection such as a
+// TCP socket or a serial port for printers created with NewPrinter.
+type Transport interface {
+	io.ReadWriteCloser
+
+	// SetWriteDeadline and SetReadDeadline set the time after which
+	// writes and reads fail with an error wrapping
+	// os.ErrDeadlineExceeded, like those of a net.Conn. The zero time
+	// means no deadline.
+	SetWriteDeadline(t time.Time) error
+	SetReadDeadline(t time.Time) error
+}
+
+// DocumentTransport is implemented by transports that delimit documents,
+// which Printer.StartDocument and Printer.EndDocument then start and end.
+type DocumentTransport interface {
+	Transport
+	StartDocument(name, datatype string) error
+	EndDocument() error
+}
+
+// NewPrinter returns a printer named name whose data is carried by t.
+// Spooler features, such as Jobs or SetDuplex, are not available.
+func NewPrinter(name string, t Transport) *Printer {
+	return &Printer{name: name, t: t}
+}
+
+// set the deadline of writes to the printer, see Transport
+func (p *Printer) SetWriteDeadline(t time.Time) error {
+	return p.t.SetWriteDeadline(t)
+}
+
+// set the deadline of reads from the printer, see Transport
+func (p *Printer) SetReadDeadline(t time.Time) error {
+	return p.t.SetReadDeadline(t)
+}
@@ -0,0 +1,153 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTransport records the data written to it.
+type fakeTransport struct {
+	bytes.Buffer
+	docs   []string
+	status []byte // returned by Read
+	closed bool
+}
+
+func (t *fakeTransport) Read(b []byte) (int, error) {
+	n := copy(b, t.status)
+	t.status = t.status[n:]
+	return n, nil
+}
+
+func (t *fakeTransport) Close() error                       { t.closed = true; return nil }
+func (t *fakeTransport) SetWriteDeadline(d time.Time) error { return nil }
+func (t *fakeTransport) SetReadDeadline(d time.Time) error  { return nil }
+
+func (t *fakeTransport) StartDocument(name, datatype string) error {
+	t.docs = append(t.docs, name+" "+datatype)
+	return nil
+}
+
+func (t *fakeTransport) EndDocument() error {
+	t.docs = append(t.docs, "end")
+	return nil
+}
+
+func TestNewPrinter(t *testing.T) {
+	ft := new(fakeTransport)
+	p := NewPrinter("fake", ft)
+	if err := p.StartRawDocument("receipt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.StartPage(); err != nil {
+		t.Fatal(err)
+	}
+	p.WriteString("hello\n")
+	if err := p.EndPage(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.EndDocument(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := ft.String(); got != "hello\n" {
+		t.Errorf("wrote %q", got)
+	}
+	if len(ft.docs) != 2 || ft.docs[0] != "receipt RAW" || ft.docs[1] != "end" {
+		t.Errorf("documents %q", ft.docs)
+	}
+	if !ft.closed {
+		t.Error("transport not closed")
+	}
+}
+
+func TestFlowControl(t *testing.T) {
+	ft := &fakeTransport{status: []byte{0x1A, 0x12, 0x12}} // offline, online, online
+	p := NewPrinter("fake", ft)
+	p.Flow = &FlowControl{Burst: 4, Poll: time.Millisecond}
+	if _, err := p.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	want := "0123\x10\x04\x01\x10\x04\x014567\x10\x04\x0189"
+	if got := ft.String(); got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+}
+
+// shortTransport accepts up to limit bytes, then fails, and counts the
+// writes it is given.
+type shortTransport struct {
+	fakeTransport
+	limit  int
+	writes int
+}
+
+func (t *shortTransport) Write(b []byte) (int, error) {
+	t.writes++
+	if n := t.limit - t.Len(); n < len(b) {
+		t.fakeTransport.Write(b[:n])
+		return n, errors.New("port gone")
+	}
+	return t.fakeTransport.Write(b)
+}
+
+func TestWritePartial(t *testing.T) {
+	st := &shortTransport{limit: 6}
+	p := NewPrinter("fake", st)
+	p.copies = 2
+	if n, err := p.Write(nil); n != 0 || err != nil || st.writes != 0 {
+		t.Errorf("Write(nil) = %d, %v with %d writes", n, err, st.writes)
+	}
+	n, err := p.Write([]byte("0123456789"))
+	if n != 6 || err == nil {
+		t.Errorf("Write = %d, %v, want 6 and an error", n, err)
+	}
+	if string(p.doc) != "012345" {
+		t.Errorf("kept %q for copies, want the bytes written", p.doc)
+	}
+}
+
+func TestWriteBuffer(t *testing.T) {
+	st := &shortTransport{limit: 1 << 20}
+	p := NewPrinter("fake", st)
+	p.WriteBuffer = 8
+	for _, s := range []string{"ab", "cd", "ef", "gh", "ij"} {
+		if _, err := p.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := st.String(); got != "abcdefgh" || st.writes != 1 {
+		t.Errorf("sent %q in %d writes, want %q in 1", got, st.writes, "abcdefgh")
+	}
+	// writes as large as the buffer are not buffered
+	if _, err := p.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if got := st.String(); got != "abcdefghij0123456789" || st.writes != 3 {
+		t.Errorf("sent %q in %d writes", got, st.writes)
+	}
+	p.WriteString("kl")
+	st.status = []byte{0x12}
+	if _, err := p.Status(); err != nil {
+		t.Fatal(err)
+	}
+	if got := st.String(); got != "abcdefghij0123456789kl\x10\x04\x01" {
+		t.Errorf("sent %q, want the buffered data before the status query", got)
+	}
+	p.WriteString("mn")
+	if err := p.EndPage(); err != nil {
+		t.Fatal(err)
+	}
+	if got := st.String(); !strings.HasSuffix(got, "mn") {
+		t.Errorf("sent %q, want the buffered data flushed by EndPage", got)
+	}
+}
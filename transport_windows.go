@@ -0,0 +1,133 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+//sys	AbortPrinter(h syscall.Handle) (err error) = winspool.AbortPrinter
+//sys	ReadPrinter(h syscall.Handle, buf *byte, bufN uint32, read *uint32) (err error) = winspool.ReadPrinter
+
+// spoolTransport writes to a printer through the spooler. WritePrinter
+// and ReadPrinter block while the port does, so with a deadline they run
+// on a goroutine of their own; writes past the deadline abort the
+// document, which makes the spooler give up on the port.
+type spoolTransport struct {
+	h syscall.Handle
+
+	mu          sync.Mutex
+	write, read time.Time // deadlines
+}
+
+func (t *spoolTransport) deadlines() (write, read time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.write, t.read
+}
+
+func (t *spoolTransport) SetWriteDeadline(d time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.write = d
+	return nil
+}
+
+func (t *spoolTransport) SetReadDeadline(d time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.read = d
+	return nil
+}
+
+func (t *spoolTransport) Write(b []byte) (int, error) {
+	deadline, _ := t.deadlines()
+	if deadline.IsZero() {
+		return t.writePrinter(b)
+	}
+	// the goroutine may outlive the call, so it writes a copy
+	b = append([]byte(nil), b...)
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		n, err := t.writePrinter(b)
+		done <- result{n, err}
+	}()
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-timer.C:
+		AbortPrinter(t.h)
+		return 0, fmt.Errorf("printer: write: %w", os.ErrDeadlineExceeded)
+	}
+}
+
+// writePrinter writes all of b, calling WritePrinter again after partial
+// writes, and returns the number of bytes written before any error.
+func (t *spoolTransport) writePrinter(b []byte) (int, error) {
+	n := 0
+	for n < len(b) {
+		var written uint32
+		err := WritePrinter(t.h, &b[n], uint32(len(b)-n), &written)
+		n += int(written)
+		if err != nil {
+			return n, err
+		}
+		if written == 0 {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+func (t *spoolTransport) Read(b []byte) (int, error) {
+	_, deadline := t.deadlines()
+	if deadline.IsZero() {
+		return t.readPrinter(b)
+	}
+	type result struct {
+		b   []byte
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		buf := make([]byte, len(b))
+		n, err := t.readPrinter(buf)
+		done <- result{buf[:n], err}
+	}()
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return copy(b, r.b), r.err
+	case <-timer.C:
+		return 0, fmt.Errorf("printer: read: %w", os.ErrDeadlineExceeded)
+	}
+}
+
+func (t *spoolTransport) readPrinter(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	var read uint32
+	if err := ReadPrinter(t.h, &b[0], uint32(len(b)), &read); err != nil {
+		return 0, err
+	}
+	return int(read), nil
+}
+
+func (t *spoolTransport) Close() error {
+	return ClosePrinter(t.h)
+}
@@ -0,0 +1,30 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"runtime"
+)
+
+// ErrUnsupported is returned when the printer or its driver does not
+// support the requested operation.
+var ErrUnsupported = errors.New("printer: operation not supported")
+
+// PlatformError is returned on systems other than Windows by the features
+// that need the Windows spooler or GDI, such as Open or Jobs. It matches
+// ErrUnsupported with errors.Is, so that portable programs can fall back
+// to a Transport.
+type PlatformError struct {
+	Op string // such as "Open"
+}
+
+func (e *PlatformError) Error() string {
+	return "printer: " + e.Op + " is not supported on " + runtime.GOOS
+}
+
+func (e *PlatformError) Is(target error) bool {
+	return target == ErrUnsupported
+}
@@ -0,0 +1,21 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlatformError(t *testing.T) {
+	var err error = &PlatformError{Op: "Open"}
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("%v does not match ErrUnsupported", err)
+	}
+	var pe *PlatformError
+	if !errors.As(err, &pe) || pe.Op != "Open" {
+		t.Errorf("errors.As(%v) = %v", err, pe)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkDigit returns the GS1 check digit of the digits in data, as used by
+// UPC and EAN barcodes: from the right, digits are weighted 3, 1, 3, ...
+func checkDigit(data string) byte {
+	sum := 0
+	for i := 0; i < len(data); i++ {
+		d := int(data[len(data)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// CompressUPCE compresses the UPC-A number upca, of 11 digits or 12 with
+// the check digit, into the 8 digits of the equivalent UPC-E barcode. It
+// returns an error if the number cannot be compressed: the number system
+// must be 0 or 1 and the manufacturer and product codes must have enough
+// zeros.
+func CompressUPCE(upca string) (string, error) {
+	if strings.Trim(upca, "0123456789") != "" || (len(upca) != 11 && len(upca) != 12) {
+		return "", fmt.Errorf("printer: UPC-A number %q must have 11 or 12 digits", upca)
+	}
+	check := checkDigit(upca[:11])
+	if len(upca) == 12 && upca[11] != check {
+		return "", fmt.Errorf("printer: UPC-A number %q has check digit %c, want %c", upca, upca[11], check)
+	}
+	if upca[0] != '0' && upca[0] != '1' {
+		return "", fmt.Errorf("printer: UPC-A number %q with number system %c cannot be compressed", upca, upca[0])
+	}
+	mfr, product := upca[1:6], upca[6:11]
+	var e string
+	switch {
+	case (mfr[2:] == "000" || mfr[2:] == "100" || mfr[2:] == "200") && product[:2] == "00":
+		e = mfr[:2] + product[2:] + mfr[2:3]
+	case mfr[3:] == "00" && product[:3] == "000":
+		e = mfr[:3] + product[3:] + "3"
+	case mfr[4] == '0' && product[:4] == "0000":
+		e = mfr[:4] + product[4:] + "4"
+	case mfr[4] != '0' && product[:4] == "0000" && product[4] >= '5':
+		e = mfr + product[4:]
+	default:
+		return "", fmt.Errorf("printer: UPC-A number %q cannot be compressed to UPC-E", upca)
+	}
+	return upca[:1] + e + string(check), nil
+}
+
+// ScaleLabel is an in-store EAN-13 barcode embedding a price or a weight,
+// as printed by retail scales on deli and bakery labels. The layout is
+// the 2 digit prefix, the 5 digit item code and the 5 digit value, or
+// with PriceCheck, a price check digit followed by a 4 digit value.
+type ScaleLabel struct {
+	// Prefix is the GS1 restricted circulation prefix, "02" or "20" to
+	// "29"; which of them carry prices or weights is set by each
+	// retailer.
+	Prefix string
+	// Item is the item code, up to 5 digits.
+	Item string
+	// Value is the price in minor units, such as cents, or the weight,
+	// such as in grams.
+	Value int
+	// PriceCheck adds a check digit for the value.
+	PriceCheck bool
+}
+
+// EAN13 returns the 13 digits of the barcode of l.
+func (l ScaleLabel) EAN13() (string, error) {
+	if !isScalePrefix(l.Prefix) {
+		return "", fmt.Errorf("printer: invalid scale label prefix %q", l.Prefix)
+	}
+	if len(l.Item) == 0 || len(l.Item) > 5 || strings.Trim(l.Item, "0123456789") != "" {
+		return "", fmt.Errorf("printer: invalid scale label item code %q", l.Item)
+	}
+	digits := 5
+	if l.PriceCheck {
+		digits = 4
+	}
+	value := fmt.Sprintf("%0*d", digits, l.Value)
+	if l.Value < 0 || len(value) > digits {
+		return "", fmt.Errorf("printer: scale label value %d does not fit in %d digits", l.Value, digits)
+	}
+	if l.PriceCheck {
+		value = string(priceCheckDigit(value)) + value
+	}
+	code := l.Prefix + fmt.Sprintf("%05s", l.Item) + value
+	return code + string(checkDigit(code)), nil
+}
+
+// ParseScaleLabel decodes the EAN-13 barcode code of a scale label, laid
+// out with or without price check digit.
+func ParseScaleLabel(code string, priceCheck bool) (ScaleLabel, error) {
+	var l ScaleLabel
+	if len(code) != 13 || strings.Trim(code, "0123456789") != "" {
+		return l, fmt.Errorf("printer: invalid EAN-13 %q", code)
+	}
+	if checkDigit(code[:12]) != code[12] {
+		return l, fmt.Errorf("printer: EAN-13 %q has a wrong check digit", code)
+	}
+	if !isScalePrefix(code[:2]) {
+		return l, fmt.Errorf("printer: EAN-13 %q is not a scale label", code)
+	}
+	l.Prefix, l.Item, l.PriceCheck = code[:2], code[2:7], priceCheck
+	value := code[7:12]
+	if priceCheck {
+		if priceCheckDigit(value[1:]) != value[0] {
+			return l, fmt.Errorf("printer: EAN-13 %q has a wrong price check digit", code)
+		}
+		value = value[1:]
+	}
+	for _, c := range value {
+		l.Value = l.Value*10 + int(c-'0')
+	}
+	return l, nil
+}
+
+func isScalePrefix(p string) bool {
+	return p == "02" || (len(p) == 2 && p[0] == '2' && p[1] >= '0' && p[1] <= '9')
+}
+
+// priceCheckDigit returns the GS1 check digit of a 4 digit price, computed
+// with the weighting factors 2-, 2-, 3 and 5-.
+func priceCheckDigit(price string) byte {
+	const (
+		w2minus = "0246891357"
+		w3      = "0369258147"
+		w5minus = "0594837261"
+	)
+	weights := []string{w2minus, w2minus, w3, w5minus}
+	sum := 0
+	for i := 0; i < 4; i++ {
+		sum += int(weights[i][price[i]-'0'] - '0')
+	}
+	return byte('0' + sum*3%10)
+}
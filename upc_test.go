@@ -0,0 +1,67 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import "testing"
+
+func TestCompressUPCE(t *testing.T) {
+	tests := []struct {
+		upca, want string
+	}{
+		{"042100005264", "04252614"},
+		{"04210000526", "04252614"},
+		{"012340000053", "01234543"},
+		{"012345000065", "01234565"},
+	}
+	for _, test := range tests {
+		got, err := CompressUPCE(test.upca)
+		if err != nil || got != test.want {
+			t.Errorf("CompressUPCE(%q) = %q, %v, want %q", test.upca, got, err, test.want)
+		}
+	}
+	for _, upca := range []string{
+		"042100005265", // wrong check digit
+		"24210000526",  // number system 2
+		"01234567890",  // not enough zeros
+		"0421000052",   // too short
+	} {
+		if got, err := CompressUPCE(upca); err == nil {
+			t.Errorf("CompressUPCE(%q) = %q, want error", upca, got)
+		}
+	}
+}
+
+func TestScaleLabel(t *testing.T) {
+	if got := priceCheckDigit("2875"); got != '9' {
+		t.Errorf("priceCheckDigit(2875) = %c, want 9", got)
+	}
+	tests := []struct {
+		l    ScaleLabel
+		want string
+	}{
+		{ScaleLabel{Prefix: "21", Item: "1234", Value: 1999}, "2101234019993"},
+		{ScaleLabel{Prefix: "28", Item: "12345", Value: 2875, PriceCheck: true}, "2812345928752"},
+	}
+	for _, test := range tests {
+		got, err := test.l.EAN13()
+		if err != nil || got != test.want {
+			t.Errorf("%+v.EAN13() = %q, %v, want %q", test.l, got, err, test.want)
+			continue
+		}
+		l, err := ParseScaleLabel(got, test.l.PriceCheck)
+		if err != nil || l.Value != test.l.Value {
+			t.Errorf("ParseScaleLabel(%q) = %+v, %v", got, l, err)
+		}
+	}
+	for _, l := range []ScaleLabel{
+		{Prefix: "30", Item: "1", Value: 1},
+		{Prefix: "20", Item: "123456", Value: 1},
+		{Prefix: "20", Item: "1", Value: 12345, PriceCheck: true},
+	} {
+		if got, err := l.EAN13(); err == nil {
+			t.Errorf("%+v.EAN13() = %q, want error", l, got)
+		}
+	}
+}
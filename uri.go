@@ -0,0 +1,111 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// TransportFactory opens the transport of a printer URI given the
+// address following the scheme, unescaped, and the query parameters. See
+// OpenURI.
+type TransportFactory func(addr string, params url.Values) (Transport, error)
+
+var (
+	transportsMu sync.RWMutex
+	transports   = map[string]TransportFactory{
+		"tcp":    openTCP,
+		"serial": openSerial,
+		"file":   openFile,
+	}
+)
+
+// RegisterTransport makes OpenURI open the URIs with scheme using
+// factory, so other packages can provide transports, such as one to a
+// cloud print agent, that work with everything built on Printer.
+// Registering a scheme again replaces its factory. The scheme is not case
+// sensitive; "winspool" is reserved.
+func RegisterTransport(scheme string, factory TransportFactory) {
+	scheme = strings.ToLower(scheme)
+	if scheme == "winspool" || factory == nil {
+		panic("printer: invalid RegisterTransport of " + scheme)
+	}
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	transports[scheme] = factory
+}
+
+// OpenURI opens the printer addressed by uri, so deployments configure
+// printers with a single string. The scheme of uri selects the transport:
+//
+//	winspool://EPSON TM-T20          the Windows spooler queue, see Open
+//	tcp://10.0.0.5:9100              a raw TCP port, see DialTCP
+//	serial:COM3?baud=19200           a serial port, see OpenSerial
+//	file:./out.bin                   a file the data is written to
+//
+// The address may be preceded by "//" and may be percent-encoded. Other
+// schemes can be added with RegisterTransport.
+func OpenURI(uri string) (*Printer, error) {
+	scheme, addr, params, err := parseURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if scheme == "winspool" {
+		return Open(addr)
+	}
+	transportsMu.RLock()
+	open, ok := transports[scheme]
+	transportsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("printer: unknown transport %q in %q", scheme, uri)
+	}
+	t, err := open(addr, params)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrinter(uri, t), nil
+}
+
+// parseURI splits uri in its scheme, address and query parameters. Unlike
+// url.Parse, it accepts addresses such as printer names with spaces.
+func parseURI(uri string) (scheme, addr string, params url.Values, err error) {
+	i := strings.Index(uri, ":")
+	if i <= 0 {
+		return "", "", nil, fmt.Errorf("printer: %q is not a printer URI", uri)
+	}
+	scheme, addr = strings.ToLower(uri[:i]), strings.TrimPrefix(uri[i+1:], "//")
+	if i := strings.Index(addr, "?"); i >= 0 {
+		if params, err = url.ParseQuery(addr[i+1:]); err != nil {
+			return "", "", nil, fmt.Errorf("printer: %q: %v", uri, err)
+		}
+		addr = addr[:i]
+	}
+	if addr, err = url.PathUnescape(addr); err != nil {
+		return "", "", nil, fmt.Errorf("printer: %q: %v", uri, err)
+	}
+	if addr == "" {
+		return "", "", nil, fmt.Errorf("printer: %q has no address", uri)
+	}
+	return scheme, addr, params, nil
+}
+
+// openTCP opens tcp://host[:port], port 9100 by default.
+func openTCP(addr string, params url.Values) (Transport, error) {
+	return DialTCP(tcpAddr(addr))
+}
+
+// tcpAddr adds port 9100 to addr if it has none. IPv6 hosts may be
+// bracketed as in URLs, such as [::1], or not.
+func tcpAddr(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	host := strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	return net.JoinHostPort(host, "9100")
+}
@@ -0,0 +1,113 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		uri, scheme, addr, params string
+	}{
+		{"winspool://EPSON TM-T20", "winspool", "EPSON TM-T20", ""},
+		{"winspool://Kitchen%20Printer", "winspool", "Kitchen Printer", ""},
+		{"tcp://10.0.0.5:9100", "tcp", "10.0.0.5:9100", ""},
+		{"TCP://printer.local", "tcp", "printer.local", ""},
+		{"tcp://[::1]", "tcp", "[::1]", ""},
+		{"serial:COM3?baud=19200&flow=rtscts", "serial", "COM3", "baud=19200&flow=rtscts"},
+		{"file:./out.bin", "file", "./out.bin", ""},
+	}
+	for _, test := range tests {
+		scheme, addr, params, err := parseURI(test.uri)
+		if err != nil {
+			t.Errorf("parseURI(%q): %v", test.uri, err)
+			continue
+		}
+		if scheme != test.scheme || addr != test.addr || params.Encode() != test.params {
+			t.Errorf("parseURI(%q) = %q, %q, %q", test.uri, scheme, addr, params.Encode())
+		}
+	}
+	for _, uri := range []string{"", "COM3", ":x", "tcp://", "file:a%zz"} {
+		if _, _, _, err := parseURI(uri); err == nil {
+			t.Errorf("parseURI(%q) succeeded", uri)
+		}
+	}
+}
+
+func TestTCPAddr(t *testing.T) {
+	tests := []struct {
+		addr, want string
+	}{
+		{"10.0.0.5", "10.0.0.5:9100"},
+		{"10.0.0.5:9101", "10.0.0.5:9101"},
+		{"printer.local", "printer.local:9100"},
+		{"[::1]", "[::1]:9100"},
+		{"[::1]:9101", "[::1]:9101"},
+		{"::1", "[::1]:9100"},
+		{"[fe80::1%eth0]", "[fe80::1%eth0]:9100"},
+	}
+	for _, test := range tests {
+		if got := tcpAddr(test.addr); got != test.want {
+			t.Errorf("tcpAddr(%q) = %q, want %q", test.addr, got, test.want)
+		}
+	}
+}
+
+func TestOpenURIFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "printer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "out.bin")
+	p, err := OpenURI("file:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Init()
+	p.WriteString("hello\n")
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "\x18\x1BS\x1B@\x1Bt\x00\x1BR\x00\x1B2hello\n"; got != want {
+		t.Errorf("file holds %q, want %q", got, want)
+	}
+	if _, err := OpenURI("lpt:1"); err == nil {
+		t.Error("unknown scheme opened")
+	}
+}
+
+func TestRegisterTransport(t *testing.T) {
+	var ft *fakeTransport
+	RegisterTransport("Fake", func(addr string, params url.Values) (Transport, error) {
+		if addr != "agent/1" || params.Get("key") != "k" {
+			t.Errorf("factory got %q, %v", addr, params)
+		}
+		ft = new(fakeTransport)
+		return ft, nil
+	})
+	p, err := OpenURI("fake://agent/1?key=k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewReceipt("test")
+	r.Text("hello")
+	if err := r.Print(p); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ft.String(), "hello\n") || len(ft.docs) != 2 {
+		t.Errorf("wrote %q in documents %q", ft.String(), ft.docs)
+	}
+}
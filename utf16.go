@@ -0,0 +1,82 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"sync"
+	"syscall"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// appendUTF16 appends s encoded as a NUL-terminated UTF-16 string to dst.
+// Like windows.UTF16FromString it returns syscall.EINVAL if s contains a
+// NUL, rather than passing a truncated string to the spooler.
+func appendUTF16(dst []uint16, s string) ([]uint16, error) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c == 0 {
+			return dst, syscall.EINVAL
+		}
+		if c < utf8.RuneSelf {
+			dst = append(dst, uint16(c))
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r >= 0x10000 {
+			r1, r2 := utf16.EncodeRune(r)
+			dst = append(dst, uint16(r1), uint16(r2))
+			continue
+		}
+		dst = append(dst, uint16(r))
+	}
+	return append(dst, 0), nil
+}
+
+// utf16String is a NUL-terminated UTF-16 string in a pooled buffer, used
+// instead of syscall.StringToUTF16 on hot paths such as Open,
+// StartDocument and text output.
+type utf16String struct {
+	b []uint16
+}
+
+// maxPooledUTF16 is the length above which buffers are not kept for reuse.
+const maxPooledUTF16 = 4096
+
+var utf16Strings = sync.Pool{
+	New: func() interface{} { return &utf16String{b: make([]uint16, 0, 128)} },
+}
+
+// newUTF16 returns s as a NUL-terminated UTF-16 string. It must be
+// released with free once the call it was passed to has returned.
+func newUTF16(s string) (*utf16String, error) {
+	u := utf16Strings.Get().(*utf16String)
+	var err error
+	u.b, err = appendUTF16(u.b[:0], s)
+	if err != nil {
+		u.free()
+		return nil, err
+	}
+	return u, nil
+}
+
+// ptr returns a pointer to the first character of u.
+func (u *utf16String) ptr() *uint16 {
+	return &u.b[0]
+}
+
+// chars returns the characters of u without the terminating NUL.
+func (u *utf16String) chars() []uint16 {
+	return u.b[:len(u.b)-1]
+}
+
+func (u *utf16String) free() {
+	if cap(u.b) > maxPooledUTF16 {
+		return
+	}
+	utf16Strings.Put(u)
+}
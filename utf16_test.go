@@ -0,0 +1,67 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"reflect"
+	"syscall"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestAppendUTF16(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want string
+	}{
+		{"", ""},
+		{"Kitchen", "Kitchen"},
+		{"Büro Drucker", "Büro Drucker"},
+		{"€ 🖨", "€ 🖨"},
+		{"bad \xff byte", "bad � byte"},
+	} {
+		want := append(utf16.Encode([]rune(tt.want)), 0)
+		if got, err := appendUTF16(nil, tt.s); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("appendUTF16(%q) = %v, %v, want %v", tt.s, got, err, want)
+		}
+	}
+	if _, err := appendUTF16(nil, "name\x00rest"); err != syscall.EINVAL {
+		t.Errorf("appendUTF16 with NUL returned %v, want %v", err, syscall.EINVAL)
+	}
+}
+
+func TestNewUTF16(t *testing.T) {
+	u, err := newUTF16("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(utf16.Decode(u.chars())); got != "abc" {
+		t.Errorf("chars = %q, want %q", got, "abc")
+	}
+	if *u.ptr() != 'a' || u.b[len(u.b)-1] != 0 {
+		t.Errorf("buffer = %v, want NUL-terminated", u.b)
+	}
+	u.free()
+	if _, err := newUTF16("a\x00b"); err != syscall.EINVAL {
+		t.Errorf("newUTF16 with NUL returned %v, want %v", err, syscall.EINVAL)
+	}
+}
+
+func BenchmarkNewUTF16(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		u, _ := newUTF16("Kitchen Receipt Printer")
+		u.free()
+	}
+}
+
+// BenchmarkEncodeUTF16 allocates like syscall.StringToUTF16, for
+// comparison with BenchmarkNewUTF16.
+func BenchmarkEncodeUTF16(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = append(utf16.Encode([]rune("Kitchen Receipt Printer")), 0)
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+// DefaultChange reports a change of the default printer.
+type DefaultChange struct {
+	Old, New string
+}
@@ -0,0 +1,75 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// windowsKey holds the "Device" value naming the default printer of the
+// current user.
+const windowsKey = `Software\Microsoft\Windows NT\CurrentVersion\Windows`
+
+// WatchDefault watches the default printer of the current user and sends
+// a DefaultChange on the returned channel each time it changes, until ctx
+// is done. The channel is closed when watching stops.
+func WatchDefault(ctx context.Context) (<-chan DefaultChange, error) {
+	k, err := registry.OpenKey(registry.CURRENT_USER, windowsKey, registry.NOTIFY|registry.QUERY_VALUE)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := windows.CreateEvent(nil, 0, 0, nil)
+	if err != nil {
+		k.Close()
+		return nil, err
+	}
+	stop, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		windows.CloseHandle(changed)
+		k.Close()
+		return nil, err
+	}
+	current, _ := Default()
+	ch := make(chan DefaultChange)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			windows.SetEvent(stop)
+		case <-done:
+		}
+	}()
+	go func() {
+		defer close(ch)
+		defer close(done)
+		defer windows.CloseHandle(stop)
+		defer windows.CloseHandle(changed)
+		defer k.Close()
+		for {
+			err := windows.RegNotifyChangeKeyValue(windows.Handle(k), false, windows.REG_NOTIFY_CHANGE_LAST_SET, changed, true)
+			if err != nil {
+				return
+			}
+			ev, err := windows.WaitForMultipleObjects([]windows.Handle{changed, stop}, false, windows.INFINITE)
+			if err != nil || ev != windows.WAIT_OBJECT_0 {
+				return
+			}
+			name, err := Default()
+			if err != nil || name == current {
+				continue
+			}
+			select {
+			case ch <- DefaultChange{Old: current, New: name}:
+				current = name
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
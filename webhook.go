@@ -0,0 +1,194 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// JobOutcome tells how a print job ended, see NotifyJobs.
+type JobOutcome string
+
+const (
+	// JobCompleted is sent when a job leaves the queue after printing.
+	JobCompleted JobOutcome = "completed"
+	// JobFailed is sent when a job enters an error state, such as the
+	// printer running out of paper. The job may still print once the
+	// error is cleared, in which case JobCompleted follows.
+	JobFailed JobOutcome = "failed"
+	// JobCancelled is sent when a job is deleted before printing.
+	JobCancelled JobOutcome = "cancelled"
+)
+
+// jobErrorStatus are the job status flags reported as JobFailed.
+const jobErrorStatus = JOB_STATUS_ERROR | JOB_STATUS_OFFLINE | JOB_STATUS_PAPEROUT |
+	JOB_STATUS_BLOCKED_DEVQ | JOB_STATUS_USER_INTERVENTION
+
+// jobOutcome returns the outcome of a job reported by e, if any.
+func jobOutcome(e JobEvent) (JobOutcome, bool) {
+	status := e.Job.StatusCode
+	switch {
+	case e.Kind == JobRemoved && status&(JOB_STATUS_PRINTED|JOB_STATUS_COMPLETE) != 0:
+		return JobCompleted, true
+	case e.Kind == JobRemoved && status&(JOB_STATUS_DELETING|JOB_STATUS_DELETED) != 0:
+		return JobCancelled, true
+	case status.IsError():
+		return JobFailed, true
+	case e.Kind == JobRemoved:
+		// jobs often leave the queue between two polls without having
+		// been seen printed
+		return JobCompleted, true
+	}
+	return "", false
+}
+
+// WebhookEvent is the JSON body posted by a Webhook.
+type WebhookEvent struct {
+	Printer string     `json:"printer"`
+	Outcome JobOutcome `json:"outcome"`
+	Job     JobInfo    `json:"job"`
+	Time    time.Time  `json:"time"`
+}
+
+// Webhook posts job outcomes to an HTTP endpoint, see NotifyJobs.
+type Webhook struct {
+	// URL receives the events as JSON encoded WebhookEvents in POST
+	// requests.
+	URL string
+	// Header is added to the requests, typically to set Authorization.
+	Header http.Header
+	// Attempts is the number of times a request is tried before giving
+	// up. Zero means 3.
+	Attempts int
+	// Backoff is the delay before the first retry, doubled on each
+	// retry. Zero means one second.
+	Backoff time.Duration
+	// Client sends the requests. Nil means a client with a 10 second
+	// timeout.
+	Client *http.Client
+	// OnError, if set, is called with the events that could not be
+	// delivered.
+	OnError func(e WebhookEvent, err error)
+}
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// Send posts e to the webhook, retrying on network errors and on 429 and
+// 5xx responses.
+func (w *Webhook) Send(ctx context.Context, e WebhookEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	client := w.Client
+	if client == nil {
+		client = webhookClient
+	}
+	attempts := w.Attempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	for i := 0; ; i++ {
+		var retry bool
+		retry, err = w.post(ctx, client, body)
+		if err == nil || !retry || i+1 == attempts {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// post sends one request, reporting whether it is worth retrying.
+func (w *Webhook) post(ctx context.Context, client *http.Client, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest("POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range w.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("printer: webhook %s: %s", w.URL, resp.Status)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// jobOutcomes tracks the jobs reported failed, so JobFailed is reported
+// once per job.
+type jobOutcomes map[uint32]bool
+
+// next returns the outcome of e to report, if any. A job removed while
+// still in the error state it was reported failed for is reported as
+// JobCancelled.
+func (failed jobOutcomes) next(e JobEvent) (JobOutcome, bool) {
+	outcome, ok := jobOutcome(e)
+	id := e.Job.JobID
+	if e.Kind == JobRemoved {
+		wasFailed := failed[id]
+		delete(failed, id)
+		if outcome == JobFailed && wasFailed {
+			return JobCancelled, true
+		}
+		return outcome, ok
+	}
+	if !ok || outcome == JobFailed && failed[id] {
+		return "", false
+	}
+	if outcome == JobFailed {
+		failed[id] = true
+	}
+	return outcome, true
+}
+
+// NotifyJobs watches the queue of p every interval, see WatchJobs, and
+// sends the outcome of its jobs to hooks until ctx is done. JobFailed is
+// sent once per job, when it first enters an error state, and a failed
+// job removed without printing is reported as JobCancelled. Webhooks are
+// called in the order of the events, one at a time.
+func (p *Printer) NotifyJobs(ctx context.Context, interval time.Duration, hooks ...*Webhook) error {
+	events, err := p.WatchJobs(ctx, interval)
+	if err != nil {
+		return err
+	}
+	failed := make(jobOutcomes)
+	for e := range events {
+		outcome, ok := failed.next(e)
+		if !ok {
+			continue
+		}
+		we := WebhookEvent{Printer: p.name, Outcome: outcome, Job: e.Job, Time: time.Now()}
+		for _, w := range hooks {
+			if err := w.Send(ctx, we); err != nil && w.OnError != nil {
+				w.OnError(we, err)
+			}
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,112 @@
+// Copyright 2013 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJobOutcome(t *testing.T) {
+	tests := []struct {
+		kind    JobEventKind
+		status  JobStatus
+		outcome JobOutcome
+		ok      bool
+	}{
+		{JobAdded, JOB_STATUS_SPOOLING, "", false},
+		{JobChanged, JOB_STATUS_PRINTING, "", false},
+		{JobChanged, JOB_STATUS_PAPEROUT, JobFailed, true},
+		{JobRemoved, JOB_STATUS_PRINTED, JobCompleted, true},
+		{JobRemoved, JOB_STATUS_PRINTING, JobCompleted, true},
+		{JobRemoved, JOB_STATUS_DELETING, JobCancelled, true},
+		{JobRemoved, JOB_STATUS_ERROR, JobFailed, true},
+	}
+	for _, tt := range tests {
+		outcome, ok := jobOutcome(JobEvent{tt.kind, JobInfo{StatusCode: tt.status}})
+		if outcome != tt.outcome || ok != tt.ok {
+			t.Errorf("jobOutcome(%v, %#x) = %q, %v, want %q, %v", tt.kind, tt.status, outcome, ok, tt.outcome, tt.ok)
+		}
+	}
+}
+
+func TestJobOutcomesNext(t *testing.T) {
+	tests := []struct {
+		kind    JobEventKind
+		job     uint32
+		status  JobStatus
+		outcome JobOutcome // empty if none
+	}{
+		{JobAdded, 1, JOB_STATUS_SPOOLING, ""},
+		{JobChanged, 1, JOB_STATUS_PAPEROUT, JobFailed},
+		{JobChanged, 1, JOB_STATUS_PAPEROUT | JOB_STATUS_ERROR, ""},
+		// removed while still failing: given up, not failed again
+		{JobRemoved, 1, JOB_STATUS_PAPEROUT, JobCancelled},
+		// failed again after the error was cleared and printed
+		{JobAdded, 2, JOB_STATUS_SPOOLING, ""},
+		{JobChanged, 2, JOB_STATUS_OFFLINE, JobFailed},
+		{JobChanged, 2, JOB_STATUS_PRINTING, ""},
+		{JobRemoved, 2, JOB_STATUS_PRINTED, JobCompleted},
+		// removed in an error state never reported
+		{JobRemoved, 3, JOB_STATUS_ERROR, JobFailed},
+		// job IDs are reused once the job left the queue
+		{JobChanged, 1, JOB_STATUS_ERROR, JobFailed},
+	}
+	failed := make(jobOutcomes)
+	for i, tt := range tests {
+		outcome, ok := failed.next(JobEvent{tt.kind, JobInfo{JobID: tt.job, StatusCode: tt.status}})
+		if outcome != tt.outcome || ok != (tt.outcome != "") {
+			t.Errorf("event %d: next(%v, job %d, %#x) = %q, %v, want %q", i, tt.kind, tt.job, tt.status, outcome, ok, tt.outcome)
+		}
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	var calls int
+	var got WebhookEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	w := &Webhook{
+		URL:     srv.URL,
+		Header:  http.Header{"Authorization": {"Bearer secret"}},
+		Backoff: time.Millisecond,
+	}
+	e := WebhookEvent{Printer: "Kitchen", Outcome: JobCompleted, Job: JobInfo{JobID: 12}}
+	if err := w.Send(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("%d calls, want 3", calls)
+	}
+	if got.Printer != "Kitchen" || got.Outcome != JobCompleted || got.Job.JobID != 12 {
+		t.Errorf("got %+v", got)
+	}
+
+	calls = -10
+	w.Attempts = 2
+	if err := w.Send(context.Background(), e); err == nil {
+		t.Error("no error after failed attempts")
+	}
+	if calls != -8 {
+		t.Errorf("%d attempts, want 2", calls+10)
+	}
+}